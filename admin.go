@@ -0,0 +1,47 @@
+package main
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// adminPassword is the password the admin routes ask for with HTTP Basic
+// authentication, set by ADMIN_PASSWORD. While it is empty they are refused.
+var adminPassword string
+
+// adminRoutes are the routes that need adminPassword, checked by serveRoute
+// before the handler runs.
+var adminRoutes = map[string]bool{
+	"admin-config":   true,
+	"admin-testconn": true,
+}
+
+// requireAdmin checks the request's Basic credentials against adminPassword,
+// answering 401 (or 403 while ADMIN_PASSWORD is unset) and returning false
+// when they do not match. Any user name is accepted.
+func requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	isAPI := strings.HasPrefix(r.URL.Path, "/api/")
+	if adminPassword == "" {
+		message := "This admin feature is disabled until ADMIN_PASSWORD is set"
+		if isAPI {
+			writeAPIError(w, http.StatusForbidden, apiErrForbidden, message)
+		} else {
+			renderErrorStatus(w, http.StatusForbidden, "Forbidden", message)
+		}
+		return false
+	}
+
+	_, password, ok := r.BasicAuth()
+	if ok && subtle.ConstantTimeCompare([]byte(password), []byte(adminPassword)) == 1 {
+		return true
+	}
+	w.Header().Set("WWW-Authenticate", `Basic realm="RediScan admin", charset="UTF-8"`)
+	message := "This admin feature needs the ADMIN_PASSWORD, sent with HTTP Basic authentication"
+	if isAPI {
+		writeAPIError(w, http.StatusUnauthorized, apiErrUnauthenticated, message)
+	} else {
+		renderErrorStatus(w, http.StatusUnauthorized, "Unauthorized", message)
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequireAdmin(t *testing.T) {
+	prev := adminPassword
+	t.Cleanup(func() { adminPassword = prev })
+	testConnEnabled = true
+	t.Cleanup(func() { testConnEnabled = false })
+	mux := http.NewServeMux()
+	registerRoutes(mux, nil)
+
+	serve := func(password string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/testconn", strings.NewReader(`{"addr": "no-port"}`))
+		req.Header.Set("Content-Type", "application/json")
+		if password != "" {
+			req.SetBasicAuth("admin", password)
+		}
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+
+	adminPassword = ""
+	if rr := serve("anything"); rr.Code != http.StatusForbidden || !strings.Contains(rr.Body.String(), "ADMIN_PASSWORD") {
+		t.Errorf("expected 403 while ADMIN_PASSWORD is unset, got %d %s", rr.Code, rr.Body.String())
+	}
+
+	adminPassword = "s3cret"
+	for _, password := range []string{"", "wrong"} {
+		rr := serve(password)
+		if rr.Code != http.StatusUnauthorized || !strings.Contains(rr.Body.String(), apiErrUnauthenticated) {
+			t.Errorf("expected 401 for password %q, got %d %s", password, rr.Code, rr.Body.String())
+		}
+		if rr.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("expected a Basic challenge for password %q", password)
+		}
+	}
+
+	// The right password reaches the handler, which rejects the address
+	if rr := serve("s3cret"); rr.Code != http.StatusBadRequest {
+		t.Errorf("expected the request to reach the handler, got %d %s", rr.Code, rr.Body.String())
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/its-the-vibe/RediScan/inspector"
+)
+
+func aggregateHandler(w http.ResponseWriter, r *http.Request) {
+	key := r.URL.Query().Get("key")
+	field := r.URL.Query().Get("field")
+	if key == "" {
+		renderNotFound(w, "Missing 'key' parameter")
+		return
+	}
+	if field == "" {
+		renderNotFound(w, "Missing 'field' parameter")
+		return
+	}
+
+	if _, err := inspector.ListLength(ctx, redisClient, key); err != nil {
+		renderListError(w, key, err)
+		return
+	}
+
+	buckets, total, err := inspector.AggregateList(ctx, redisClient, key, field)
+	if err != nil {
+		erroredKeys.record(key, errorKindRedis, err.Error())
+		renderError(w, fmt.Sprintf("Error getting list elements: %v", err))
+		return
+	}
+
+	tmplStr := `<!DOCTYPE html>
+<html>
+<head>
+    <title>RediScan - {{displayKey .Key}} by {{.Field}}</title>
+    <style>
+        body {
+            font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif;
+            max-width: 1200px;
+            margin: 0 auto;
+            padding: 20px;
+            background-color: #f5f5f5;
+        }
+        h1 a {
+            color: #333;
+            text-decoration: none;
+        }
+        h1 a:hover {
+            text-decoration: underline;
+        }
+        .metadata, .aggregation {
+            background-color: white;
+            padding: 15px;
+            border-radius: 5px;
+            margin-bottom: 20px;
+            box-shadow: 0 2px 4px rgba(0,0,0,0.1);
+        }
+        .metadata p {
+            margin: 5px 0;
+        }
+        table {
+            width: 100%;
+            border-collapse: collapse;
+        }
+        th, td {
+            text-align: left;
+            padding: 8px;
+            border-bottom: 1px solid #eee;
+        }
+        td.value {
+            font-family: monospace;
+            word-break: break-word;
+        }
+        td.count {
+            white-space: nowrap;
+        }
+        .bar {
+            background-color: #2196F3;
+            height: 14px;
+            border-radius: 2px;
+        }
+        .back-link {
+            display: inline-block;
+            color: #2196F3;
+            text-decoration: none;
+        }
+        .back-link:hover {
+            text-decoration: underline;
+        }
+    </style>
+    {{customCSSLink}}
+</head>
+<body>
+    {{persistenceBanner}}
+    <h1><a href="/">RediScan - Redis List Inspector</a></h1>
+
+    <div class="metadata">
+        <p><strong>Key:</strong> {{displayKey .Key}}</p>
+        <p><strong>Grouped by:</strong> {{.Field}}</p>
+        <p><strong>Elements:</strong> {{.Total}}</p>
+    </div>
+
+    <div class="aggregation">
+        <table>
+            <tr><th>Value</th><th>Count</th><th style="width: 40%"></th></tr>
+            {{range .Buckets}}
+            <tr>
+                <td class="value">{{.Value}}</td>
+                <td class="count">{{.Count}} ({{printf "%.1f" .Percent}}%)</td>
+                <td><div class="bar" style="width: {{printf "%.1f" .Percent}}%"></div></td>
+            </tr>
+            {{end}}
+        </table>
+    </div>
+
+    <a href="{{lindexPath}}?key={{.Key | urlquery}}" class="back-link">← Back to list</a>
+</body>
+</html>`
+
+	tmpl, err := parseTemplate("aggregate", tmplStr)
+	if err != nil {
+		renderError(w, fmt.Sprintf("Template error: %v", err))
+		return
+	}
+
+	data := struct {
+		Key     string
+		Field   string
+		Total   int64
+		Buckets []inspector.FieldBucket
+	}{
+		Key:     key,
+		Field:   field,
+		Total:   total,
+		Buckets: buckets,
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("Error rendering template: %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/its-the-vibe/RediScan/inspector"
+)
+
+func TestAggregateHandler(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("events",
+		`{"status":"failed"}`,
+		`{"status":"success"}`,
+		`{"status":"success"}`,
+		`plain text`,
+	)
+
+	rr := httptest.NewRecorder()
+	aggregateHandler(rr, httptest.NewRequest(http.MethodGet, "/aggregate?key=events&field=status", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if body := rr.Body.String(); !strings.Contains(body, inspector.NoFieldBucket) || !strings.Contains(body, "50.0%") {
+		t.Errorf("expected buckets with percentages in page, got: %s", body)
+	}
+
+	rr = httptest.NewRecorder()
+	aggregateHandler(rr, httptest.NewRequest(http.MethodGet, "/aggregate?key=events", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for missing field, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,370 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/its-the-vibe/RediScan/inspector"
+)
+
+const (
+	statsScanLimit = 10000 // Maximum keys examined by /api/stats
+	statsTopLists  = 10    // Default number of largest lists reported
+	typesMaxKeys   = 1000  // Maximum keys classified by one /api/types request
+)
+
+// Error codes returned in API error responses. Clients should branch on
+// these rather than on the message, which may change.
+const (
+	apiErrNotFound         = "NOT_FOUND"          // The key does not exist
+	apiErrWrongType        = "WRONG_TYPE"         // The key is not a list
+	apiErrEmptyList        = "EMPTY_LIST"         // The list has no elements
+	apiErrOutOfBounds      = "OUT_OF_BOUNDS"      // The index is outside the list
+	apiErrNoMatch          = "NO_MATCH"           // No element has the field value looked up
+	apiErrInvalidIndex     = "INVALID_INDEX"      // The index parameter is malformed
+	apiErrInvalidParameter = "INVALID_PARAMETER"  // Another parameter is missing or malformed
+	apiErrMethodNotAllowed = "METHOD_NOT_ALLOWED" // The route does not accept the request method
+	apiErrUnauthenticated  = "UNAUTHENTICATED"    // The request has no signed-in user or admin password
+	apiErrForbidden        = "FORBIDDEN"          // The feature is disabled by configuration, or the request is cross-origin
+	apiErrBusy             = "BUSY"               // Too many Redis operations are in progress; retry later
+	apiErrTooLarge         = "TOO_LARGE"          // The request body exceeds MAX_REQUEST_BODY_BYTES
+	apiErrInternal         = "INTERNAL"           // Redis or the server failed
+)
+
+// apiError is the body of every API error response:
+// {"error": {"code": "NOT_FOUND", "message": "..."}}.
+type apiError struct {
+	Error apiErrorDetail `json:"error"`
+}
+
+type apiErrorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// writeAPIError writes an API error response with a stable code.
+func writeAPIError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, apiError{Error: apiErrorDetail{Code: code, Message: message}})
+}
+
+// writeAPIListError is renderListError for API responses.
+func writeAPIListError(w http.ResponseWriter, key string, err error) {
+	var wrongType *inspector.WrongTypeError
+	switch {
+	case errors.Is(err, inspector.ErrKeyNotFound):
+		writeAPIError(w, http.StatusNotFound, apiErrNotFound, fmt.Sprintf("key '%s' does not exist", displayKey(key)))
+	case errors.As(err, &wrongType):
+		if wrongType.Type != "stream" {
+			erroredKeys.record(key, errorKindWrongType, fmt.Sprintf("expected list, found %s", wrongType.Type))
+		}
+		writeAPIError(w, http.StatusConflict, apiErrWrongType, fmt.Sprintf("key '%s' is not a list (type: %s)", displayKey(key), wrongType.Type))
+	case errors.Is(err, inspector.ErrEmptyList):
+		writeAPIError(w, http.StatusNotFound, apiErrEmptyList, fmt.Sprintf("list '%s' is empty", displayKey(key)))
+	default:
+		erroredKeys.record(key, errorKindRedis, err.Error())
+		writeAPIError(w, http.StatusInternalServerError, apiErrInternal, err.Error())
+	}
+}
+
+// apiAllowMethods is allowMethods for API routes, replying with a JSON error.
+func apiAllowMethods(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, m := range methods {
+		if r.Method == m {
+			return true
+		}
+	}
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeAPIError(w, http.StatusMethodNotAllowed, apiErrMethodNotAllowed, fmt.Sprintf("%s is not allowed; use %s", r.Method, strings.Join(methods, " or ")))
+	return false
+}
+
+// writeJSON encodes v as the JSON response body with the given status.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Error encoding JSON response: %v", err)
+	}
+}
+
+// listSummary describes a discovered list for the index page. Key names may
+// contain arbitrary bytes that JSON cannot carry faithfully, so the display
+// form and the URL-encoded form are sent alongside the name.
+type listSummary struct {
+	Name    string `json:"name"`
+	Display string `json:"display"`
+	Query   string `json:"query"`
+	Size    int64  `json:"size"`
+	Preview string `json:"preview,omitempty"`
+	Binary  bool   `json:"binary,omitempty"` // The newest element is not valid UTF-8
+	TTL     int64  `json:"ttl,omitempty"`    // Seconds until the key expires, omitted when it has no expiry
+}
+
+// listsAPIHandler returns the available lists as JSON for the index page to
+// load asynchronously.
+func listsAPIHandler(w http.ResponseWriter, r *http.Request) {
+	scan, err := getAvailableLists()
+	if err != nil {
+		log.Printf("Error fetching available lists: %v", err)
+		writeAPIError(w, http.StatusInternalServerError, apiErrInternal, err.Error())
+		return
+	}
+	lists := scan.Lists
+
+	summaries := summarizeLists(lists)
+
+	// Volatile keys are flagged so users know a link may not last
+	keys := make([]string, len(lists))
+	for i, list := range lists {
+		keys[i] = list.Name
+	}
+	ttls, err := inspector.TTLs(ctx, redisClient, keys)
+	if err != nil {
+		log.Printf("Error reading list TTLs: %v", err)
+	}
+	for i, ttl := range ttls {
+		summaries[i].TTL = int64((ttl + time.Second - 1) / time.Second)
+	}
+
+	if previewLength > 0 {
+		// Previews are a nicety, so a failure here still returns the lists
+		peeks, err := inspector.PeekLists(ctx, redisClient, lists, newestIndex)
+		if err != nil {
+			log.Printf("Error reading list previews: %v", err)
+		}
+		for i, peek := range peeks {
+			summaries[i].Preview = elementPreview(peek.Key, peek.Value, previewLength)
+			summaries[i].Binary = !utf8.ValidString(peek.Value)
+		}
+	}
+	body := map[string]interface{}{
+		"lists":     summaries,
+		"pattern":   "*",
+		"scanned":   scan.Scanned,
+		"truncated": !scan.Complete,
+	}
+	addFailedShards(body, scan.FailedShards)
+	writeJSON(w, http.StatusOK, body)
+}
+
+// shardFailure is the JSON form of a Redis Cluster shard a scan skipped.
+type shardFailure struct {
+	Addr  string `json:"addr"`
+	Error string `json:"error"`
+}
+
+// addFailedShards adds the shards a scan could not cover to a response as
+// failed_shards, leaving it out when every shard was scanned.
+func addFailedShards(body map[string]interface{}, failed []inspector.ShardError) {
+	if len(failed) == 0 {
+		return
+	}
+	shards := make([]shardFailure, len(failed))
+	for i, shard := range failed {
+		shards[i] = shardFailure{Addr: shard.Addr, Error: shard.Err.Error()}
+	}
+	body["failed_shards"] = shards
+}
+
+// summarizeLists converts lists to their JSON form.
+func summarizeLists(lists []inspector.ListInfo) []listSummary {
+	summaries := make([]listSummary, len(lists))
+	for i, list := range lists {
+		summaries[i] = listSummary{
+			Name:    list.Name,
+			Display: displayKey(list.Name),
+			Query:   url.QueryEscape(list.Name),
+			Size:    list.Size,
+		}
+	}
+	return summaries
+}
+
+// keySummary describes a discovered key of any type for the grouped index
+// page, carrying the display and URL-encoded forms as listSummary does.
+type keySummary struct {
+	Name    string `json:"name"`
+	Display string `json:"display"`
+	Query   string `json:"query"`
+	Type    string `json:"type"`
+}
+
+// keysAPIHandler returns up to MAX_LISTS keys of every type, for the index
+// page's grouped view.
+func keysAPIHandler(w http.ResponseWriter, r *http.Request) {
+	scan, err := inspector.ScanKeyspace(ctx, redisClient, "*", maxLists)
+	if err != nil {
+		log.Printf("Error fetching keys: %v", err)
+		writeAPIError(w, http.StatusInternalServerError, apiErrInternal, err.Error())
+		return
+	}
+
+	summaries := make([]keySummary, len(scan.Keys))
+	for i, key := range scan.Keys {
+		summaries[i] = keySummary{
+			Name:    key.Name,
+			Display: displayKey(key.Name),
+			Query:   url.QueryEscape(key.Name),
+			Type:    key.Type,
+		}
+	}
+	body := map[string]interface{}{"keys": summaries}
+	addFailedShards(body, scan.FailedShards)
+	writeJSON(w, http.StatusOK, body)
+}
+
+// statsAPIHandler returns counts of keys per type and the largest lists from
+// a bounded scan of the keyspace. The optional top parameter sets how many
+// lists are reported.
+func statsAPIHandler(w http.ResponseWriter, r *http.Request) {
+	top := statsTopLists
+	if topStr := r.URL.Query().Get("top"); topStr != "" {
+		n, err := strconv.Atoi(topStr)
+		if err != nil || n < 0 {
+			writeAPIError(w, http.StatusBadRequest, apiErrInvalidParameter, "top must be a non-negative integer")
+			return
+		}
+		top = n
+	}
+
+	stats, err := inspector.Stats(ctx, redisClient, statsScanLimit, top)
+	if err != nil {
+		log.Printf("Error gathering keyspace stats: %v", err)
+		writeAPIError(w, http.StatusInternalServerError, apiErrInternal, err.Error())
+		return
+	}
+
+	body := map[string]interface{}{
+		"dbsize":        stats.DBSize,
+		"scanned":       stats.Scanned,
+		"complete":      stats.Complete,
+		"types":         stats.Types,
+		"largest_lists": summarizeLists(stats.LargestLists),
+	}
+	addFailedShards(body, stats.FailedShards)
+	writeJSON(w, http.StatusOK, body)
+}
+
+// llenAPIHandler reports the length of one list without reading any of its
+// elements (just TYPE and LLEN), for cheap polling by scripts and pages.
+func llenAPIHandler(w http.ResponseWriter, r *http.Request) {
+	key := r.URL.Query().Get("key")
+	if key == "" {
+		writeAPIError(w, http.StatusBadRequest, apiErrInvalidParameter, "missing 'key' parameter")
+		return
+	}
+	llen, err := inspector.ListLength(ctx, redisClient, key)
+	if err != nil {
+		writeAPIListError(w, key, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"key":  key,
+		"llen": llen,
+	})
+}
+
+// typesAPIHandler returns the type of each key listed in the POST body,
+// separated by commas or newlines, for tooling that routes keys to the right
+// inspector. Keys are URL-encoded, as in the query field of /api/keys, so
+// any key can be sent, and each type is reported under the key as sent. The
+// types come from a single pipeline of TYPE calls, so the number of keys per
+// request is capped.
+func typesAPIHandler(w http.ResponseWriter, r *http.Request) {
+	if !refuseCrossOrigin(w, r) {
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, apiErrInvalidParameter, fmt.Sprintf("reading request body: %v", err))
+		return
+	}
+	var fields, keys []string
+	seen := make(map[string]bool)
+	for _, field := range strings.FieldsFunc(string(body), func(c rune) bool { return c == ',' || c == '\n' || c == '\r' }) {
+		if seen[field] {
+			continue
+		}
+		key, err := url.QueryUnescape(field)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, apiErrInvalidParameter, fmt.Sprintf("invalid key %q: expected it URL-encoded, as in the query field of /api/keys", field))
+			return
+		}
+		seen[field] = true
+		fields = append(fields, field)
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		writeAPIError(w, http.StatusBadRequest, apiErrInvalidParameter, "the request body must list at least one key")
+		return
+	}
+	if len(keys) > typesMaxKeys {
+		writeAPIError(w, http.StatusBadRequest, apiErrInvalidParameter, fmt.Sprintf("at most %d keys may be classified at once (got %d)", typesMaxKeys, len(keys)))
+		return
+	}
+
+	types, err := inspector.KeyTypes(ctx, redisClient, keys)
+	if err != nil {
+		log.Printf("Error reading key types: %v", err)
+		writeAPIError(w, http.StatusInternalServerError, apiErrInternal, err.Error())
+		return
+	}
+	result := make(map[string]string, len(keys))
+	for i, field := range fields {
+		result[field] = types[i]
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"types": result})
+}
+
+// readAPIElement reads the raw element named by the key and index query
+// parameters for API handlers, writing an API error and returning false when
+// it cannot. A negative index counts back from the tail.
+func readAPIElement(w http.ResponseWriter, query url.Values) (key string, index int64, value string, ok bool) {
+	key = query.Get("key")
+	if key == "" {
+		writeAPIError(w, http.StatusBadRequest, apiErrInvalidParameter, "missing 'key' parameter")
+		return "", 0, "", false
+	}
+	index, err := strconv.ParseInt(query.Get("index"), 10, 64)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, apiErrInvalidIndex, "invalid 'index' parameter: expected an integer")
+		return "", 0, "", false
+	}
+
+	llen, err := inspector.ListLength(ctx, redisClient, key)
+	if err != nil {
+		writeAPIListError(w, key, err)
+		return "", 0, "", false
+	}
+	if index < 0 {
+		index += llen
+	}
+	if index < 0 || index >= llen {
+		writeAPIError(w, http.StatusNotFound, apiErrOutOfBounds, fmt.Sprintf("index %d out of bounds (list length: %d)", index, llen))
+		return "", 0, "", false
+	}
+
+	peeks, err := inspector.PeekIndices(ctx, redisClient, key, llen, []int64{index})
+	if err != nil {
+		log.Printf("Error reading %q[%d]: %v", key, index, err)
+		writeAPIError(w, http.StatusInternalServerError, apiErrInternal, err.Error())
+		return "", 0, "", false
+	}
+	if !peeks[0].Found {
+		// The list shrank since its length was read
+		writeAPIError(w, http.StatusNotFound, apiErrOutOfBounds, fmt.Sprintf("index %d is no longer in the list", index))
+		return "", 0, "", false
+	}
+
+	return key, index, peeks[0].Value, true
+}
@@ -0,0 +1,325 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/its-the-vibe/RediScan/inspector"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestIndexHandler_RendersWithoutScanning(t *testing.T) {
+	prev := redisClient
+	redisClient = redis.NewClient(&redis.Options{Addr: "127.0.0.1:1"})
+	defer func() {
+		redisClient.Close()
+		redisClient = prev
+	}()
+
+	rr := httptest.NewRecorder()
+	indexHandler(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), `class="spinner"`) {
+		t.Errorf("expected loading spinner on index page")
+	}
+	if !strings.Contains(rr.Body.String(), "navigator.clipboard.writeText(name)") {
+		t.Errorf("expected copy-key-name control on index page")
+	}
+	if !strings.Contains(rr.Body.String(), `list="keySuggestions"`) {
+		t.Errorf("expected key suggestions on the key entry form")
+	}
+}
+
+func TestListsAPIHandler(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("jobs", "a", "b")
+
+	rr := httptest.NewRecorder()
+	listsAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/lists", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected JSON content type, got %q", ct)
+	}
+	if body := rr.Body.String(); !strings.Contains(body, `"name":"jobs"`) || !strings.Contains(body, `"size":2`) {
+		t.Errorf("unexpected response body: %s", body)
+	}
+	if strings.Contains(rr.Body.String(), "failed_shards") {
+		t.Errorf("expected no failed shards for a single server, got: %s", rr.Body.String())
+	}
+}
+
+func TestAddFailedShards(t *testing.T) {
+	body := map[string]interface{}{}
+	addFailedShards(body, []inspector.ShardError{{Addr: "10.0.0.2:6379", Err: errors.New("connection refused")}})
+	want := []shardFailure{{Addr: "10.0.0.2:6379", Error: "connection refused"}}
+	if !reflect.DeepEqual(body["failed_shards"], want) {
+		t.Errorf("expected %+v, got %+v", want, body["failed_shards"])
+	}
+}
+
+func TestListsAPIHandler_Truncated(t *testing.T) {
+	mr := useMiniredis(t)
+	prev := maxLists
+	defer func() { maxLists = prev }()
+	mr.RPush("jobs", "a")
+	mr.RPush("logs", "a")
+
+	maxLists = 1
+	rr := httptest.NewRecorder()
+	listsAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/lists", nil))
+	if body := rr.Body.String(); !strings.Contains(body, `"truncated":true`) || !strings.Contains(body, `"pattern":"*"`) {
+		t.Errorf("expected the scan to be reported as truncated at MAX_LISTS, got: %s", body)
+	}
+
+	maxLists = 10
+	rr = httptest.NewRecorder()
+	listsAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/lists", nil))
+	if body := rr.Body.String(); !strings.Contains(body, `"truncated":false`) || !strings.Contains(body, `"scanned":2`) {
+		t.Errorf("expected a complete scan of 2 keys, got: %s", body)
+	}
+}
+
+func TestListsAPIHandler_RedisError(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.Close()
+
+	rr := httptest.NewRecorder()
+	listsAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/lists", nil))
+	if rr.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d", rr.Code)
+	}
+	var resp apiError
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Error.Code != apiErrInternal || resp.Error.Message == "" {
+		t.Errorf("expected an INTERNAL error with a message, got: %s", rr.Body.String())
+	}
+}
+
+func TestStatsAPIHandler(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("jobs", "a", "b")
+	mr.RPush("logs", "a")
+	mr.Set("plain", "v")
+
+	rr := httptest.NewRecorder()
+	statsAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/stats?top=1", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var resp struct {
+		DBSize       int64            `json:"dbsize"`
+		Complete     bool             `json:"complete"`
+		Types        map[string]int64 `json:"types"`
+		LargestLists []listSummary    `json:"largest_lists"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.DBSize != 3 || !resp.Complete || resp.Types["list"] != 2 || resp.Types["string"] != 1 {
+		t.Errorf("unexpected stats: %+v", resp)
+	}
+	if len(resp.LargestLists) != 1 || resp.LargestLists[0].Name != "jobs" {
+		t.Errorf("expected only the largest list, got %+v", resp.LargestLists)
+	}
+
+	rr = httptest.NewRecorder()
+	statsAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/stats?top=x", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for invalid top, got %d", rr.Code)
+	}
+}
+
+func TestLlenAPIHandler(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("jobs", "a", "b", "c")
+	mr.Set("plain", "v")
+
+	rr := httptest.NewRecorder()
+	llenAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/llen?key=jobs", nil))
+	if rr.Code != http.StatusOK || strings.TrimSpace(rr.Body.String()) != `{"key":"jobs","llen":3}` {
+		t.Errorf("expected the list length, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	tests := []struct {
+		query  string
+		status int
+		code   string
+	}{
+		{"", http.StatusBadRequest, apiErrInvalidParameter},
+		{"key=missing", http.StatusNotFound, apiErrNotFound},
+		{"key=plain", http.StatusConflict, apiErrWrongType},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		llenAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/llen?"+tt.query, nil))
+		if rr.Code != tt.status || !strings.Contains(rr.Body.String(), tt.code) {
+			t.Errorf("%q: expected %d %s, got %d: %s", tt.query, tt.status, tt.code, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestTypesAPIHandler(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("jobs", "a")
+	mr.HSet("user:1", "name", "x")
+	mr.Set("plain", "v")
+
+	rr := httptest.NewRecorder()
+	mr.RPush(" odd\xff%key ", "a")
+	odd := url.QueryEscape(" odd\xff%key ")
+	typesAPIHandler(rr, httptest.NewRequest(http.MethodPost, "/api/types", strings.NewReader("jobs,user%3A1\nplain\r\nmissing,,jobs, plain\n"+odd)))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var body struct {
+		Types map[string]string `json:"types"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	// Names are not trimmed, so " plain" is another key
+	want := map[string]string{"jobs": "list", "user%3A1": "hash", "plain": "string", "missing": "none", " plain": "none", odd: "list"}
+	if !reflect.DeepEqual(body.Types, want) {
+		t.Errorf("expected %v, got %v", want, body.Types)
+	}
+
+	var tooMany strings.Builder
+	for i := 0; i <= typesMaxKeys; i++ {
+		fmt.Fprintf(&tooMany, "key:%d\n", i)
+	}
+	for _, input := range []string{"", ",\r\n", "100%", tooMany.String()} {
+		rr := httptest.NewRecorder()
+		typesAPIHandler(rr, httptest.NewRequest(http.MethodPost, "/api/types", strings.NewReader(input)))
+		if rr.Code != http.StatusBadRequest || !strings.Contains(rr.Body.String(), apiErrInvalidParameter) {
+			t.Errorf("expected %s for %d bytes of keys, got %d: %s", apiErrInvalidParameter, len(input), rr.Code, rr.Body.String())
+		}
+	}
+
+	// Another site cannot make a visitor's browser classify keys
+	req := httptest.NewRequest(http.MethodPost, "/api/types", strings.NewReader("jobs"))
+	req.Header.Set("Sec-Fetch-Site", "cross-site")
+	rr = httptest.NewRecorder()
+	typesAPIHandler(rr, req)
+	if rr.Code != http.StatusForbidden || !strings.Contains(rr.Body.String(), apiErrForbidden) {
+		t.Errorf("expected a cross-site request to be refused, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestListsAPIHandler_Preview(t *testing.T) {
+	mr := useMiniredis(t)
+	prev := previewLength
+	defer func() { previewLength = prev }()
+	previewLength = 5
+	mr.RPush("jobs", "oldest", "héllo wörld")
+
+	rr := httptest.NewRecorder()
+	listsAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/lists", nil))
+	var resp struct {
+		Lists []listSummary `json:"lists"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Lists) != 1 || resp.Lists[0].Preview != "héllo…" || resp.Lists[0].Binary {
+		t.Errorf("expected truncated preview of the newest element, got %+v", resp.Lists)
+	}
+
+	mr.RPush("images", "\x89PNG\r\n\x1a\n\xff")
+	rr = httptest.NewRecorder()
+	listsAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/lists", nil))
+	if !strings.Contains(rr.Body.String(), `"binary":true`) {
+		t.Errorf("expected a list with a binary newest element to be flagged, got: %s", rr.Body.String())
+	}
+
+	previewLength = 0
+	rr = httptest.NewRecorder()
+	listsAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/lists", nil))
+	if strings.Contains(rr.Body.String(), `"preview"`) {
+		t.Errorf("expected no preview when PREVIEW_LENGTH is 0, got: %s", rr.Body.String())
+	}
+}
+
+func TestGetAvailableLists_EmptyReused(t *testing.T) {
+	mr := useMiniredis(t)
+
+	scan, err := getAvailableLists()
+	if err != nil || len(scan.Lists) != 0 || !scan.Complete {
+		t.Fatalf("expected an empty complete scan, got %+v (%v)", scan, err)
+	}
+
+	// Within emptyScanTTL the empty result is reused without touching Redis
+	mr.RPush("jobs", "a")
+	if scan, _ := getAvailableLists(); len(scan.Lists) != 0 {
+		t.Errorf("expected the empty result to be reused, got %+v", scan.Lists)
+	}
+
+	// Until RediScan creates a list itself
+	forgetEmptyScan()
+	if scan, _ := getAvailableLists(); len(scan.Lists) != 1 {
+		t.Errorf("expected a fresh scan to find the new list, got %+v", scan.Lists)
+	}
+}
+
+func TestListsAPIHandler_TTL(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("volatile", "a")
+	mr.SetTTL("volatile", 90*time.Second)
+
+	rr := httptest.NewRecorder()
+	listsAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/lists", nil))
+	if !strings.Contains(rr.Body.String(), `"ttl":90`) {
+		t.Errorf("expected the observed TTL in the response, got: %s", rr.Body.String())
+	}
+}
+
+func TestAPIHandlers_MethodNotAllowed(t *testing.T) {
+	mux := http.NewServeMux()
+	registerRoutes(mux, nil)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/lists", nil))
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected status 405, got %d", rr.Code)
+	}
+	if body := rr.Body.String(); !strings.Contains(body, `{"error":{"code":"METHOD_NOT_ALLOWED","message":`) {
+		t.Errorf("expected a JSON error body, got: %s", body)
+	}
+}
+
+func TestKeysAPIHandler(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("jobs", "a")
+	mr.HSet("user:1", "name", "Ada")
+
+	rr := httptest.NewRecorder()
+	keysAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/keys", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var body struct {
+		Keys []keySummary `json:"keys"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	types := make(map[string]string)
+	for _, k := range body.Keys {
+		types[k.Name] = k.Type
+	}
+	if types["jobs"] != "list" || types["user:1"] != "hash" || len(types) != 2 {
+		t.Errorf("unexpected keys %+v", body.Keys)
+	}
+}
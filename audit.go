@@ -0,0 +1,188 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+const (
+	// auditKey is the reserved Redis list the audit trail is kept in, newest
+	// entry first.
+	auditKey = "rediscan:audit"
+	// auditPageEntries is how many recent accesses /admin/audit shows.
+	auditPageEntries = 100
+)
+
+var (
+	auditRedis      bool         // Whether AUDIT_REDIS keeps the audit trail in Redis as well as the log
+	auditMaxEntries int64 = 1000 // Entries kept in the Redis audit trail (AUDIT_MAX_ENTRIES)
+)
+
+// auditEntry records that a signed-in user viewed an element of a list.
+type auditEntry struct {
+	Time  time.Time `json:"time"`
+	User  string    `json:"user"`
+	Key   string    `json:"key"`
+	Index int64     `json:"index"`
+}
+
+// recordAccess writes an audit entry for each index of key shown to the user
+// the authenticating proxy signed in. Requests without a user (no proxy in
+// front of RediScan) are not audited. Failing to write to Redis is logged
+// but does not fail the request.
+func recordAccess(r *http.Request, key string, indices ...int64) {
+	user := prefsUser(r)
+	if user == "" {
+		return
+	}
+
+	now := time.Now().UTC()
+	entries := make([]interface{}, 0, len(indices))
+	for _, index := range indices {
+		entry := auditEntry{Time: now, User: user, Key: key, Index: index}
+		log.Printf("audit: time=%s user=%q key=%q index=%d", now.Format(time.RFC3339), user, displayKey(key), index)
+		if encoded, err := json.Marshal(entry); err == nil {
+			entries = append(entries, encoded)
+		}
+	}
+	if !auditRedis || len(entries) == 0 {
+		return
+	}
+
+	pipe := redisClient.TxPipeline()
+	pipe.LPush(ctx, auditKey, entries...)
+	pipe.LTrim(ctx, auditKey, 0, auditMaxEntries-1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("Error writing audit entry to %s: %v", auditKey, err)
+	}
+}
+
+// recentAccesses returns up to n audit entries from Redis, newest first.
+// Entries that cannot be decoded are skipped.
+func recentAccesses(n int64) ([]auditEntry, error) {
+	values, err := redisClient.LRange(ctx, auditKey, 0, n-1).Result()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]auditEntry, 0, len(values))
+	for _, value := range values {
+		var entry auditEntry
+		if json.Unmarshal([]byte(value), &entry) == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func auditHandler(w http.ResponseWriter, r *http.Request) {
+	var entries []auditEntry
+	if auditRedis {
+		var err error
+		entries, err = recentAccesses(auditPageEntries)
+		if err != nil {
+			renderError(w, fmt.Sprintf("Error reading audit trail: %v", err))
+			return
+		}
+	}
+
+	tmplStr := `<!DOCTYPE html>
+<html>
+<head>
+    <title>Audit Trail - RediScan</title>
+    <style>
+        body {
+            font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif;
+            max-width: 1200px;
+            margin: 0 auto;
+            padding: 20px;
+            background-color: #f5f5f5;
+        }
+        h1 a {
+            color: #333;
+            text-decoration: none;
+        }
+        h1 a:hover {
+            text-decoration: underline;
+        }
+        .report {
+            background-color: white;
+            padding: 20px;
+            border-radius: 5px;
+            box-shadow: 0 2px 4px rgba(0,0,0,0.1);
+        }
+        .report h2 {
+            margin-top: 0;
+            color: #333;
+        }
+        table {
+            width: 100%;
+            border-collapse: collapse;
+        }
+        th, td {
+            text-align: left;
+            padding: 8px;
+            border-bottom: 1px solid #eee;
+            vertical-align: top;
+        }
+        td a {
+            color: #2196F3;
+            text-decoration: none;
+        }
+        td a:hover {
+            text-decoration: underline;
+        }
+        .empty {
+            color: #666;
+            font-style: italic;
+        }
+    </style>
+    {{customCSSLink}}
+</head>
+<body>
+    {{persistenceBanner}}
+    <h1><a href="/">RediScan - Redis List Inspector</a></h1>
+    <div class="report">
+        <h2>Recent Accesses</h2>
+        {{if not .Enabled}}
+        <p class="empty">The audit trail is only written to the log. Set AUDIT_REDIS=true to also keep it in Redis and list it here.</p>
+        {{else if .Entries}}
+        <table>
+            <tr><th>Time</th><th>User</th><th>Key</th><th>Index</th></tr>
+            {{range .Entries}}
+            <tr>
+                <td>{{formatTime .Time}}</td>
+                <td>{{.User}}</td>
+                <td><a href="{{lindexPath}}?key={{.Key | urlquery}}">{{displayKey .Key}}</a></td>
+                <td><a href="{{lindexPath}}?key={{.Key | urlquery}}&index={{.Index}}">{{.Index}}</a></td>
+            </tr>
+            {{end}}
+        </table>
+        {{else}}
+        <p class="empty">No accesses recorded.</p>
+        {{end}}
+    </div>
+</body>
+</html>`
+
+	tmpl, err := parseTemplate("audit", tmplStr)
+	if err != nil {
+		renderError(w, fmt.Sprintf("Template error: %v", err))
+		return
+	}
+
+	data := struct {
+		Enabled bool
+		Entries []auditEntry
+	}{
+		Enabled: auditRedis,
+		Entries: entries,
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("Error rendering template: %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRecordAccess_Lindex(t *testing.T) {
+	mr := useMiniredis(t)
+	prev := auditRedis
+	defer func() { auditRedis = prev }()
+	auditRedis = true
+	mr.RPush("jobs", "a", "b", "c")
+
+	// Anonymous requests are not audited
+	lindexHandler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/lindex?key=jobs&index=1", nil))
+	if mr.Exists(auditKey) {
+		t.Fatal("expected no audit entry without a signed-in user")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/lindex?key=jobs&index=1", nil)
+	req.Header.Set(prefsUserHeader, "ada")
+	lindexHandler(httptest.NewRecorder(), req)
+
+	// Failed lookups are not audited either
+	req = httptest.NewRequest(http.MethodGet, "/lindex?key=jobs&index=9", nil)
+	req.Header.Set(prefsUserHeader, "ada")
+	lindexHandler(httptest.NewRecorder(), req)
+
+	entries, err := recentAccesses(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].User != "ada" || entries[0].Key != "jobs" || entries[0].Index != 1 {
+		t.Fatalf("expected one entry for ada viewing jobs[1], got %+v", entries)
+	}
+
+	rr := httptest.NewRecorder()
+	auditHandler(rr, httptest.NewRequest(http.MethodGet, "/admin/audit", nil))
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "ada") {
+		t.Errorf("expected the audit page to list the access, got %d", rr.Code)
+	}
+}
+
+func TestRecordAccess_Trimmed(t *testing.T) {
+	mr := useMiniredis(t)
+	prevRedis, prevMax := auditRedis, auditMaxEntries
+	defer func() { auditRedis, auditMaxEntries = prevRedis, prevMax }()
+	auditRedis, auditMaxEntries = true, 2
+
+	req := httptest.NewRequest(http.MethodGet, "/lindex?key=jobs&index=0,1,2", nil)
+	req.Header.Set(prefsUserHeader, "ada")
+	recordAccess(req, "jobs", 0, 1, 2)
+
+	entries, _ := recentAccesses(10)
+	if len(entries) != 2 || entries[0].Index != 2 {
+		t.Errorf("expected the newest two entries to be kept, got %+v", entries)
+	}
+	if list, _ := mr.List(auditKey); len(list) != 2 {
+		t.Errorf("expected the Redis list to be trimmed to 2, got %d", len(list))
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// staticMaxAge is how long browsers may cache static assets such as the
+// custom stylesheet, configured by STATIC_CACHE_MAX_AGE.
+var staticMaxAge = time.Hour
+
+// noStoreHandler marks every response as not cacheable unless the handler
+// sets its own Cache-Control. List data changes constantly, and a cached page
+// or API response would show stale elements after navigating back.
+func noStoreHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		next.ServeHTTP(w, r)
+	})
+}
+
+// immutableMaxAge is how long browsers may cache assets whose URL changes
+// with their contents.
+const immutableMaxAge = 365 * 24 * time.Hour
+
+// setStaticCacheHeaders lets browsers cache a static asset for staticMaxAge.
+// Assets are also served with an ETag, so an expired copy is revalidated
+// cheaply rather than downloaded again.
+func setStaticCacheHeaders(w http.ResponseWriter) {
+	if staticMaxAge <= 0 {
+		w.Header().Set("Cache-Control", "no-cache")
+		return
+	}
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(staticMaxAge/time.Second)))
+}
+
+// setImmutableCacheHeaders lets browsers keep an asset served under a URL
+// carrying a hash of its contents without ever revalidating it: new contents
+// get a new URL. STATIC_CACHE_MAX_AGE does not apply.
+func setImmutableCacheHeaders(w http.ResponseWriter) {
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(immutableMaxAge/time.Second))+", immutable")
+}
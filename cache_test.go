@@ -0,0 +1,55 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNoStoreHandler(t *testing.T) {
+	handler := noStoreHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=jobs", nil))
+	if got := rr.Header().Get("Cache-Control"); got != "no-store" {
+		t.Errorf("expected dynamic responses to be no-store, got %q", got)
+	}
+}
+
+func TestCustomCSS_Cacheable(t *testing.T) {
+	prev := customCSS
+	defer func() { customCSS = prev }()
+	path := filepath.Join(t.TempDir(), "theme.css")
+	if err := os.WriteFile(path, []byte("body { color: red; }"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	loadCustomCSS(path)
+
+	// The asset's own headers win over the no-store default
+	handler := noStoreHandler(http.HandlerFunc(customCSSHandler))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/custom.css", nil))
+	if got := rr.Header().Get("Cache-Control"); got != "public, max-age=3600" {
+		t.Errorf("expected the stylesheet to be cacheable, got %q", got)
+	}
+	etag := rr.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("expected an ETag")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/custom.css", nil)
+	req.Header.Set("If-None-Match", etag)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNotModified {
+		t.Errorf("expected 304 for a matching ETag, got %d", rr.Code)
+	}
+
+	// Pages link to the current version, which never needs revalidating
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/custom.css?v="+customCSSVersion, nil))
+	if got := rr.Header().Get("Cache-Control"); got != "public, max-age=31536000, immutable" {
+		t.Errorf("expected the versioned stylesheet to be immutable, got %q", got)
+	}
+}
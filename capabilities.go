@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/its-the-vibe/RediScan/inspector"
+	"github.com/redis/go-redis/v9"
+)
+
+// redisCapabilities are the introspection commands the connected Redis
+// accepts, probed once at startup. Until then, or if Redis was unreachable,
+// every command is assumed available and failures are handled per request.
+var redisCapabilities = inspector.AllCapabilities()
+
+// probeCapabilities detects which introspection commands Redis accepts, so
+// pages leave out what the server will not report instead of failing.
+func probeCapabilities() {
+	redisCapabilities = inspector.ProbeCapabilities(ctx, redisClient)
+	var missing []string
+	for _, c := range []struct {
+		command   string
+		available bool
+	}{
+		{"OBJECT IDLETIME", redisCapabilities.ObjectIdleTime},
+		{"OBJECT ENCODING", redisCapabilities.ObjectEncoding},
+		{"MEMORY USAGE", redisCapabilities.MemoryUsage},
+		{"CONFIG GET", redisCapabilities.ConfigGet},
+	} {
+		if !c.available {
+			missing = append(missing, c.command)
+		}
+	}
+	if len(missing) > 0 {
+		log.Printf("Redis refuses %s; the features that use them are hidden", strings.Join(missing, ", "))
+	}
+}
+
+// describeEncoding returns the key's internal encoding (listpack, quicklist,
+// ...) from OBJECT ENCODING, or "" when the server does not report it.
+func describeEncoding(ctx context.Context, client redis.UniversalClient, key string) string {
+	if !redisCapabilities.ObjectEncoding {
+		return ""
+	}
+	encoding, err := client.ObjectEncoding(ctx, key).Result()
+	if err != nil {
+		debugf("Reading encoding of %q: %v", key, err)
+		return ""
+	}
+	return encoding
+}
+
+// describeMemory returns the key's approximate memory footprint from MEMORY
+// USAGE, or "" when the server does not report it.
+func describeMemory(ctx context.Context, client redis.UniversalClient, key string) string {
+	if !redisCapabilities.MemoryUsage {
+		return ""
+	}
+	size, err := client.MemoryUsage(ctx, key).Result()
+	if err != nil {
+		debugf("Reading memory usage of %q: %v", key, err)
+		return ""
+	}
+	return formatSize(size)
+}
+
+// formatSize renders a byte count with a binary unit, e.g. 1.5 KiB.
+func formatSize(n int64) string {
+	if n < 1024 {
+		return fmt.Sprintf("%d bytes", n)
+	}
+	value := float64(n) / 1024
+	for _, unit := range []string{"KiB", "MiB", "GiB"} {
+		if value < 1024 || unit == "GiB" {
+			return fmt.Sprintf("%.1f %s", value, unit)
+		}
+		value /= 1024
+	}
+	return ""
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/its-the-vibe/RediScan/inspector"
+)
+
+func TestLindexHandler_Capabilities(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("jobs", "a", "b")
+	prev := redisCapabilities
+	defer func() { redisCapabilities = prev }()
+
+	probeCapabilities()
+	rr := httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=jobs", nil))
+	body := rr.Body.String()
+	if !strings.Contains(body, "<strong>Memory:</strong>") || !strings.Contains(body, "<strong>Last Access:</strong>") {
+		t.Errorf("expected the metadata the server supports, got: %s", body)
+	}
+	// miniredis has no OBJECT ENCODING, so the line is left out
+	if strings.Contains(body, "<strong>Encoding:</strong>") {
+		t.Errorf("expected no encoding from a server that refuses OBJECT ENCODING")
+	}
+
+	redisCapabilities = inspector.Capabilities{}
+	rr = httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=jobs", nil))
+	body = rr.Body.String()
+	if rr.Code != http.StatusOK || strings.Contains(body, "<strong>Memory:</strong>") || strings.Contains(body, "<strong>Last Access:</strong>") {
+		t.Errorf("expected the page without introspection metadata, got %d: %s", rr.Code, body)
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := map[int64]string{
+		512:        "512 bytes",
+		1536:       "1.5 KiB",
+		3 << 20:    "3.0 MiB",
+		5 << 30:    "5.0 GiB",
+		2048 << 30: "2048.0 GiB",
+	}
+	for n, want := range tests {
+		if got := formatSize(n); got != want {
+			t.Errorf("formatSize(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
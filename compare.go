@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/its-the-vibe/RediScan/inspector"
+	"github.com/redis/go-redis/v9"
+)
+
+// maxCompareIndices caps how many elements one comparison request may fetch.
+const maxCompareIndices = 50
+
+// renderComparison handles /lindex requests with a comma-separated index
+// list, fetching just those elements with a pipeline of LINDEX calls and
+// rendering them one above the other.
+func renderComparison(w http.ResponseWriter, r *http.Request, reqCtx context.Context, client redis.UniversalClient, key, indexList string) {
+	parts := strings.Split(indexList, ",")
+	if len(parts) > maxCompareIndices {
+		renderBadRequest(w, fmt.Sprintf("Too many indices: at most %d can be compared at once", maxCompareIndices))
+		return
+	}
+
+	llen, err := inspector.ListLength(reqCtx, client, key)
+	if err != nil {
+		renderListError(w, key, err)
+		return
+	}
+
+	// Each entry accepts the same forms as a single index
+	headIsNewest := inspector.MatchDirection(directionRules, key).HeadIsNewest()
+	indices := make([]int64, len(parts))
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			renderBadRequest(w, "Invalid 'index' parameter: empty entry in index list")
+			return
+		}
+		index, err := resolveIndex(part, "", llen, headIsNewest)
+		if err != nil {
+			renderBadRequest(w, fmt.Sprintf("%v: %q", err, part))
+			return
+		}
+		if index < 0 || index >= llen {
+			renderNotFound(w, fmt.Sprintf("Index %d out of bounds (list length: %d)", index, llen))
+			return
+		}
+		indices[i] = index
+	}
+
+	peeks, err := inspector.PeekIndices(reqCtx, client, key, llen, indices)
+	if err != nil {
+		renderListError(w, key, err)
+		return
+	}
+	recordAccess(r, key, indices...)
+	for i := range peeks {
+		if peeks[i].Found {
+			peeks[i].Value = renderValue(key, peeks[i].Value)
+		}
+	}
+
+	tmplStr := `<!DOCTYPE html>
+<html>
+<head>
+    <title>Compare Elements - RediScan</title>
+    <style>
+        body {
+            font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif;
+            max-width: 1200px;
+            margin: 0 auto;
+            padding: 20px;
+            background-color: #f5f5f5;
+        }
+        h1 a {
+            color: #333;
+            text-decoration: none;
+        }
+        h1 a:hover {
+            text-decoration: underline;
+        }
+        .metadata, .element {
+            background-color: white;
+            padding: 15px 20px;
+            border-radius: 5px;
+            margin-bottom: 20px;
+            box-shadow: 0 2px 4px rgba(0,0,0,0.1);
+        }
+        .metadata p {
+            margin: 5px 0;
+        }
+        .element h2 {
+            margin: 0 0 10px 0;
+            font-size: 18px;
+            color: #333;
+        }
+        .element h2 a {
+            font-size: 14px;
+            font-weight: normal;
+            color: #2196F3;
+            text-decoration: none;
+            margin-left: 10px;
+        }
+        pre {
+            background-color: #f9f9f9;
+            padding: 15px;
+            border-radius: 3px;
+            overflow-x: auto;
+            white-space: pre-wrap;
+            word-wrap: break-word;
+            margin: 0;
+        }
+        .missing {
+            color: #666;
+            font-style: italic;
+        }
+        .back-link {
+            color: #2196F3;
+            text-decoration: none;
+        }
+    </style>
+    {{customCSSLink}}
+</head>
+<body>
+    {{persistenceBanner}}
+    <h1><a href="/">RediScan - Redis List Inspector</a></h1>
+    <div class="metadata">
+        <p><strong>Key:</strong> {{displayKey .Key}}</p>
+        <p><strong>List Length:</strong> {{.LLen}}</p>
+    </div>
+    {{range .Peeks}}
+    <div class="element">
+        <h2>Index {{.Index}}<a href="{{lindexPath}}?key={{$.Key | urlquery}}&index={{.Index}}">Open</a></h2>
+        {{if .Found}}<pre>{{.Value}}</pre>{{else}}<p class="missing">(no longer available)</p>{{end}}
+    </div>
+    {{end}}
+    <a href="{{lindexPath}}?key={{.Key | urlquery}}" class="back-link">← Back to List</a>
+</body>
+</html>`
+
+	tmpl, err := parseTemplate("compare", tmplStr)
+	if err != nil {
+		renderError(w, fmt.Sprintf("Template error: %v", err))
+		return
+	}
+
+	data := struct {
+		Key   string
+		LLen  int64
+		Peeks []inspector.Peek
+	}{
+		Key:   key,
+		LLen:  llen,
+		Peeks: peeks,
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("Error rendering template: %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLindexHandler_MultipleIndices(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("events", `{"n":0}`, `{"n":1}`, `{"n":2}`, `{"n":3}`)
+
+	rr := httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=events&index=3,0,-2", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	body := rr.Body.String()
+	first := strings.Index(body, "Index 3<")
+	second := strings.Index(body, "Index 0<")
+	third := strings.Index(body, "Index 2<")
+	if first == -1 || second == -1 || third == -1 || !(first < second && second < third) {
+		t.Errorf("expected elements labelled in the requested order, got: %s", body)
+	}
+	if !strings.Contains(body, "&#34;n&#34;: 3") {
+		t.Errorf("expected pretty-printed values")
+	}
+	if strings.Contains(body, "&#34;n&#34;: 1") {
+		t.Errorf("expected only the requested elements")
+	}
+
+	rr = httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=events&index=1,x", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for a malformed entry, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=events&index=1,9", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for an out-of-bounds entry, got %d", rr.Code)
+	}
+}
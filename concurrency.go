@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	redisSlotWait       = 500 * time.Millisecond // How long a request waits for a free Redis slot before getting 503
+	redisBusyRetryAfter = 2                      // Seconds clients are told to wait before retrying when Redis is busy
+)
+
+// redisSlots is a semaphore bounding how many requests issue Redis commands
+// at once, sized by MAX_CONCURRENT_REDIS_OPS. Nil means unlimited.
+var redisSlots chan struct{}
+
+// redisFreeRoutes are the routes that never call Redis, so they are served
+// even while every slot is taken.
+var redisFreeRoutes = map[string]bool{
+	"index":              true,
+	"custom-css":         true,
+	"static":             true,
+	"admin-errors":       true,
+	"admin-errors-clear": true,
+	"admin-config":       true,
+	"admin-testconn":     true,
+}
+
+// redisBatchedRoutes are the long-running routes that take a Redis slot for
+// each batch of work themselves, with acquireRedisSlot, rather than holding
+// one for the whole request.
+var redisBatchedRoutes = map[string]bool{
+	"keys-export-api": true,
+}
+
+// limitRedis makes next hold one of the redisSlots while it runs. When none
+// frees up within redisSlotWait the request is refused with 503 and a
+// Retry-After hint, so a burst of users cannot pile parallel scans and
+// LRANGEs onto a shared Redis.
+func limitRedis(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !acquireRedisSlot(r.Context(), redisSlotWait) {
+			if r.Context().Err() == nil {
+				renderRedisBusy(w, r)
+			}
+			return
+		}
+		defer releaseRedisSlot()
+		next(w, r)
+	}
+}
+
+// acquireRedisSlot takes one of the redisSlots, waiting at most wait for one
+// to free up, or until ctx is done when wait is 0, and reports whether it got
+// one. Each slot taken must be given back with releaseRedisSlot.
+func acquireRedisSlot(ctx context.Context, wait time.Duration) bool {
+	if redisSlots == nil {
+		return true
+	}
+	var timeout <-chan time.Time
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case redisSlots <- struct{}{}:
+		return true
+	case <-timeout:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// releaseRedisSlot gives back a slot taken by acquireRedisSlot.
+func releaseRedisSlot() {
+	if redisSlots != nil {
+		<-redisSlots
+	}
+}
+
+// renderRedisBusy refuses a request that found no free Redis slot with 503
+// and a Retry-After hint.
+func renderRedisBusy(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Retry-After", strconv.Itoa(redisBusyRetryAfter))
+	message := "Redis is busy serving other requests (MAX_CONCURRENT_REDIS_OPS reached). Try again in a few seconds."
+	if strings.HasPrefix(r.URL.Path, "/api/") || wantsJSON(r) {
+		writeAPIError(w, http.StatusServiceUnavailable, apiErrBusy, message)
+		return
+	}
+	renderErrorStatus(w, http.StatusServiceUnavailable, "Busy", message)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLimitRedis(t *testing.T) {
+	prev := redisSlots
+	defer func() { redisSlots = prev }()
+	redisSlots = make(chan struct{}, 1)
+
+	ran := 0
+	handler := limitRedis(func(w http.ResponseWriter, r *http.Request) {
+		ran++
+		if redisSlots != nil && len(redisSlots) != 1 {
+			t.Errorf("expected the handler to hold a slot while running")
+		}
+	})
+
+	rr := httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=jobs", nil))
+	if ran != 1 || rr.Code != http.StatusOK || len(redisSlots) != 0 {
+		t.Fatalf("expected the handler to run and release its slot, got status %d", rr.Code)
+	}
+
+	// With every slot taken, requests are turned away
+	redisSlots <- struct{}{}
+	rr = httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=jobs", nil))
+	if ran != 1 || rr.Code != http.StatusServiceUnavailable || rr.Header().Get("Retry-After") == "" {
+		t.Errorf("expected 503 with Retry-After when saturated, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "MAX_CONCURRENT_REDIS_OPS") {
+		t.Errorf("expected the error page to explain the limit")
+	}
+
+	rr = httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodGet, "/api/lists", nil))
+	if rr.Code != http.StatusServiceUnavailable || !strings.Contains(rr.Body.String(), apiErrBusy) {
+		t.Errorf("expected an API error for API routes, got %d %s", rr.Code, rr.Body.String())
+	}
+
+	// Without a limit, handlers run regardless
+	redisSlots = nil
+	handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/lindex?key=jobs", nil))
+	if ran != 2 {
+		t.Errorf("expected the handler to run without a limit")
+	}
+}
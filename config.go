@@ -0,0 +1,260 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	serverPort     = "8080" // Port the server listens on
+	serverTLS      bool     // Whether SERVER_TLS_CERT/SERVER_TLS_KEY are in use
+	configFile     string   // Path of the CONFIG_FILE settings were read from, if any
+	disabledRoutes map[string]bool
+)
+
+// configEntry is one row of the effective configuration page.
+type configEntry struct {
+	Name  string
+	Value string
+}
+
+// redacted stands in for a setting that is not shown, saying only whether it
+// is set.
+func redacted(value string) string {
+	if value == "" {
+		return "(not set)"
+	}
+	return "(set, redacted)"
+}
+
+// effectiveConfig describes the settings the server is actually running
+// with, after defaults and invalid values have been applied. Secrets and the
+// addresses of Redis servers are never included, only whether they are set.
+func effectiveConfig() []configEntry {
+	var addr, clusterAddrs, redisPassword, clientName string
+	var db int
+	switch client := redisClient.(type) {
+	case *redis.ClusterClient:
+		opts := client.Options()
+		clusterAddrs = strings.Join(opts.Addrs, ",")
+		redisPassword, clientName = opts.Password, opts.ClientName
+	case *redis.Client:
+		opts := client.Options()
+		addr, db = opts.Addr, opts.DB
+		redisPassword, clientName = opts.Password, opts.ClientName
+	}
+
+	logLevel := "info"
+	if debugLogging {
+		logLevel = "debug"
+	}
+
+	// Only the command is shown, as its arguments may carry secrets
+	filterCmd := "(none)"
+	if valueFilterArgs != nil {
+		filterCmd = valueFilterArgs[0]
+		if len(valueFilterArgs) > 1 {
+			filterCmd += " (arguments hidden)"
+		}
+	}
+
+	css := "(none)"
+	if customCSS != nil {
+		css = customCSSPath
+	}
+
+	var disabled []string
+	for name := range disabledRoutes {
+		disabled = append(disabled, name)
+	}
+	sort.Strings(disabled)
+
+	var transforms []string
+	for _, rule := range transformRules {
+		transforms = append(transforms, rule.Pattern.String()+" → "+strings.Join(rule.Stages, " → "))
+	}
+	var schemas []string
+	for _, rule := range schemaRules {
+		schemas = append(schemas, rule.Pattern.String()+" → "+rule.Path)
+	}
+	var directions []string
+	for _, rule := range directionRules {
+		directions = append(directions, rule.Pattern.String()+" → "+string(rule.Direction))
+	}
+	var summaries []string
+	for _, rule := range summaryRules {
+		summaries = append(summaries, rule.Pattern.String()+" → "+strings.Join(rule.Fields, ", "))
+	}
+	var timestamps []string
+	for _, rule := range timestampRules {
+		timestamps = append(timestamps, rule.Pattern.String()+" → "+rule.Field)
+	}
+	var alerts []string
+	for _, rule := range alertRules {
+		for _, re := range rule.Alerts {
+			alerts = append(alerts, rule.Pattern.String()+" → "+re.String())
+		}
+	}
+
+	file := "(none)"
+	if configFile != "" {
+		file = configFile
+	}
+
+	return []configEntry{
+		{"CONFIG_FILE", file},
+		{"REDIS_ADDR", redacted(addr)},
+		{"REDIS_PASSWORD", redacted(redisPassword)},
+		{"REDIS_DB", strconv.Itoa(db)},
+		{"REDIS_CLUSTER_ADDRS", redacted(clusterAddrs)},
+		{"REDIS_CLIENT_NAME", clientName},
+		{"REDIS_RETRY_ATTEMPTS", strconv.Itoa(redisRetry.attempts)},
+		{"REDIS_RETRY_DELAY", redisRetry.baseDelay.String()},
+		{"REDIS_MAX_TIMEOUT", maxRequestTimeout.String()},
+		{"MAX_CONCURRENT_REDIS_OPS", strconv.Itoa(cap(redisSlots))},
+		{"AUDIT_REDIS", strconv.FormatBool(auditRedis)},
+		{"AUDIT_MAX_ENTRIES", strconv.FormatInt(auditMaxEntries, 10)},
+		{"FIND_INDEX_TTL", findIndexTTL.String()},
+		{"PERSISTENCE_CHECK", strconv.FormatBool(persistenceCheck)},
+		{"MAX_REQUEST_BODY_BYTES", strconv.FormatInt(maxRequestBodyBytes, 10)},
+		{"LOG_LEVEL", logLevel},
+		{"PORT", serverPort},
+		{"TLS", strconv.FormatBool(serverTLS)},
+		{"MAX_LISTS", strconv.Itoa(maxLists)},
+		{"INDEX_GROUP_BY_TYPE", strconv.FormatBool(groupByType)},
+		{"KEY_DELIMITER", keyDelimiter},
+		{"HOME_REDIRECT", homeRedirect},
+		{"LINDEX_ROUTE", lindexRoute},
+		{"WRITE_ENABLED", strconv.FormatBool(writeEnabled)},
+		{"FORCE_READONLY", strconv.FormatBool(forceReadOnly)},
+		{"PREFS_ENABLED", strconv.FormatBool(prefsEnabled)},
+		{"TESTCONN_ENABLED", strconv.FormatBool(testConnEnabled)},
+		{"ADMIN_PASSWORD", redacted(adminPassword)},
+		{"PREFS_USER_HEADER", prefsUserHeader},
+		{"PREVIEW_LENGTH", strconv.Itoa(previewLength)},
+		{"DISPLAY_TIMEZONE", displayLocation.String()},
+		{"WRAP_MODE", wrapMode},
+		{"PRETTY_CACHE_SIZE", strconv.Itoa(prettyJSON.Capacity())},
+		{"MAX_JSON_DEPTH", strconv.Itoa(maxJSONDepth)},
+		{"JSON_KEY_ORDER", jsonKeyOrder},
+		{"MAX_PRELOAD_BYTES", strconv.Itoa(maxPreloadSize)},
+		{"MAX_VALUE_BYTES", strconv.Itoa(maxValueBytes)},
+		{"PRELOAD_NEWEST", strconv.FormatInt(preloadNewest, 10)},
+		{"DUPLICATES_MAX_LENGTH", strconv.FormatInt(duplicatesMaxLength, 10)},
+		{"SAMPLE_COUNT", strconv.Itoa(sampleCount)},
+		{"CUSTOM_CSS_PATH", css},
+		{"STATIC_CACHE_MAX_AGE", staticMaxAge.String()},
+		{"LIVE_IDLE_TIMEOUT", liveIdleTimeout.String()},
+		{"ERROR_REPORT_SIZE", strconv.Itoa(erroredKeys.capacity)},
+		{"METRICS_LIST_PATTERNS", listOrNone(metricsPatterns)},
+		{"METRICS_MAX_SERIES", strconv.Itoa(metricsMaxSeries)},
+		{"VALUE_TRANSFORMS", listOrNone(transforms)},
+		{"VALUE_FILTER_CMD", filterCmd},
+		{"VALUE_FILTER_TIMEOUT", valueFilterTimeout.String()},
+		{"VALUE_FILTER_MAX_BYTES", strconv.FormatInt(valueFilterMaxBytes, 10)},
+		{"VALUE_FILTER_BUDGET", valueFilterBudget.String()},
+		{"JSON_SCHEMAS", listOrNone(schemas)},
+		{"PUSH_DIRECTIONS", listOrNone(directions)},
+		{"ALERT_PATTERNS", listOrNone(alerts)},
+		{"SUMMARY_FIELDS", listOrNone(summaries)},
+		{"TIMESTAMP_FIELDS", listOrNone(timestamps)},
+		{"DISABLED_ROUTES", listOrNone(disabled)},
+	}
+}
+
+func listOrNone(items []string) string {
+	if len(items) == 0 {
+		return "(none)"
+	}
+	return strings.Join(items, "\n")
+}
+
+// configHandler shows the effective configuration, for diagnosing why one
+// instance behaves differently from another.
+func configHandler(w http.ResponseWriter, r *http.Request) {
+	tmplStr := `<!DOCTYPE html>
+<html>
+<head>
+    <title>Configuration - RediScan</title>
+    <style>
+        body {
+            font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif;
+            max-width: 1200px;
+            margin: 0 auto;
+            padding: 20px;
+            background-color: #f5f5f5;
+        }
+        h1 a {
+            color: #333;
+            text-decoration: none;
+        }
+        h1 a:hover {
+            text-decoration: underline;
+        }
+        .report {
+            background-color: white;
+            padding: 20px;
+            border-radius: 5px;
+            box-shadow: 0 2px 4px rgba(0,0,0,0.1);
+        }
+        .report h2 {
+            margin-top: 0;
+            color: #333;
+        }
+        table {
+            width: 100%;
+            border-collapse: collapse;
+        }
+        th, td {
+            text-align: left;
+            padding: 8px;
+            border-bottom: 1px solid #eee;
+            vertical-align: top;
+        }
+        .name {
+            font-weight: bold;
+            white-space: nowrap;
+        }
+        .value {
+            font-family: monospace;
+            white-space: pre-wrap;
+            word-break: break-word;
+        }
+    </style>
+    {{customCSSLink}}
+</head>
+<body>
+    {{persistenceBanner}}
+    <h1><a href="/">RediScan - Redis List Inspector</a></h1>
+    <div class="report">
+        <h2>Effective Configuration</h2>
+        <table>
+            <tr><th>Setting</th><th>Value</th></tr>
+            {{range .}}
+            <tr>
+                <td class="name">{{.Name}}</td>
+                <td class="value">{{.Value}}</td>
+            </tr>
+            {{end}}
+        </table>
+    </div>
+</body>
+</html>`
+
+	tmpl, err := parseTemplate("config", tmplStr)
+	if err != nil {
+		renderError(w, fmt.Sprintf("Template error: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tmpl.Execute(w, effectiveConfig()); err != nil {
+		log.Printf("Error rendering template: %v", err)
+	}
+}
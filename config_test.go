@@ -0,0 +1,60 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestConfigHandler_Redacts(t *testing.T) {
+	prev, prevFilter := redisClient, valueFilterArgs
+	redisClient = redis.NewClient(&redis.Options{Addr: "redis.internal:6380", Password: "hunter2", DB: 3})
+	valueFilterArgs = []string{"decode-events", "--token=s3cret"}
+	defer func() {
+		redisClient.Close()
+		redisClient, valueFilterArgs = prev, prevFilter
+	}()
+
+	rr := httptest.NewRecorder()
+	configHandler(rr, httptest.NewRequest(http.MethodGet, "/admin/config", nil))
+	body := rr.Body.String()
+	if strings.Contains(body, "hunter2") {
+		t.Fatalf("expected the Redis password to be redacted")
+	}
+	if strings.Contains(body, "redis.internal") {
+		t.Errorf("expected the Redis address to be redacted")
+	}
+	if strings.Contains(body, "s3cret") {
+		t.Errorf("expected the filter command's arguments to be hidden")
+	}
+	for _, want := range []string{"(set, redacted)", `<td class="value">3</td>`, "decode-events (arguments hidden)"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q on config page", want)
+		}
+	}
+}
+
+func TestConfigHandler_RequiresAdmin(t *testing.T) {
+	prev := adminPassword
+	defer func() { adminPassword = prev }()
+	adminPassword = "s3cret"
+	mux := http.NewServeMux()
+	registerRoutes(mux, nil)
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/admin/config", nil))
+	if rr.Code != http.StatusUnauthorized || rr.Header().Get("WWW-Authenticate") == "" {
+		t.Errorf("expected 401 with a Basic challenge, got %d", rr.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/config", nil)
+	req.SetBasicAuth("admin", "s3cret")
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "REDIS_ADDR") {
+		t.Errorf("expected the config page with the admin password, got %d", rr.Code)
+	}
+}
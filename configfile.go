@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds the raw value of every setting, keyed in files by the
+// lower-cased environment variable name (e.g. redis_addr for REDIS_ADDR).
+// Values are parsed and validated by main exactly as if they had come from
+// the environment.
+//
+// In a file, settings that take JSON (such as value_transforms) may be
+// written as native YAML structures, and disabled_routes as a list.
+type Config struct {
+	RedisAddr             string `yaml:"redis_addr" env:"REDIS_ADDR"`
+	RedisPassword         string `yaml:"redis_password" env:"REDIS_PASSWORD"`
+	RedisDB               string `yaml:"redis_db" env:"REDIS_DB"`
+	RedisClusterAddrs     string `yaml:"redis_cluster_addrs" env:"REDIS_CLUSTER_ADDRS"`
+	RedisClientName       string `yaml:"redis_client_name" env:"REDIS_CLIENT_NAME"`
+	LogLevel              string `yaml:"log_level" env:"LOG_LEVEL"`
+	RedisRetryAttempts    string `yaml:"redis_retry_attempts" env:"REDIS_RETRY_ATTEMPTS"`
+	RedisRetryDelay       string `yaml:"redis_retry_delay" env:"REDIS_RETRY_DELAY"`
+	RedisMaxTimeout       string `yaml:"redis_max_timeout" env:"REDIS_MAX_TIMEOUT"`
+	MaxConcurrentRedisOps string `yaml:"max_concurrent_redis_ops" env:"MAX_CONCURRENT_REDIS_OPS"`
+	AuditRedis            string `yaml:"audit_redis" env:"AUDIT_REDIS"`
+	AuditMaxEntries       string `yaml:"audit_max_entries" env:"AUDIT_MAX_ENTRIES"`
+	FindIndexTTL          string `yaml:"find_index_ttl" env:"FIND_INDEX_TTL"`
+	PersistenceCheck      string `yaml:"persistence_check" env:"PERSISTENCE_CHECK"`
+	MaxRequestBodyBytes   string `yaml:"max_request_body_bytes" env:"MAX_REQUEST_BODY_BYTES"`
+	MaxLists              string `yaml:"max_lists" env:"MAX_LISTS"`
+	IndexGroupByType      string `yaml:"index_group_by_type" env:"INDEX_GROUP_BY_TYPE"`
+	KeyDelimiter          string `yaml:"key_delimiter" env:"KEY_DELIMITER"`
+	HomeRedirect          string `yaml:"home_redirect" env:"HOME_REDIRECT"`
+	LindexRoute           string `yaml:"lindex_route" env:"LINDEX_ROUTE"`
+	PreviewLength         string `yaml:"preview_length" env:"PREVIEW_LENGTH"`
+	WriteEnabled          string `yaml:"write_enabled" env:"WRITE_ENABLED"`
+	ForceReadOnly         string `yaml:"force_readonly" env:"FORCE_READONLY"`
+	PrefsEnabled          string `yaml:"prefs_enabled" env:"PREFS_ENABLED"`
+	TestConnEnabled       string `yaml:"testconn_enabled" env:"TESTCONN_ENABLED"`
+	AdminPassword         string `yaml:"admin_password" env:"ADMIN_PASSWORD"`
+	PrefsUserHeader       string `yaml:"prefs_user_header" env:"PREFS_USER_HEADER"`
+	DisplayTimezone       string `yaml:"display_timezone" env:"DISPLAY_TIMEZONE"`
+	WrapMode              string `yaml:"wrap_mode" env:"WRAP_MODE"`
+	PrettyCacheSize       string `yaml:"pretty_cache_size" env:"PRETTY_CACHE_SIZE"`
+	MaxJSONDepth          string `yaml:"max_json_depth" env:"MAX_JSON_DEPTH"`
+	JSONKeyOrder          string `yaml:"json_key_order" env:"JSON_KEY_ORDER"`
+	MaxPreloadBytes       string `yaml:"max_preload_bytes" env:"MAX_PRELOAD_BYTES"`
+	MaxValueBytes         string `yaml:"max_value_bytes" env:"MAX_VALUE_BYTES"`
+	PreloadNewest         string `yaml:"preload_newest" env:"PRELOAD_NEWEST"`
+	DuplicatesMaxLength   string `yaml:"duplicates_max_length" env:"DUPLICATES_MAX_LENGTH"`
+	SampleCount           string `yaml:"sample_count" env:"SAMPLE_COUNT"`
+	CustomCSSPath         string `yaml:"custom_css_path" env:"CUSTOM_CSS_PATH"`
+	StaticCacheMaxAge     string `yaml:"static_cache_max_age" env:"STATIC_CACHE_MAX_AGE"`
+	LiveIdleTimeout       string `yaml:"live_idle_timeout" env:"LIVE_IDLE_TIMEOUT"`
+	ValueTransforms       string `yaml:"value_transforms" env:"VALUE_TRANSFORMS"`
+	ValueFilterCmd        string `yaml:"value_filter_cmd" env:"VALUE_FILTER_CMD"`
+	ValueFilterTimeout    string `yaml:"value_filter_timeout" env:"VALUE_FILTER_TIMEOUT"`
+	ValueFilterMaxBytes   string `yaml:"value_filter_max_bytes" env:"VALUE_FILTER_MAX_BYTES"`
+	ValueFilterBudget     string `yaml:"value_filter_budget" env:"VALUE_FILTER_BUDGET"`
+	ErrorReportSize       string `yaml:"error_report_size" env:"ERROR_REPORT_SIZE"`
+	MetricsListPatterns   string `yaml:"metrics_list_patterns" env:"METRICS_LIST_PATTERNS"`
+	MetricsMaxSeries      string `yaml:"metrics_max_series" env:"METRICS_MAX_SERIES"`
+	JSONSchemas           string `yaml:"json_schemas" env:"JSON_SCHEMAS"`
+	PushDirections        string `yaml:"push_directions" env:"PUSH_DIRECTIONS"`
+	AlertPatterns         string `yaml:"alert_patterns" env:"ALERT_PATTERNS"`
+	SummaryFields         string `yaml:"summary_fields" env:"SUMMARY_FIELDS"`
+	TimestampFields       string `yaml:"timestamp_fields" env:"TIMESTAMP_FIELDS"`
+	DemoMode              string `yaml:"demo_mode" env:"DEMO_MODE"`
+	DemoSafeDB            string `yaml:"demo_safe_db" env:"DEMO_SAFE_DB"`
+	DisabledRoutes        string `yaml:"disabled_routes" env:"DISABLED_ROUTES"`
+	Port                  string `yaml:"port" env:"PORT"`
+	ServerTLSCert         string `yaml:"server_tls_cert" env:"SERVER_TLS_CERT"`
+	ServerTLSKey          string `yaml:"server_tls_key" env:"SERVER_TLS_KEY"`
+}
+
+// loadConfig reads the optional config file at path and overlays any
+// environment variables that are set, so the environment always wins.
+// YAML and JSON files are both accepted, as JSON is valid YAML.
+func loadConfig(path string, lookupEnv func(string) (string, bool)) (Config, error) {
+	var cfg Config
+	v := reflect.ValueOf(&cfg).Elem()
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return cfg, err
+		}
+		var file map[string]yaml.Node
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return cfg, fmt.Errorf("%s: %w", path, err)
+		}
+		fields := make(map[string]int)
+		for i := 0; i < v.NumField(); i++ {
+			fields[v.Type().Field(i).Tag.Get("yaml")] = i
+		}
+		for key, node := range file {
+			i, ok := fields[key]
+			if !ok {
+				return cfg, fmt.Errorf("%s: unknown setting %q", path, key)
+			}
+			value, err := settingValue(&node)
+			if err != nil {
+				return cfg, fmt.Errorf("%s: %s: %w", path, key, err)
+			}
+			v.Field(i).SetString(value)
+		}
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		if value, ok := lookupEnv(v.Type().Field(i).Tag.Get("env")); ok && value != "" {
+			v.Field(i).SetString(value)
+		}
+	}
+	return cfg, nil
+}
+
+// settingValue flattens a config file value into the string form its
+// environment variable takes: scalars as written, lists of scalars
+// comma-separated and any other structure as JSON.
+func settingValue(node *yaml.Node) (string, error) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Tag == "!!null" {
+			return "", nil
+		}
+		return node.Value, nil
+	case yaml.SequenceNode:
+		var items []string
+		if err := node.Decode(&items); err == nil {
+			return strings.Join(items, ","), nil
+		}
+	}
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return "", err
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func envMap(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+}
+
+func TestLoadConfig_YAMLWithEnvOverride(t *testing.T) {
+	path := writeConfigFile(t, "rediscan.yaml", `
+redis_addr: redis.internal:6380
+redis_db: 2
+write_enabled: true
+disabled_routes: [admin-errors, admin-errors-clear]
+value_transforms:
+  - pattern: "jobs:*"
+    transforms: [base64, json]
+`)
+
+	cfg, err := loadConfig(path, envMap(map[string]string{"REDIS_DB": "5", "PORT": "9090"}))
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	if cfg.RedisAddr != "redis.internal:6380" {
+		t.Errorf("RedisAddr = %q", cfg.RedisAddr)
+	}
+	if cfg.RedisDB != "5" {
+		t.Errorf("expected REDIS_DB to override the file, got %q", cfg.RedisDB)
+	}
+	if cfg.Port != "9090" || cfg.WriteEnabled != "true" {
+		t.Errorf("Port = %q, WriteEnabled = %q", cfg.Port, cfg.WriteEnabled)
+	}
+	if cfg.DisabledRoutes != "admin-errors,admin-errors-clear" {
+		t.Errorf("DisabledRoutes = %q", cfg.DisabledRoutes)
+	}
+	if want := `[{"pattern":"jobs:*","transforms":["base64","json"]}]`; cfg.ValueTransforms != want {
+		t.Errorf("ValueTransforms = %q, want %q", cfg.ValueTransforms, want)
+	}
+}
+
+func TestLoadConfig_JSON(t *testing.T) {
+	path := writeConfigFile(t, "rediscan.json", `{"redis_addr": "db:6379", "max_lists": 40, "json_schemas": "[]"}`)
+
+	cfg, err := loadConfig(path, envMap(map[string]string{"REDIS_ADDR": ""}))
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	if cfg.RedisAddr != "db:6379" || cfg.MaxLists != "40" || cfg.JSONSchemas != "[]" {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+}
+
+func TestLoadConfig_Errors(t *testing.T) {
+	if _, err := loadConfig(filepath.Join(t.TempDir(), "missing.yaml"), envMap(nil)); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+	if _, err := loadConfig(writeConfigFile(t, "typo.yaml", "redis_adr: x\n"), envMap(nil)); err == nil {
+		t.Error("expected an error for an unknown setting")
+	}
+	if _, err := loadConfig(writeConfigFile(t, "bad.yaml", "redis_addr: [\n"), envMap(nil)); err == nil {
+		t.Error("expected an error for malformed YAML")
+	}
+}
+
+func TestLoadConfig_NoFile(t *testing.T) {
+	cfg, err := loadConfig("", envMap(map[string]string{"REDIS_ADDR": "env:6379"}))
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	if cfg.RedisAddr != "env:6379" {
+		t.Errorf("RedisAddr = %q", cfg.RedisAddr)
+	}
+}
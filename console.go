@@ -0,0 +1,318 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// consoleReadCommands may be run from the console at any time. They do not
+// modify data and are not expensive enough to stall the server (KEYS is left
+// out in favour of SCAN).
+var consoleReadCommands = map[string]bool{
+	"get": true, "mget": true, "strlen": true, "getrange": true,
+	"type": true, "ttl": true, "pttl": true, "exists": true, "dbsize": true,
+	"llen": true, "lrange": true, "lindex": true, "lpos": true,
+	"hget": true, "hmget": true, "hgetall": true, "hkeys": true, "hvals": true, "hlen": true, "hexists": true, "hscan": true,
+	"smembers": true, "scard": true, "sismember": true, "srandmember": true, "sscan": true,
+	"zrange": true, "zrangebyscore": true, "zcard": true, "zscore": true, "zrank": true, "zscan": true,
+	"xrange": true, "xrevrange": true, "xlen": true, "xinfo": true,
+	"scan": true, "object": true, "info": true,
+}
+
+// consoleWriteCommands may only be run from the console when WRITE_ENABLED
+// is set. Anything in neither list, such as FLUSHALL or CONFIG, is refused.
+var consoleWriteCommands = map[string]bool{
+	"set": true, "del": true, "expire": true, "persist": true,
+	"lpush": true, "rpush": true, "lpop": true, "rpop": true, "lset": true, "lrem": true, "ltrim": true,
+	"hset": true, "hdel": true, "sadd": true, "srem": true, "zadd": true, "zrem": true, "xadd": true,
+}
+
+// parseCommandLine splits a command typed as in redis-cli into arguments.
+// Arguments are separated by whitespace and may be quoted with "..." (which
+// understands backslash escapes such as \n and \x41) or '...' (taken
+// literally).
+func parseCommandLine(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == ' ' || c == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		case c == '"':
+			// Let strconv handle the escapes by finding the closing quote
+			end := i + 1
+			for ; end < len(line) && line[end] != '"'; end++ {
+				if line[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(line) {
+				return nil, errors.New("unterminated double quote")
+			}
+			unquoted, err := strconv.Unquote(line[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid quoted argument %s", line[i:end+1])
+			}
+			current.WriteString(unquoted)
+			inArg = true
+			i = end
+		case c == '\'':
+			end := strings.IndexByte(line[i+1:], '\'')
+			if end < 0 {
+				return nil, errors.New("unterminated single quote")
+			}
+			current.WriteString(line[i+1 : i+1+end])
+			inArg = true
+			i += end + 1
+		default:
+			current.WriteByte(c)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// checkConsoleCommand returns an error unless the command may be run. Writes
+// must additionally arrive by POST, so a link cannot trigger one; forms
+// posted from other sites are refused by consoleHandler before this.
+func checkConsoleCommand(args []string, method string) error {
+	if len(args) == 0 {
+		return errors.New("enter a command")
+	}
+	name := strings.ToLower(args[0])
+	switch {
+	case consoleReadCommands[name]:
+		return nil
+	case consoleWriteCommands[name] && !writeEnabled:
+		return fmt.Errorf("%s modifies data, which is disabled. Set WRITE_ENABLED=true to allow it", strings.ToUpper(name))
+	case consoleWriteCommands[name] && method != http.MethodPost:
+		return fmt.Errorf("%s modifies data, so it must be submitted from the form", strings.ToUpper(name))
+	case consoleWriteCommands[name]:
+		return nil
+	default:
+		return fmt.Errorf("%s is not allowed in the console", strings.ToUpper(name))
+	}
+}
+
+// formatReply renders a Redis reply the way redis-cli does, with string
+// values pretty-printed when they are JSON.
+func formatReply(reply interface{}, indent string) string {
+	switch v := reply.(type) {
+	case nil:
+		return "(nil)"
+	case string:
+		if v == "" {
+			return `""`
+		}
+		return indentLines(prettyPrintJSON(v), indent)
+	case int64:
+		return "(integer) " + strconv.FormatInt(v, 10)
+	case float64:
+		return "(double) " + strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		return "(boolean) " + strconv.FormatBool(v)
+	case []interface{}:
+		if len(v) == 0 {
+			return "(empty array)"
+		}
+		var b strings.Builder
+		for i, item := range v {
+			if i > 0 {
+				b.WriteString("\n" + indent)
+			}
+			prefix := strconv.Itoa(i+1) + ") "
+			b.WriteString(prefix + formatReply(item, indent+strings.Repeat(" ", len(prefix))))
+		}
+		return b.String()
+	case map[interface{}]interface{}:
+		if len(v) == 0 {
+			return "(empty hash)"
+		}
+		keys := make([]string, 0, len(v))
+		values := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			k := fmt.Sprint(key)
+			keys = append(keys, k)
+			values[k] = value
+		}
+		sort.Strings(keys)
+		var b strings.Builder
+		for i, k := range keys {
+			if i > 0 {
+				b.WriteString("\n" + indent)
+			}
+			prefix := strconv.Itoa(i+1) + "# " + k + " => "
+			b.WriteString(prefix + formatReply(values[k], indent+strings.Repeat(" ", len(strconv.Itoa(i+1))+2)))
+		}
+		return b.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// indentLines indents every line of s after the first.
+func indentLines(s, indent string) string {
+	return strings.ReplaceAll(s, "\n", "\n"+indent)
+}
+
+// consoleHandler runs a single allowlisted Redis command and shows its reply.
+func consoleHandler(w http.ResponseWriter, r *http.Request) {
+	// A form on another site could otherwise post writes from the operator's browser
+	if !refuseCrossOrigin(w, r) {
+		return
+	}
+
+	command := r.FormValue("cmd")
+	var result, errMessage string
+	status := http.StatusOK
+	if strings.TrimSpace(command) != "" {
+		args, err := parseCommandLine(command)
+		if err == nil {
+			err = checkConsoleCommand(args, r.Method)
+		}
+		if err != nil {
+			errMessage = err.Error()
+			status = http.StatusBadRequest
+		} else {
+			cmdArgs := make([]interface{}, len(args))
+			for i, arg := range args {
+				cmdArgs[i] = arg
+			}
+			reply, err := redisClient.Do(ctx, cmdArgs...).Result()
+			if consoleWriteCommands[strings.ToLower(args[0])] {
+				forgetEmptyScan()
+			}
+			switch {
+			case errors.Is(err, redis.Nil):
+				result = "(nil)"
+			case err != nil:
+				errMessage = "(error) " + err.Error()
+			default:
+				result = formatReply(reply, "")
+			}
+		}
+	}
+
+	tmplStr := `<!DOCTYPE html>
+<html>
+<head>
+    <title>Console - RediScan</title>
+    <style>
+        body {
+            font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif;
+            max-width: 1200px;
+            margin: 0 auto;
+            padding: 20px;
+            background-color: #f5f5f5;
+        }
+        h1 a {
+            color: #333;
+            text-decoration: none;
+        }
+        h1 a:hover {
+            text-decoration: underline;
+        }
+        .console {
+            background-color: white;
+            padding: 20px;
+            border-radius: 5px;
+            box-shadow: 0 2px 4px rgba(0,0,0,0.1);
+            margin-bottom: 20px;
+        }
+        .console form {
+            display: flex;
+            gap: 10px;
+        }
+        .console input {
+            flex-grow: 1;
+            padding: 8px;
+            font-family: monospace;
+            border: 1px solid #ddd;
+            border-radius: 3px;
+        }
+        .console button {
+            background-color: #2196F3;
+            color: white;
+            padding: 8px 16px;
+            border: none;
+            border-radius: 3px;
+            cursor: pointer;
+        }
+        .hint {
+            color: #666;
+            font-size: 14px;
+        }
+        pre {
+            background-color: #f9f9f9;
+            padding: 15px;
+            border-radius: 3px;
+            overflow-x: auto;
+            white-space: pre-wrap;
+            word-wrap: break-word;
+        }
+        .error {
+            color: #c62828;
+        }
+        .back-link {
+            color: #2196F3;
+            text-decoration: none;
+        }
+    </style>
+    {{customCSSLink}}
+</head>
+<body>
+    {{persistenceBanner}}
+    <h1><a href="/">RediScan - Redis List Inspector</a></h1>
+    <div class="console">
+        <form action="/console" method="post">
+            <input type="text" name="cmd" value="{{.Command}}" placeholder="e.g., LRANGE jobs 0 9" autofocus autocomplete="off">
+            <button type="submit">Run</button>
+        </form>
+        <p class="hint">Read commands such as GET, LRANGE, HGETALL, TYPE, TTL and SCAN are allowed.{{if .WriteEnabled}} Commands that modify data (SET, DEL, LPUSH, ...) are also allowed because WRITE_ENABLED is set.{{end}} Quote arguments containing spaces as in redis-cli.</p>
+        {{if .Error}}<pre class="error">{{.Error}}</pre>{{end}}
+        {{if .Result}}<pre>{{.Result}}</pre>{{end}}
+    </div>
+    <a href="/" class="back-link">← Back to Home</a>
+</body>
+</html>`
+
+	tmpl, err := parseTemplate("console", tmplStr)
+	if err != nil {
+		renderError(w, fmt.Sprintf("Template error: %v", err))
+		return
+	}
+
+	data := struct {
+		Command      string
+		Result       string
+		Error        string
+		WriteEnabled bool
+	}{
+		Command:      command,
+		Result:       result,
+		Error:        errMessage,
+		WriteEnabled: writeEnabled,
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("Error rendering template: %v", err)
+	}
+}
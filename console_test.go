@@ -0,0 +1,109 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCommandLine(t *testing.T) {
+	tests := map[string][]string{
+		`LRANGE jobs 0 -1`:               {"LRANGE", "jobs", "0", "-1"},
+		`  GET   "my key"  `:             {"GET", "my key"},
+		`SET k "line\nbreak" `:           {"SET", "k", "line\nbreak"},
+		`SET k 'it''s'`:                  {"SET", "k", "its"},
+		`HGET "user:\x41" 'raw \n text'`: {"HGET", "user:A", `raw \n text`},
+		`GET ""`:                         {"GET", ""},
+	}
+	for line, want := range tests {
+		got, err := parseCommandLine(line)
+		if err != nil {
+			t.Errorf("parseCommandLine(%q) error: %v", line, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("parseCommandLine(%q) = %q, want %q", line, got, want)
+		}
+	}
+
+	for _, line := range []string{`GET "open`, `GET 'open`} {
+		if _, err := parseCommandLine(line); err == nil {
+			t.Errorf("expected an error for %q", line)
+		}
+	}
+}
+
+func TestCheckConsoleCommand(t *testing.T) {
+	prev := writeEnabled
+	defer func() { writeEnabled = prev }()
+
+	writeEnabled = false
+	if err := checkConsoleCommand([]string{"lrange", "jobs", "0", "1"}, http.MethodGet); err != nil {
+		t.Errorf("expected reads to be allowed, got %v", err)
+	}
+	if err := checkConsoleCommand([]string{"DEL", "jobs"}, http.MethodPost); err == nil {
+		t.Error("expected writes to be refused without WRITE_ENABLED")
+	}
+	if err := checkConsoleCommand([]string{"FLUSHALL"}, http.MethodPost); err == nil {
+		t.Error("expected FLUSHALL to be refused")
+	}
+
+	writeEnabled = true
+	if err := checkConsoleCommand([]string{"DEL", "jobs"}, http.MethodPost); err != nil {
+		t.Errorf("expected writes to be allowed with WRITE_ENABLED, got %v", err)
+	}
+	if err := checkConsoleCommand([]string{"DEL", "jobs"}, http.MethodGet); err == nil {
+		t.Error("expected writes by GET to be refused")
+	}
+	if err := checkConsoleCommand([]string{"CONFIG", "SET", "x", "y"}, http.MethodPost); err == nil {
+		t.Error("expected CONFIG to be refused even with WRITE_ENABLED")
+	}
+}
+
+func TestConsoleHandler(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("jobs", `{"id":1}`, "plain")
+
+	rr := httptest.NewRecorder()
+	consoleHandler(rr, httptest.NewRequest(http.MethodGet, "/console?cmd="+url.QueryEscape("LRANGE jobs 0 -1"), nil))
+	body := rr.Body.String()
+	if rr.Code != http.StatusOK || !strings.Contains(body, "1) {\n     &#34;id&#34;: 1\n   }\n2) plain") {
+		t.Errorf("expected formatted LRANGE reply, got %d: %s", rr.Code, body)
+	}
+
+	rr = httptest.NewRecorder()
+	consoleHandler(rr, httptest.NewRequest(http.MethodGet, "/console?cmd=FLUSHALL", nil))
+	if rr.Code != http.StatusBadRequest || !strings.Contains(rr.Body.String(), "FLUSHALL is not allowed") {
+		t.Errorf("expected FLUSHALL to be refused, got %d", rr.Code)
+	}
+	if !mr.Exists("jobs") {
+		t.Error("expected data to be untouched")
+	}
+}
+
+func TestConsoleHandler_CrossOrigin(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("jobs", "a")
+	prev := writeEnabled
+	defer func() { writeEnabled = prev }()
+	writeEnabled = true
+
+	post := func(site string) int {
+		req := httptest.NewRequest(http.MethodPost, "/console", strings.NewReader("cmd=DEL+jobs"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("Sec-Fetch-Site", site)
+		rr := httptest.NewRecorder()
+		consoleHandler(rr, req)
+		return rr.Code
+	}
+
+	if code := post("cross-site"); code != http.StatusForbidden || !mr.Exists("jobs") {
+		t.Errorf("expected a write posted from another site to be refused, got %d", code)
+	}
+	if code := post("same-origin"); code != http.StatusOK || mr.Exists("jobs") {
+		t.Errorf("expected the console's own form to run the write, got %d", code)
+	}
+}
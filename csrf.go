@@ -0,0 +1,30 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strings"
+)
+
+// crossOrigin spots requests a browser sent on behalf of another site, from
+// their Sec-Fetch-Site or Origin header. RediScan has no authentication of
+// its own, so without it any page the operator visits could submit a form
+// that writes to Redis.
+var crossOrigin = http.NewCrossOriginProtection()
+
+// refuseCrossOrigin rejects a POST that did not come from RediScan's own
+// pages with 403 (a FORBIDDEN error under /api/), returning false. Requests
+// without either header, such as from curl, are allowed.
+func refuseCrossOrigin(w http.ResponseWriter, r *http.Request) bool {
+	if err := crossOrigin.Check(r); err != nil {
+		log.Printf("Refused cross-origin %s %s (Origin %q, Sec-Fetch-Site %q)", r.Method, r.URL.Path, r.Header.Get("Origin"), r.Header.Get("Sec-Fetch-Site"))
+		message := "Requests that change data must come from RediScan's own pages, not another site."
+		if strings.HasPrefix(r.URL.Path, "/api/") {
+			writeAPIError(w, http.StatusForbidden, apiErrForbidden, message)
+		} else {
+			renderErrorStatus(w, http.StatusForbidden, "Forbidden", message)
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"html/template"
+	"log"
+	"net/http"
+	"os"
+	"time"
+)
+
+// customCSSRoute is where the operator-supplied stylesheet is served from.
+const customCSSRoute = "/custom.css"
+
+// customCSS holds the contents of CUSTOM_CSS_PATH, nil when not configured.
+var customCSS []byte
+
+// customCSSPath is the path customCSS was loaded from.
+var customCSSPath string
+
+// customCSSVersion is a hash of the loaded stylesheet's contents. Pages link
+// to it with ?v=<version> so a changed file is fetched after a restart.
+var customCSSVersion string
+
+// customCSSETag identifies the loaded stylesheet's contents for revalidation.
+var customCSSETag string
+
+// loadCustomCSS reads the stylesheet at path so it can be served to every page.
+// A missing or unreadable file is logged and the default styling is used.
+func loadCustomCSS(path string) {
+	css, err := os.ReadFile(path)
+	if err != nil {
+		log.Printf("Warning: Could not read custom CSS from %s: %v", path, err)
+		return
+	}
+	customCSS = css
+	customCSSPath = path
+	sum := sha256.Sum256(css)
+	customCSSVersion = hex.EncodeToString(sum[:8])
+	customCSSETag = `"` + customCSSVersion + `"`
+	log.Printf("Loaded custom CSS from %s", path)
+}
+
+// customCSSLink returns the <link> tag for the custom stylesheet. Templates
+// place it after their default styles so its rules take precedence.
+func customCSSLink() template.HTML {
+	if customCSS == nil {
+		return ""
+	}
+	return template.HTML(`<link rel="stylesheet" href="` + customCSSRoute + `?v=` + customCSSVersion + `">`)
+}
+
+func customCSSHandler(w http.ResponseWriter, r *http.Request) {
+	if customCSS == nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/css; charset=utf-8")
+	w.Header().Set("ETag", customCSSETag)
+	if r.URL.Query().Get("v") == customCSSVersion {
+		setImmutableCacheHeaders(w)
+	} else {
+		setStaticCacheHeaders(w)
+	}
+	http.ServeContent(w, r, "custom.css", time.Time{}, bytes.NewReader(customCSS))
+}
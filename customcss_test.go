@@ -0,0 +1,59 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCustomCSS_LinkedAndServed(t *testing.T) {
+	prev := customCSS
+	defer func() { customCSS = prev }()
+
+	path := filepath.Join(t.TempDir(), "theme.css")
+	if err := os.WriteFile(path, []byte("body { color: red; }"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	loadCustomCSS(path)
+
+	rr := httptest.NewRecorder()
+	renderNotFound(rr, "missing")
+	body := rr.Body.String()
+	link := `<link rel="stylesheet" href="/custom.css?v=` + customCSSVersion + `">`
+	if !strings.Contains(body, link) {
+		t.Fatalf("expected custom stylesheet link in page, got: %s", body)
+	}
+	if strings.Index(body, link) < strings.Index(body, "</style>") {
+		t.Errorf("expected custom stylesheet to follow the default styles")
+	}
+
+	rr = httptest.NewRecorder()
+	customCSSHandler(rr, httptest.NewRequest(http.MethodGet, "/custom.css", nil))
+	if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/css") {
+		t.Errorf("expected text/css content type, got %q", ct)
+	}
+	if rr.Body.String() != "body { color: red; }" {
+		t.Errorf("unexpected stylesheet body: %s", rr.Body.String())
+	}
+}
+
+func TestCustomCSS_NotConfigured(t *testing.T) {
+	prev := customCSS
+	customCSS = nil
+	defer func() { customCSS = prev }()
+
+	rr := httptest.NewRecorder()
+	renderNotFound(rr, "missing")
+	if strings.Contains(rr.Body.String(), "/custom.css") {
+		t.Errorf("expected no custom stylesheet link when unconfigured")
+	}
+
+	rr = httptest.NewRecorder()
+	customCSSHandler(rr, httptest.NewRequest(http.MethodGet, "/custom.css", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 when no custom CSS is configured, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/its-the-vibe/RediScan/inspector"
+)
+
+const (
+	dashboardMaxKeys      = 200 // Most lists a dashboard tracks
+	dashboardPollInterval = 5   // Seconds between length refreshes
+)
+
+// lengthsAPIHandler returns the current lengths of lists matching pattern,
+// for the dashboard to poll.
+func lengthsAPIHandler(w http.ResponseWriter, r *http.Request) {
+	pattern := r.URL.Query().Get("pattern")
+	if pattern == "" {
+		writeAPIError(w, http.StatusBadRequest, apiErrInvalidParameter, "missing 'pattern' parameter")
+		return
+	}
+
+	// Ask for one extra so a capped result can be reported as truncated
+	lists, err := inspector.MatchingLists(ctx, redisClient, pattern, dashboardMaxKeys+1)
+	if err != nil {
+		log.Printf("Error scanning keys for %q: %v", pattern, err)
+		writeAPIError(w, http.StatusInternalServerError, apiErrInternal, err.Error())
+		return
+	}
+	truncated := len(lists) > dashboardMaxKeys
+	if truncated {
+		lists = lists[:dashboardMaxKeys]
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"lists":     summarizeLists(lists),
+		"truncated": truncated,
+	})
+}
+
+// dashboardHandler shows a live table of the lengths of lists matching a
+// pattern, refreshed from /api/lengths.
+func dashboardHandler(w http.ResponseWriter, r *http.Request) {
+	pattern := r.URL.Query().Get("pattern")
+	if pattern == "" {
+		renderNotFound(w, "Missing 'pattern' parameter")
+		return
+	}
+
+	tmplStr := `<!DOCTYPE html>
+<html>
+<head>
+    <title>Dashboard - RediScan</title>
+    <style>
+        body {
+            font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif;
+            max-width: 1200px;
+            margin: 0 auto;
+            padding: 20px;
+            background-color: #f5f5f5;
+        }
+        h1 a {
+            color: #333;
+            text-decoration: none;
+        }
+        h1 a:hover {
+            text-decoration: underline;
+        }
+        .dashboard {
+            background-color: white;
+            padding: 20px;
+            border-radius: 5px;
+            box-shadow: 0 2px 4px rgba(0,0,0,0.1);
+            margin-bottom: 20px;
+        }
+        .dashboard h2 {
+            margin-top: 0;
+            color: #333;
+        }
+        .status {
+            color: #666;
+            font-size: 14px;
+        }
+        table {
+            width: 100%;
+            border-collapse: collapse;
+        }
+        th, td {
+            text-align: left;
+            padding: 8px;
+            border-bottom: 1px solid #eee;
+        }
+        td a {
+            color: #2196F3;
+            text-decoration: none;
+            word-break: break-all;
+        }
+        td a:hover {
+            text-decoration: underline;
+        }
+        .length {
+            font-family: monospace;
+            width: 15%;
+        }
+        .change {
+            font-family: monospace;
+            width: 15%;
+        }
+        tr.growing .change {
+            color: #c62828;
+            font-weight: bold;
+        }
+        tr.growing {
+            background-color: #ffebee;
+        }
+        tr.shrinking .change {
+            color: #2e7d32;
+        }
+        .empty, .error {
+            color: #666;
+            font-style: italic;
+        }
+        .back-link {
+            color: #2196F3;
+            text-decoration: none;
+        }
+        #resume {
+            background-color: #2196F3;
+            color: white;
+            padding: 4px 12px;
+            border: none;
+            border-radius: 3px;
+            cursor: pointer;
+            margin-left: 8px;
+        }
+    </style>
+    {{customCSSLink}}
+    <script src="{{staticURL "idle.js"}}"></script>
+</head>
+<body>
+    {{persistenceBanner}}
+    <h1><a href="/">RediScan - Redis List Inspector</a></h1>
+    <div class="dashboard">
+        <h2>Lengths of lists matching <code>{{.Pattern}}</code></h2>
+        <p class="status"><span id="status">Loading&hellip;</span><button type="button" id="resume" hidden>Resume</button></p>
+        <table>
+            <thead><tr><th>Key</th><th class="length">Length</th><th class="change">Change</th></tr></thead>
+            <tbody id="lengths"></tbody>
+        </table>
+    </div>
+    <a href="/" class="back-link">← Back to Home</a>
+
+    <script>
+        const pattern = {{.Pattern}};
+        const pollInterval = {{.PollInterval}} * 1000;
+        const maxKeys = {{.MaxKeys}};
+        // Length of each list at the previous refresh, by key name
+        let previous = null;
+        const activity = idleTracker({{.IdleTimeout}} * 1000);
+
+        function render(body) {
+            const tbody = document.getElementById('lengths');
+            tbody.textContent = '';
+            const current = {};
+            body.lists.forEach(function(list) {
+                current[list.name] = list.size;
+                const change = previous && list.name in previous ? list.size - previous[list.name] : 0;
+
+                const row = document.createElement('tr');
+                if (change > 0) {
+                    row.className = 'growing';
+                } else if (change < 0) {
+                    row.className = 'shrinking';
+                }
+
+                const keyCell = document.createElement('td');
+                const link = document.createElement('a');
+                link.href = '{{lindexPath}}?key=' + list.query;
+                link.textContent = list.display;
+                keyCell.appendChild(link);
+                row.appendChild(keyCell);
+
+                const lengthCell = document.createElement('td');
+                lengthCell.className = 'length';
+                lengthCell.textContent = list.size;
+                row.appendChild(lengthCell);
+
+                const changeCell = document.createElement('td');
+                changeCell.className = 'change';
+                changeCell.textContent = change > 0 ? '+' + change : change < 0 ? String(change) : '';
+                row.appendChild(changeCell);
+
+                tbody.appendChild(row);
+            });
+            previous = current;
+
+            let status = body.lists.length === 0 ? 'No lists match this pattern.' : body.lists.length + ' list' + (body.lists.length === 1 ? '' : 's');
+            if (body.truncated) {
+                status += ' (showing the first ' + maxKeys + ' matches only)';
+            }
+            document.getElementById('status').textContent = status + ' — updated ' + new Date().toLocaleTimeString();
+        }
+
+        function refresh() {
+            fetch('/api/lengths?pattern=' + encodeURIComponent(pattern))
+                .then(function(response) {
+                    return response.json().then(function(body) {
+                        if (!response.ok) {
+                            throw new Error(body.error ? body.error.message : response.statusText);
+                        }
+                        return body;
+                    });
+                })
+                .then(render)
+                .catch(function(err) {
+                    document.getElementById('status').textContent = 'Could not refresh lengths: ' + err.message;
+                })
+                .finally(function() {
+                    if (activity.idle()) {
+                        // Nobody is watching, so stop querying Redis
+                        const status = document.getElementById('status');
+                        status.textContent += ' — paused after ' + activity.describe() + ' without activity';
+                        document.getElementById('resume').hidden = false;
+                        return;
+                    }
+                    setTimeout(refresh, pollInterval);
+                });
+        }
+        document.getElementById('resume').addEventListener('click', function() {
+            this.hidden = true;
+            activity.reset();
+            refresh();
+        });
+        refresh();
+    </script>
+</body>
+</html>`
+
+	tmpl, err := parseTemplate("dashboard", tmplStr)
+	if err != nil {
+		renderError(w, fmt.Sprintf("Template error: %v", err))
+		return
+	}
+
+	data := struct {
+		Pattern      string
+		PollInterval int
+		MaxKeys      int
+		IdleTimeout  int
+	}{
+		Pattern:      pattern,
+		PollInterval: dashboardPollInterval,
+		MaxKeys:      dashboardMaxKeys,
+		IdleTimeout:  int(liveIdleTimeout / time.Second),
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("Error rendering template: %v", err)
+	}
+}
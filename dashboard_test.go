@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLengthsAPIHandler(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("worker:1:queue", "a", "b")
+	mr.RPush("worker:2:queue", "a")
+	mr.RPush("other", "a")
+
+	rr := httptest.NewRecorder()
+	lengthsAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/lengths?pattern=worker:*:queue", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var resp struct {
+		Lists     []listSummary `json:"lists"`
+		Truncated bool          `json:"truncated"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	sizes := make(map[string]int64)
+	for _, l := range resp.Lists {
+		sizes[l.Name] = l.Size
+	}
+	if len(sizes) != 2 || sizes["worker:1:queue"] != 2 || sizes["worker:2:queue"] != 1 || resp.Truncated {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+
+	rr = httptest.NewRecorder()
+	lengthsAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/lengths", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 without a pattern, got %d", rr.Code)
+	}
+}
+
+func TestLengthsAPIHandler_Truncated(t *testing.T) {
+	mr := useMiniredis(t)
+	for i := 0; i <= dashboardMaxKeys; i++ {
+		mr.RPush(fmt.Sprintf("q:%d", i), "a")
+	}
+
+	rr := httptest.NewRecorder()
+	lengthsAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/lengths?pattern=q:*", nil))
+	if !strings.Contains(rr.Body.String(), `"truncated":true`) {
+		t.Errorf("expected the capped result to be flagged as truncated")
+	}
+}
+
+func TestDashboardHandler(t *testing.T) {
+	rr := httptest.NewRecorder()
+	dashboardHandler(rr, httptest.NewRequest(http.MethodGet, "/dashboard?pattern=worker:*", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), `const pattern = "worker:*";`) {
+		t.Errorf("expected the pattern to be passed to the polling script")
+	}
+	if !strings.Contains(rr.Body.String(), "idleTracker( 300  * 1000)") {
+		t.Errorf("expected polling to pause after LIVE_IDLE_TIMEOUT")
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// errDemoNotEmpty is returned by seedDemoData when the database already
+// holds keys, which are never overwritten.
+var errDemoNotEmpty = errors.New("database is not empty")
+
+// demoKeys are the keys seedDemoData writes.
+var demoKeys = []string{"demo:orders", "demo:events", "demo:logs", "demo:user:alice", "demo:tags"}
+
+// demoDatabase reports whether DEMO_SAFE_DB (setting) flags db, the database
+// RediScan is connected to, as safe to seed. Requiring the database to be
+// named keeps DEMO_MODE in a config copied to another environment from
+// seeding it.
+func demoDatabase(setting string, db int) (bool, error) {
+	if setting == "" {
+		return false, fmt.Errorf("DEMO_SAFE_DB is not set: name the database that is safe to seed, e.g. DEMO_SAFE_DB=%d", db)
+	}
+	named, err := strconv.Atoi(setting)
+	if err != nil || named < 0 {
+		return false, fmt.Errorf("invalid DEMO_SAFE_DB %q: expected a database number", setting)
+	}
+	return named == db, nil
+}
+
+// seedDemoData fills an empty database with a few example keys so the UI has
+// something to show (DEMO_MODE). It refuses to touch a database that already
+// holds any keys. The demo keys are watched from the size check until they
+// are written, so a real writer creating any of them in between makes the
+// seed fail rather than add to its data.
+func seedDemoData(ctx context.Context, client redis.UniversalClient) error {
+	err := client.Watch(ctx, func(tx *redis.Tx) error {
+		size, err := tx.DBSize(ctx).Result()
+		if err != nil {
+			return fmt.Errorf("checking database size: %w", err)
+		}
+		if size > 0 {
+			return fmt.Errorf("%w (%d keys)", errDemoNotEmpty, size)
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			queueDemoKeys(ctx, pipe)
+			return nil
+		})
+		return err
+	}, demoKeys...)
+	if errors.Is(err, redis.TxFailedErr) {
+		return fmt.Errorf("%w: demo keys were written while seeding", errDemoNotEmpty)
+	}
+	if err != nil && !errors.Is(err, errDemoNotEmpty) {
+		return fmt.Errorf("writing demo keys: %w", err)
+	}
+	return err
+}
+
+// queueDemoKeys queues the writes of the example keys.
+func queueDemoKeys(ctx context.Context, pipe redis.Pipeliner) {
+	pipe.RPush(ctx, "demo:orders",
+		`{"id": 1001, "customer": "alice", "status": "delivered", "total": 42.50, "items": [{"sku": "MUG-01", "qty": 2}]}`,
+		`{"id": 1002, "customer": "bob", "status": "shipped", "total": 15.00, "items": [{"sku": "TEE-03", "qty": 1}]}`,
+		`{"id": 1003, "customer": "carol", "status": "failed", "total": 99.99, "items": [{"sku": "HAT-07", "qty": 3}]}`,
+		`{"id": 1004, "customer": "alice", "status": "pending", "total": 7.25, "items": []}`,
+	)
+	// Newest first, as a consumer using LPUSH would leave it
+	pipe.LPush(ctx, "demo:events",
+		`{"type": "signup", "user": "dave", "at": "2024-01-01T09:00:00Z"}`,
+		`{"type": "login", "user": "alice", "at": "2024-01-01T09:05:00Z"}`,
+		`{"type": "logout", "user": "alice", "at": "2024-01-01T09:45:00Z"}`,
+	)
+	pipe.RPush(ctx, "demo:logs",
+		"INFO worker started",
+		"WARN retrying job 17",
+		"ERROR job 17 failed: timeout",
+		"42",
+		"true",
+	)
+	pipe.HSet(ctx, "demo:user:alice", "name", "Alice", "plan", "pro")
+	pipe.SAdd(ctx, "demo:tags", "new", "sale", "featured")
+}
+
+// runDemoMode seeds demo data when DEMO_SAFE_DB (safeDB) flags the database
+// in use, logging rather than failing so RediScan still starts against a
+// database that cannot be seeded.
+func runDemoMode(safeDB string, db int) {
+	if safe, err := demoDatabase(safeDB, db); err != nil {
+		log.Printf("Warning: DEMO_MODE: %v; not seeding example data", err)
+		return
+	} else if !safe {
+		log.Printf("Warning: DEMO_MODE: DEMO_SAFE_DB=%s is not database %d, which RediScan uses (REDIS_DB); not seeding example data", safeDB, db)
+		return
+	}
+	if err := seedDemoData(ctx, redisClient); err != nil {
+		log.Printf("Warning: DEMO_MODE: not seeding example data: %v", err)
+		return
+	}
+	log.Printf("DEMO_MODE: seeded example lists under demo:*")
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestSeedDemoData(t *testing.T) {
+	mr := useMiniredis(t)
+
+	if err := seedDemoData(context.Background(), redisClient); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if values, _ := mr.List("demo:orders"); len(values) != 4 {
+		t.Errorf("expected seeded orders, got %v", values)
+	}
+	if !mr.Exists("demo:user:alice") || !mr.Exists("demo:tags") {
+		t.Errorf("expected seeded hash and set")
+	}
+}
+
+func TestSeedDemoData_RefusesNonEmptyDatabase(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.Set("real:data", "keep me")
+
+	err := seedDemoData(context.Background(), redisClient)
+	if !errors.Is(err, errDemoNotEmpty) {
+		t.Fatalf("expected errDemoNotEmpty, got %v", err)
+	}
+	if n, _ := redisClient.DBSize(context.Background()).Result(); n != 1 {
+		t.Errorf("expected the database to be left untouched, got %d keys", n)
+	}
+	if mr.Exists("demo:orders") {
+		t.Errorf("expected no demo keys to be written")
+	}
+}
+
+// writeDuringDBSize is a go-redis hook that creates key straight after
+// DBSIZE replies, as a real writer racing the seed would.
+type writeDuringDBSize struct {
+	mr  *miniredis.Miniredis
+	key string
+}
+
+func (h writeDuringDBSize) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (h writeDuringDBSize) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		err := next(ctx, cmd)
+		if cmd.Name() == "dbsize" {
+			h.mr.RPush(h.key, "real job")
+		}
+		return err
+	}
+}
+
+func (h writeDuringDBSize) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func TestSeedDemoData_RacingWriter(t *testing.T) {
+	mr := useMiniredis(t)
+	redisClient.AddHook(writeDuringDBSize{mr: mr, key: "demo:orders"})
+
+	err := seedDemoData(context.Background(), redisClient)
+	if !errors.Is(err, errDemoNotEmpty) {
+		t.Fatalf("expected the seed to give up, got %v", err)
+	}
+	if values, _ := mr.List("demo:orders"); len(values) != 1 || mr.Exists("demo:tags") {
+		t.Errorf("expected nothing seeded alongside the real writer, got %v", values)
+	}
+}
+
+func TestDemoDatabase(t *testing.T) {
+	if safe, err := demoDatabase("15", 15); err != nil || !safe {
+		t.Errorf("expected the named database to be seeded, got %v, %v", safe, err)
+	}
+	if safe, err := demoDatabase("15", 0); err != nil || safe {
+		t.Errorf("expected another database to be left alone, got %v, %v", safe, err)
+	}
+	for _, setting := range []string{"", "true", "-1"} {
+		if _, err := demoDatabase(setting, 0); err == nil {
+			t.Errorf("%q: expected an error, as it names no database", setting)
+		}
+	}
+}
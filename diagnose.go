@@ -0,0 +1,22 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/its-the-vibe/RediScan/inspector"
+)
+
+// diagnoseAPIHandler reports what the raw element at key[index] appears to be
+// (UTF-8, JSON, gzip, base64, ...) for the result page's Diagnose panel.
+func diagnoseAPIHandler(w http.ResponseWriter, r *http.Request) {
+	key, index, value, ok := readAPIElement(w, r.URL.Query())
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"key":       key,
+		"index":     index,
+		"diagnosis": inspector.Diagnose(value),
+	})
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/its-the-vibe/RediScan/inspector"
+)
+
+func TestDiagnoseAPIHandler(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("blobs", "plain text", "7b226964223a317d")
+
+	rr := httptest.NewRecorder()
+	diagnoseAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/diagnose?key=blobs&index=-1", nil))
+	var body struct {
+		Index     int64               `json:"index"`
+		Diagnosis inspector.Diagnosis `json:"diagnosis"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("expected a JSON body, got %q: %v", rr.Body.String(), err)
+	}
+	if body.Index != 1 || body.Diagnosis.Bytes != 16 || strings.Join(body.Diagnosis.Suggested, ",") != "hex,json" {
+		t.Errorf("unexpected diagnosis: %+v", body)
+	}
+
+	errorTests := []struct {
+		target string
+		status int
+		code   string
+	}{
+		{"/api/diagnose?key=blobs", http.StatusBadRequest, apiErrInvalidIndex},
+		{"/api/diagnose?key=blobs&index=5", http.StatusNotFound, apiErrOutOfBounds},
+		{"/api/diagnose?key=missing&index=0", http.StatusNotFound, apiErrNotFound},
+		{"/api/diagnose?index=0", http.StatusBadRequest, apiErrInvalidParameter},
+	}
+	for _, tt := range errorTests {
+		rr := httptest.NewRecorder()
+		diagnoseAPIHandler(rr, httptest.NewRequest(http.MethodGet, tt.target, nil))
+		if rr.Code != tt.status || !strings.Contains(rr.Body.String(), tt.code) {
+			t.Errorf("%s: expected %d %s, got %d %s", tt.target, tt.status, tt.code, rr.Code, rr.Body.String())
+		}
+	}
+}
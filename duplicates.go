@@ -0,0 +1,56 @@
+package main
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/its-the-vibe/RediScan/inspector"
+)
+
+// duplicatesMaxLength is the longest list whose duplicates are looked up
+// (DUPLICATES_MAX_LENGTH). LPOS reads every element, so longer lists are
+// skipped; 0 skips every list.
+var duplicatesMaxLength int64 = 100000
+
+// duplicatesAPIHandler reports where the element at key[index] sits relative
+// to both ends of the list and the indices of every element with the same
+// value, to spot elements enqueued more than once.
+func duplicatesAPIHandler(w http.ResponseWriter, r *http.Request) {
+	key, index, value, ok := readAPIElement(w, r.URL.Query())
+	if !ok {
+		return
+	}
+	llen, err := inspector.ListLength(ctx, redisClient, key)
+	if err != nil {
+		writeAPIListError(w, key, err)
+		return
+	}
+
+	body := map[string]interface{}{
+		"key":        key,
+		"index":      index,
+		"length":     llen,
+		"from_head":  index,
+		"from_tail":  llen - 1 - index,
+		"max_length": duplicatesMaxLength,
+	}
+	if llen > duplicatesMaxLength {
+		body["skipped"] = true
+		writeJSON(w, http.StatusOK, body)
+		return
+	}
+
+	indices, err := inspector.Occurrences(ctx, redisClient, key, value)
+	if err != nil {
+		log.Printf("Error finding duplicates in %q: %v", key, err)
+		writeAPIError(w, http.StatusInternalServerError, apiErrInternal, err.Error())
+		return
+	}
+	if indices == nil {
+		// The element was removed after it was read
+		indices = []int64{}
+	}
+	body["indices"] = indices
+	body["count"] = len(indices)
+	writeJSON(w, http.StatusOK, body)
+}
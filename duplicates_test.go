@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDuplicatesAPIHandler(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("jobs", "a", "b", "a", "c")
+
+	rr := httptest.NewRecorder()
+	duplicatesAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/duplicates?key=jobs&index=2", nil))
+	var body struct {
+		FromHead int64   `json:"from_head"`
+		FromTail int64   `json:"from_tail"`
+		Indices  []int64 `json:"indices"`
+		Count    int     `json:"count"`
+		Skipped  bool    `json:"skipped"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("expected a JSON body, got %q: %v", rr.Body.String(), err)
+	}
+	if body.FromHead != 2 || body.FromTail != 1 {
+		t.Errorf("expected 2 from the head and 1 from the tail, got %+v", body)
+	}
+	if body.Count != 2 || len(body.Indices) != 2 || body.Indices[0] != 0 || body.Indices[1] != 2 {
+		t.Errorf("expected the value at indices 0 and 2, got %+v", body)
+	}
+
+	rr = httptest.NewRecorder()
+	duplicatesAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/duplicates?key=jobs&index=-1", nil))
+	if !strings.Contains(rr.Body.String(), `"indices":[3]`) {
+		t.Errorf("expected a unique element to be found once, got: %s", rr.Body.String())
+	}
+
+	prev := duplicatesMaxLength
+	defer func() { duplicatesMaxLength = prev }()
+	duplicatesMaxLength = 3
+	rr = httptest.NewRecorder()
+	duplicatesAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/duplicates?key=jobs&index=0", nil))
+	if body := rr.Body.String(); !strings.Contains(body, `"skipped":true`) || strings.Contains(body, `"indices"`) {
+		t.Errorf("expected lists over DUPLICATES_MAX_LENGTH to be skipped, got: %s", body)
+	}
+
+	rr = httptest.NewRecorder()
+	duplicatesAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/duplicates?key=missing&index=0", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for a missing key, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"container/list"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Kinds of problems recorded in the errored-keys report.
+const (
+	errorKindWrongType = "wrong type"
+	errorKindRedis     = "redis error"
+	errorKindTransform = "transform failed"
+)
+
+// erroredKey summarises the problems seen for one key and kind.
+type erroredKey struct {
+	Key       string
+	Kind      string
+	Message   string // Most recent message
+	Count     int
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+// errorReport is a bounded, in-memory record of keys that recently produced
+// errors. When full, the key that errored least recently is dropped.
+type errorReport struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // Most recently seen at the front
+	entries  map[string]*list.Element
+}
+
+// erroredKeys is the report shown on the admin errors page.
+var erroredKeys = newErrorReport(100)
+
+func newErrorReport(capacity int) *errorReport {
+	return &errorReport{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// record notes an error of the given kind for key.
+func (r *errorReport) record(key, kind, message string) {
+	now := time.Now()
+	id := kind + "\x00" + key
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if elem, ok := r.entries[id]; ok {
+		entry := elem.Value.(*erroredKey)
+		entry.Message = message
+		entry.Count++
+		entry.LastSeen = now
+		r.order.MoveToFront(elem)
+		return
+	}
+
+	r.entries[id] = r.order.PushFront(&erroredKey{
+		Key:       key,
+		Kind:      kind,
+		Message:   message,
+		Count:     1,
+		FirstSeen: now,
+		LastSeen:  now,
+	})
+	if r.order.Len() > r.capacity {
+		oldest := r.order.Back()
+		r.order.Remove(oldest)
+		entry := oldest.Value.(*erroredKey)
+		delete(r.entries, entry.Kind+"\x00"+entry.Key)
+	}
+}
+
+// snapshot returns a copy of the report, most recently seen first.
+func (r *errorReport) snapshot() []erroredKey {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := make([]erroredKey, 0, r.order.Len())
+	for elem := r.order.Front(); elem != nil; elem = elem.Next() {
+		entries = append(entries, *elem.Value.(*erroredKey))
+	}
+	return entries
+}
+
+// clear removes every entry from the report.
+func (r *errorReport) clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.order.Init()
+	r.entries = make(map[string]*list.Element)
+}
+
+func errorReportHandler(w http.ResponseWriter, r *http.Request) {
+	tmplStr := `<!DOCTYPE html>
+<html>
+<head>
+    <title>Errored Keys - RediScan</title>
+    <style>
+        body {
+            font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif;
+            max-width: 1200px;
+            margin: 0 auto;
+            padding: 20px;
+            background-color: #f5f5f5;
+        }
+        h1 a {
+            color: #333;
+            text-decoration: none;
+        }
+        h1 a:hover {
+            text-decoration: underline;
+        }
+        .report {
+            background-color: white;
+            padding: 20px;
+            border-radius: 5px;
+            box-shadow: 0 2px 4px rgba(0,0,0,0.1);
+        }
+        .report h2 {
+            margin-top: 0;
+            color: #333;
+        }
+        table {
+            width: 100%;
+            border-collapse: collapse;
+        }
+        th, td {
+            text-align: left;
+            padding: 8px;
+            border-bottom: 1px solid #eee;
+            vertical-align: top;
+        }
+        td a {
+            color: #2196F3;
+            text-decoration: none;
+        }
+        td a:hover {
+            text-decoration: underline;
+        }
+        .kind {
+            color: #d32f2f;
+            white-space: nowrap;
+        }
+        .message {
+            font-family: monospace;
+            word-break: break-word;
+        }
+        .empty {
+            color: #666;
+            font-style: italic;
+        }
+        button {
+            background-color: #d32f2f;
+            color: white;
+            padding: 8px 16px;
+            border: none;
+            border-radius: 3px;
+            cursor: pointer;
+            margin-top: 15px;
+        }
+        button:hover {
+            background-color: #b71c1c;
+        }
+    </style>
+    {{customCSSLink}}
+</head>
+<body>
+    {{persistenceBanner}}
+    <h1><a href="/">RediScan - Redis List Inspector</a></h1>
+    <div class="report">
+        <h2>Recently Errored Keys</h2>
+        {{if .Entries}}
+        <table>
+            <tr><th>Key</th><th>Problem</th><th>Count</th><th>Last Message</th><th>Last Seen</th></tr>
+            {{range .Entries}}
+            <tr>
+                <td><a href="{{lindexPath}}?key={{.Key | urlquery}}">{{displayKey .Key}}</a></td>
+                <td class="kind">{{.Kind}}</td>
+                <td>{{.Count}}</td>
+                <td class="message">{{.Message}}</td>
+                <td>{{formatTime .LastSeen}}</td>
+            </tr>
+            {{end}}
+        </table>
+        <form action="/admin/errors/clear" method="post">
+            <button type="submit">Clear Report</button>
+        </form>
+        {{else}}
+        <p class="empty">No errors recorded.</p>
+        {{end}}
+    </div>
+</body>
+</html>`
+
+	tmpl, err := parseTemplate("errors", tmplStr)
+	if err != nil {
+		renderError(w, fmt.Sprintf("Template error: %v", err))
+		return
+	}
+
+	data := struct {
+		Entries []erroredKey
+	}{
+		Entries: erroredKeys.snapshot(),
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("Error rendering template: %v", err)
+	}
+}
+
+func clearErrorReportHandler(w http.ResponseWriter, r *http.Request) {
+	if !refuseCrossOrigin(w, r) {
+		return
+	}
+	erroredKeys.clear()
+	http.Redirect(w, r, "/admin/errors", http.StatusSeeOther)
+}
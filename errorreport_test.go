@@ -0,0 +1,81 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestErrorReport_CountsAndEvicts(t *testing.T) {
+	report := newErrorReport(2)
+	report.record("a", errorKindWrongType, "expected list, found hash")
+	report.record("a", errorKindWrongType, "expected list, found set")
+	report.record("b", errorKindRedis, "timeout")
+	report.record("c", errorKindRedis, "timeout")
+
+	entries := report.snapshot()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries after eviction, got %d", len(entries))
+	}
+	if entries[0].Key != "c" || entries[1].Key != "b" {
+		t.Errorf("expected most recent first and 'a' evicted, got %+v", entries)
+	}
+
+	report = newErrorReport(10)
+	report.record("a", errorKindWrongType, "first")
+	report.record("a", errorKindWrongType, "second")
+	entries = report.snapshot()
+	if len(entries) != 1 || entries[0].Count != 2 || entries[0].Message != "second" {
+		t.Errorf("expected one entry with count 2 and latest message, got %+v", entries)
+	}
+
+	report.clear()
+	if len(report.snapshot()) != 0 {
+		t.Errorf("expected empty report after clear")
+	}
+}
+
+func TestErrorReport_RecordsWrongTypeFromLindex(t *testing.T) {
+	mr := useMiniredis(t)
+	prev := erroredKeys
+	erroredKeys = newErrorReport(10)
+	defer func() { erroredKeys = prev }()
+
+	mr.HSet("myhash", "field", "value")
+	lindexHandler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/lindex?key=myhash", nil))
+
+	rr := httptest.NewRecorder()
+	errorReportHandler(rr, httptest.NewRequest(http.MethodGet, "/admin/errors", nil))
+	body := rr.Body.String()
+	if !strings.Contains(body, "myhash") || !strings.Contains(body, errorKindWrongType) {
+		t.Errorf("expected myhash wrong-type entry in report, got: %s", body)
+	}
+
+	rr = httptest.NewRecorder()
+	clearErrorReportHandler(rr, httptest.NewRequest(http.MethodPost, "/admin/errors/clear", nil))
+	if rr.Code != http.StatusSeeOther {
+		t.Errorf("expected redirect after clearing, got %d", rr.Code)
+	}
+	if len(erroredKeys.snapshot()) != 0 {
+		t.Errorf("expected report to be cleared")
+	}
+}
+
+func TestClearErrorReportHandler_CrossOrigin(t *testing.T) {
+	prev := erroredKeys
+	erroredKeys = newErrorReport(10)
+	defer func() { erroredKeys = prev }()
+	erroredKeys.record("a", errorKindRedis, "timeout")
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/errors/clear", nil)
+	req.Header.Set("Sec-Fetch-Site", "cross-site")
+	rr := httptest.NewRecorder()
+	clearErrorReportHandler(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("expected a cross-site clear to be refused, got %d", rr.Code)
+	}
+	if len(erroredKeys.snapshot()) != 1 {
+		t.Errorf("expected the report to be kept")
+	}
+}
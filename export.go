@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/its-the-vibe/RediScan/inspector"
+)
+
+// keyExportEntry is one key in a JSON export. As in /api/keys, the display
+// and URL-encoded forms of the name are included, as JSON cannot carry every
+// key faithfully.
+type keyExportEntry struct {
+	Name    string `json:"name"`
+	Display string `json:"display"`
+	Query   string `json:"query"`
+	Type    string `json:"type"`
+	Size    int64  `json:"size"`
+}
+
+// keyExportColumns is the header row of a CSV export.
+var keyExportColumns = []string{"name", "type", "size", "display", "query"}
+
+// keysExportHandler streams every key matching pattern, with its type and
+// size, as a CSV or JSON download. Unlike the index page it is not limited
+// by MAX_LISTS: the whole keyspace is scanned, a batch at a time, and each
+// batch is written out before the next is read. As an export can run for
+// minutes, a Redis slot is held only while each batch is read, so other
+// requests are served in between.
+func keysExportHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	pattern := query.Get("pattern")
+	if pattern == "" {
+		pattern = "*"
+	}
+	format := query.Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" {
+		writeAPIError(w, http.StatusBadRequest, apiErrInvalidParameter, "format must be csv or json")
+		return
+	}
+
+	if !acquireRedisSlot(r.Context(), redisSlotWait) {
+		if r.Context().Err() == nil {
+			renderRedisBusy(w, r)
+		}
+		return
+	}
+	holding := true
+	defer func() {
+		if holding {
+			releaseRedisSlot()
+		}
+	}()
+
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.Header().Set("Content-Disposition", `attachment; filename="rediscan-keys.`+format+`"`)
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	// The status is sent with the first batch, so a scan failing before then
+	// still gets an error response
+	rc := http.NewResponseController(w)
+	started := false
+	csvWriter := csv.NewWriter(w)
+	written := 0
+	failed, err := inspector.WalkKeyspace(r.Context(), redisClient, pattern, func(keys []inspector.KeyInfo) error {
+		sizes := inspector.KeySizes(r.Context(), redisClient, keys)
+		if !started {
+			started = true
+			if format == "csv" {
+				csvWriter.Write(keyExportColumns)
+			} else if _, err := w.Write([]byte("[")); err != nil {
+				return err
+			}
+		}
+		for i, key := range keys {
+			if format == "csv" {
+				csvWriter.Write([]string{key.Name, key.Type, strconv.FormatInt(sizes[i], 10), displayKey(key.Name), url.QueryEscape(key.Name)})
+				continue
+			}
+			entry, err := json.Marshal(keyExportEntry{
+				Name:    key.Name,
+				Display: displayKey(key.Name),
+				Query:   url.QueryEscape(key.Name),
+				Type:    key.Type,
+				Size:    sizes[i],
+			})
+			if err != nil {
+				return err
+			}
+			separator := ",\n"
+			if written == 0 {
+				separator = "\n"
+			}
+			if _, err := w.Write(append([]byte(separator), entry...)); err != nil {
+				return err
+			}
+			written++
+		}
+		csvWriter.Flush()
+		if err := csvWriter.Error(); err != nil {
+			return err
+		}
+		rc.Flush()
+
+		// Let waiting requests in before reading the next batch
+		releaseRedisSlot()
+		if holding = acquireRedisSlot(r.Context(), 0); !holding {
+			return r.Context().Err()
+		}
+		return nil
+	})
+	for _, shard := range failed {
+		log.Printf("Warning: Key export of %q is missing the keys of cluster shard %s: %v", pattern, shard.Addr, shard.Err)
+	}
+	if err != nil && !started {
+		log.Printf("Error exporting keys matching %q: %v", pattern, err)
+		w.Header().Del("Content-Disposition")
+		writeAPIError(w, http.StatusInternalServerError, apiErrInternal, err.Error())
+		return
+	}
+	if err != nil {
+		// Too late for an error status: the download is left cut short, which
+		// for JSON also makes it invalid
+		log.Printf("Error exporting keys matching %q, export cut short: %v", pattern, err)
+		return
+	}
+
+	if format == "csv" {
+		if !started {
+			csvWriter.Write(keyExportColumns)
+		}
+		csvWriter.Flush()
+	} else if !started {
+		w.Write([]byte("[]\n"))
+	} else {
+		w.Write([]byte("\n]\n"))
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestKeysExportHandler(t *testing.T) {
+	mr := useMiniredis(t)
+	prev := maxLists
+	defer func() { maxLists = prev }()
+	maxLists = 5
+	for i := 0; i < 30; i++ {
+		mr.RPush(fmt.Sprintf("jobs:%d", i), "a", "b")
+	}
+	mr.HSet("jobs:meta", "owner", "ops")
+	mr.Set("other", "value")
+
+	// CSV is the default, covering every match despite MAX_LISTS
+	rr := httptest.NewRecorder()
+	keysExportHandler(rr, httptest.NewRequest(http.MethodGet, "/api/keys/export?pattern=jobs:*", nil))
+	if rr.Code != http.StatusOK || !strings.HasPrefix(rr.Header().Get("Content-Type"), "text/csv") {
+		t.Fatalf("expected a CSV download, got %d %q", rr.Code, rr.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(rr.Header().Get("Content-Disposition"), "rediscan-keys.csv") {
+		t.Errorf("expected a download file name, got %q", rr.Header().Get("Content-Disposition"))
+	}
+	records, err := csv.NewReader(rr.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 32 || strings.Join(records[0], ",") != "name,type,size,display,query" {
+		t.Fatalf("expected a header and 31 keys, got %d records", len(records))
+	}
+	for _, record := range records[1:] {
+		if record[0] == "jobs:meta" && (record[1] != "hash" || record[2] != "1") {
+			t.Errorf("unexpected hash record %v", record)
+		}
+		if record[0] == "jobs:3" && (record[1] != "list" || record[2] != "2") {
+			t.Errorf("unexpected list record %v", record)
+		}
+		if record[0] == "other" {
+			t.Errorf("expected keys outside the pattern to be left out")
+		}
+	}
+
+	rr = httptest.NewRecorder()
+	keysExportHandler(rr, httptest.NewRequest(http.MethodGet, "/api/keys/export?format=json", nil))
+	var entries []keyExportEntry
+	if err := json.Unmarshal(rr.Body.Bytes(), &entries); err != nil {
+		t.Fatalf("expected a JSON array, got %q: %v", rr.Body.String(), err)
+	}
+	if len(entries) != 32 {
+		t.Errorf("expected every key with no pattern, got %d", len(entries))
+	}
+
+	// Binary names are exported in the escaped and URL-encoded forms too
+	mr.RPush("bin:\xff key", "a")
+	rr = httptest.NewRecorder()
+	keysExportHandler(rr, httptest.NewRequest(http.MethodGet, "/api/keys/export?pattern=bin:*&format=json", nil))
+	entries = nil
+	if err := json.Unmarshal(rr.Body.Bytes(), &entries); err != nil || len(entries) != 1 {
+		t.Fatalf("expected one binary key, got %q: %v", rr.Body.String(), err)
+	}
+	if entries[0].Display != `bin:\xff key` || entries[0].Query != "bin%3A%FF+key" {
+		t.Errorf("expected the escaped and URL-encoded key, got %+v", entries[0])
+	}
+	rr = httptest.NewRecorder()
+	keysExportHandler(rr, httptest.NewRequest(http.MethodGet, "/api/keys/export?pattern=bin:*", nil))
+	if !strings.Contains(rr.Body.String(), `bin:\xff key,bin%3A%FF+key`) {
+		t.Errorf("expected the escaped and URL-encoded key in the CSV, got %q", rr.Body.String())
+	}
+	mr.Del("bin:\xff key")
+
+	rr = httptest.NewRecorder()
+	keysExportHandler(rr, httptest.NewRequest(http.MethodGet, "/api/keys/export?pattern=none:*&format=json", nil))
+	if body := strings.TrimSpace(rr.Body.String()); body != "[]" {
+		t.Errorf("expected an empty array when nothing matches, got %q", body)
+	}
+
+	// A Redis slot is held only while reading, and refused when none is free
+	prevSlots := redisSlots
+	defer func() { redisSlots = prevSlots }()
+	redisSlots = make(chan struct{}, 1)
+	rr = httptest.NewRecorder()
+	keysExportHandler(rr, httptest.NewRequest(http.MethodGet, "/api/keys/export", nil))
+	if rr.Code != http.StatusOK || len(redisSlots) != 0 {
+		t.Errorf("expected the export to release its slot, got %d with %d slots taken", rr.Code, len(redisSlots))
+	}
+	redisSlots <- struct{}{}
+	rr = httptest.NewRecorder()
+	keysExportHandler(rr, httptest.NewRequest(http.MethodGet, "/api/keys/export", nil))
+	if rr.Code != http.StatusServiceUnavailable || !strings.Contains(rr.Body.String(), apiErrBusy) {
+		t.Errorf("expected 503 when every slot is taken, got %d %s", rr.Code, rr.Body.String())
+	}
+	redisSlots = prevSlots
+
+	rr = httptest.NewRecorder()
+	keysExportHandler(rr, httptest.NewRequest(http.MethodGet, "/api/keys/export?format=xml", nil))
+	if rr.Code != http.StatusBadRequest || !strings.Contains(rr.Body.String(), apiErrInvalidParameter) {
+		t.Errorf("expected an unknown format to be rejected, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
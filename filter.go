@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// valueFilterArgs is the command every raw value is piped through before it
+// is rendered, parsed from VALUE_FILTER_CMD. Nil disables the filter.
+var valueFilterArgs []string
+
+var (
+	valueFilterTimeout        = 2 * time.Second  // Longest a filter run may take (VALUE_FILTER_TIMEOUT)
+	valueFilterMaxBytes int64 = 1 << 20          // Largest filter output accepted (VALUE_FILTER_MAX_BYTES)
+	valueFilterBudget         = 10 * time.Second // Total time the filter runs of one page may take (VALUE_FILTER_BUDGET)
+)
+
+// filterCacheMaxBytes bounds the filter outputs kept by filterCache.
+const filterCacheMaxBytes = 32 << 20
+
+// errFilterBudgetSpent is returned by filterValue for elements left once a
+// page's VALUE_FILTER_BUDGET is spent; they are shown unfiltered.
+var errFilterBudgetSpent = errors.New("skipped, as this page's VALUE_FILTER_BUDGET is spent")
+
+// filterRun bounds the filter runs made while rendering one page to
+// valueFilterBudget in total. A nil *filterRun has no budget.
+type filterRun struct {
+	deadline time.Time
+	skipped  int // Elements shown unfiltered because the budget was spent
+}
+
+// newFilterRun starts the budget of one page's filter runs.
+func newFilterRun() *filterRun {
+	return &filterRun{deadline: time.Now().Add(valueFilterBudget)}
+}
+
+// filterCache is a bounded LRU cache of filter outputs keyed by a hash of the
+// command and the raw value, so an element is only piped through the command
+// once however often it is shown. Failures are not cached.
+var filterCache = &outputCache{order: list.New(), entries: make(map[[sha256.Size]byte]*list.Element), maxBytes: filterCacheMaxBytes}
+
+// outputCache holds up to maxBytes of command outputs, evicting the least
+// recently used first.
+type outputCache struct {
+	mu       sync.Mutex
+	order    *list.List
+	entries  map[[sha256.Size]byte]*list.Element
+	bytes    int
+	maxBytes int
+}
+
+type outputCacheEntry struct {
+	key    [sha256.Size]byte
+	output string
+}
+
+// filterCacheKey identifies value piped through the current filter command.
+func filterCacheKey(value string) [sha256.Size]byte {
+	h := sha256.New()
+	for _, arg := range valueFilterArgs {
+		h.Write([]byte(arg))
+		h.Write([]byte{0})
+	}
+	h.Write([]byte(value))
+	var key [sha256.Size]byte
+	h.Sum(key[:0])
+	return key
+}
+
+func (c *outputCache) get(key [sha256.Size]byte) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*outputCacheEntry).output, true
+}
+
+func (c *outputCache) put(key [sha256.Size]byte, output string) {
+	if len(output) > c.maxBytes {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; ok {
+		return
+	}
+	c.entries[key] = c.order.PushFront(&outputCacheEntry{key: key, output: output})
+	c.bytes += len(output)
+	for c.bytes > c.maxBytes {
+		oldest := c.order.Back()
+		entry := oldest.Value.(*outputCacheEntry)
+		c.order.Remove(oldest)
+		delete(c.entries, entry.key)
+		c.bytes -= len(entry.output)
+	}
+}
+
+// limitedBuffer collects up to max bytes. Anything beyond is discarded, and
+// recorded in overflowed, so a chatty command is never blocked writing. The
+// buffer is a field rather than embedded so its ReadFrom cannot bypass Write.
+type limitedBuffer struct {
+	buf        bytes.Buffer
+	max        int64
+	overflowed bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - int64(b.buf.Len()); int64(len(p)) > room {
+		b.buf.Write(p[:max(room, 0)])
+		b.overflowed = true
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *limitedBuffer) String() string {
+	return b.buf.String()
+}
+
+// parseValueFilter parses VALUE_FILTER_CMD, a command line quoted as in a
+// shell or redis-cli. The command is run directly, not through a shell.
+func parseValueFilter(config string) ([]string, error) {
+	args, err := parseCommandLine(config)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		return nil, errors.New("no command given")
+	}
+	if _, err := exec.LookPath(args[0]); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+// filterValue runs the VALUE_FILTER_CMD subprocess with value on its stdin
+// and returns its stdout, or the output cached from an earlier run on the
+// same value. The process is killed after valueFilterTimeout or when run's
+// budget is spent, after which no more are started. Output larger than
+// valueFilterMaxBytes is rejected, and a non-zero exit is an error that
+// includes the start of its stderr.
+func filterValue(value string, run *filterRun) (string, error) {
+	key := filterCacheKey(value)
+	if output, ok := filterCache.get(key); ok {
+		return output, nil
+	}
+
+	timeout := valueFilterTimeout
+	if run != nil {
+		remaining := time.Until(run.deadline)
+		if remaining <= 0 {
+			run.skipped++
+			return "", errFilterBudgetSpent
+		}
+		timeout = min(timeout, remaining)
+	}
+	filterCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(filterCtx, valueFilterArgs[0], valueFilterArgs[1:]...)
+	cmd.Stdin = strings.NewReader(value)
+	stdout := &limitedBuffer{max: valueFilterMaxBytes}
+	stderr := &limitedBuffer{max: 1024}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	switch {
+	case filterCtx.Err() == context.DeadlineExceeded:
+		if timeout < valueFilterTimeout {
+			run.skipped++
+			return "", errFilterBudgetSpent
+		}
+		return "", fmt.Errorf("timed out after %s", valueFilterTimeout)
+	case err != nil:
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("%v: %s", err, message)
+		}
+		return "", err
+	case stdout.overflowed:
+		return "", fmt.Errorf("output is larger than VALUE_FILTER_MAX_BYTES (%d bytes)", valueFilterMaxBytes)
+	}
+	filterCache.put(key, stdout.String())
+	return stdout.String(), nil
+}
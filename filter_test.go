@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseValueFilter(t *testing.T) {
+	args, err := parseValueFilter(`tr 'a-z' "A-Z"`)
+	if err != nil || len(args) != 3 || args[1] != "a-z" || args[2] != "A-Z" {
+		t.Errorf("expected the quoted arguments to be split, got %q (%v)", args, err)
+	}
+	if _, err := parseValueFilter("no-such-command-rediscan"); err == nil {
+		t.Error("expected an error for a command that is not installed")
+	}
+	if _, err := parseValueFilter("  "); err == nil {
+		t.Error("expected an error for an empty command")
+	}
+}
+
+func TestRenderValue_Filter(t *testing.T) {
+	prevArgs, prevTimeout, prevMax := valueFilterArgs, valueFilterTimeout, valueFilterMaxBytes
+	defer func() { valueFilterArgs, valueFilterTimeout, valueFilterMaxBytes = prevArgs, prevTimeout, prevMax }()
+
+	valueFilterArgs = []string{"tr", "a-z", "A-Z"}
+	if got := renderValue("jobs", "hello"); got != "HELLO" {
+		t.Errorf("expected the filtered value, got %q", got)
+	}
+
+	// Filter output is pretty-printed like any other value
+	valueFilterArgs = []string{"sh", "-c", `echo '{"id":1}'`}
+	if got := renderValue("jobs", "ignored"); !strings.Contains(got, "\n  \"id\": 1") {
+		t.Errorf("expected pretty-printed JSON from the filter, got %q", got)
+	}
+
+	valueFilterArgs = []string{"sh", "-c", "echo broken >&2; exit 3"}
+	if got := renderValue("jobs", "raw"); !strings.Contains(got, "[Filter sh: exit status 3: broken]") || !strings.HasSuffix(got, "raw") {
+		t.Errorf("expected the error above the raw value, got %q", got)
+	}
+
+	valueFilterArgs = []string{"sleep", "5"}
+	valueFilterTimeout = 50 * time.Millisecond
+	if got := renderValue("jobs", "raw"); !strings.Contains(got, "timed out") {
+		t.Errorf("expected a timeout, got %q", got)
+	}
+
+	valueFilterArgs = []string{"cat"}
+	valueFilterMaxBytes = 4
+	if got := renderValue("jobs", "too long"); !strings.Contains(got, "VALUE_FILTER_MAX_BYTES") {
+		t.Errorf("expected oversized output to be rejected, got %q", got)
+	}
+}
+
+func TestFilterValue_CacheAndBudget(t *testing.T) {
+	prevArgs, prevBudget := valueFilterArgs, valueFilterBudget
+	defer func() { valueFilterArgs, valueFilterBudget = prevArgs, prevBudget }()
+
+	// Each run appends to a file, counting how often the command started
+	runs := filepath.Join(t.TempDir(), "runs")
+	valueFilterArgs = []string{"sh", "-c", "echo run >> " + runs + "; cat"}
+	for i := 0; i < 3; i++ {
+		if got, err := filterValue("cached", nil); err != nil || got != "cached" {
+			t.Fatalf("unexpected result %q (%v)", got, err)
+		}
+	}
+	if data, _ := os.ReadFile(runs); strings.Count(string(data), "run") != 1 {
+		t.Errorf("expected one run for a repeated value, got %d", strings.Count(string(data), "run"))
+	}
+
+	// Once a page's budget is spent, no more runs start
+	valueFilterArgs = []string{"sh", "-c", "sleep 0.2; cat"}
+	valueFilterBudget = 100 * time.Millisecond
+	run := newFilterRun()
+	for _, value := range []string{"a", "b", "c"} {
+		got := renderValueWith("jobs", value, nil, run)
+		if !strings.Contains(got, "VALUE_FILTER_BUDGET is spent") || !strings.HasSuffix(got, value) {
+			t.Errorf("expected %q shown unfiltered once the budget is spent, got %q", value, got)
+		}
+	}
+	if run.skipped != 3 {
+		t.Errorf("expected every element skipped, got %d", run.skipped)
+	}
+}
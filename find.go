@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/its-the-vibe/RediScan/inspector"
+)
+
+// findMaxIndexes caps how many field indexes are held in memory at once; the
+// oldest is dropped to make room for a new one.
+const findMaxIndexes = 20
+
+// findIndexTTL is how long a field index is reused before the list is
+// scanned again, configured by FIND_INDEX_TTL (0 disables reuse).
+var findIndexTTL = 5 * time.Minute
+
+// fieldIndex maps the values of one field to their first index in a list, as
+// of when the list had llen elements.
+type fieldIndex struct {
+	llen      int64
+	built     time.Time
+	positions map[string]int64
+}
+
+// fieldIndexKey identifies a field index by list and field path.
+type fieldIndexKey struct {
+	key, field string
+}
+
+// fieldIndexCache holds the indexes built by /api/find.
+type fieldIndexCache struct {
+	mu      sync.Mutex
+	indexes map[fieldIndexKey]*fieldIndex
+}
+
+var findIndexes = &fieldIndexCache{indexes: make(map[fieldIndexKey]*fieldIndex)}
+
+// get returns the index for key and field if it was built within the TTL
+// and the list still has llen elements.
+func (c *fieldIndexCache) get(key, field string, llen int64) *fieldIndex {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	index := c.indexes[fieldIndexKey{key, field}]
+	if index == nil || index.llen != llen || time.Since(index.built) >= findIndexTTL {
+		return nil
+	}
+	return index
+}
+
+// put stores an index, dropping the oldest when the cache is full.
+func (c *fieldIndexCache) put(key, field string, index *fieldIndex) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.indexes[fieldIndexKey{key, field}]; !ok && len(c.indexes) >= findMaxIndexes {
+		var oldest fieldIndexKey
+		var oldestBuilt time.Time
+		for k, v := range c.indexes {
+			if oldestBuilt.IsZero() || v.built.Before(oldestBuilt) {
+				oldest, oldestBuilt = k, v.built
+			}
+		}
+		delete(c.indexes, oldest)
+	}
+	c.indexes[fieldIndexKey{key, field}] = index
+}
+
+// findAPIHandler returns the index of the first element of a list whose JSON
+// field has the given value. The first lookup scans the list and remembers
+// every value of the field, so repeated lookups on the same list answer
+// without reading it again until its length changes or the index expires.
+func findAPIHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	key, field := query.Get("key"), query.Get("field")
+	if key == "" || field == "" {
+		writeAPIError(w, http.StatusBadRequest, apiErrInvalidParameter, "missing 'key' or 'field' parameter")
+		return
+	}
+	if !query.Has("value") {
+		writeAPIError(w, http.StatusBadRequest, apiErrInvalidParameter, "missing 'value' parameter")
+		return
+	}
+	value := query.Get("value")
+
+	llen, err := inspector.ListLength(ctx, redisClient, key)
+	if err != nil {
+		writeAPIListError(w, key, err)
+		return
+	}
+
+	index := findIndexes.get(key, field, llen)
+	cached := index != nil
+	if cached {
+		// An element may have been replaced without the length changing
+		// (e.g. LPUSH then RPOP), so confirm the hit before trusting it
+		if position, ok := index.positions[value]; ok {
+			element, err := redisClient.LIndex(ctx, key, position).Result()
+			if text, ok := inspector.FieldText(element, field); err != nil || !ok || text != value {
+				cached, index = false, nil
+			}
+		}
+	}
+	if index == nil {
+		positions, total, err := inspector.IndexField(ctx, redisClient, key, field)
+		if err != nil {
+			log.Printf("Error indexing %q by %s: %v", key, field, err)
+			writeAPIError(w, http.StatusInternalServerError, apiErrInternal, err.Error())
+			return
+		}
+		index = &fieldIndex{llen: total, built: time.Now(), positions: positions}
+		if findIndexTTL > 0 {
+			findIndexes.put(key, field, index)
+		}
+	}
+
+	position, ok := index.positions[value]
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, apiErrNoMatch, fmt.Sprintf("no element of '%s' has %s=%s", displayKey(key), field, value))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"key":    key,
+		"field":  field,
+		"value":  value,
+		"index":  position,
+		"cached": cached,
+	})
+}
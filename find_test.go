@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFindAPIHandler(t *testing.T) {
+	mr := useMiniredis(t)
+	prev := findIndexes
+	defer func() { findIndexes = prev }()
+	findIndexes = &fieldIndexCache{indexes: make(map[fieldIndexKey]*fieldIndex)}
+	mr.RPush("orders", `{"id":"a"}`, `{"id":"b"}`, `{"id":42}`)
+
+	find := func(query string) (int, map[string]interface{}) {
+		rr := httptest.NewRecorder()
+		findAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/find?"+query, nil))
+		var body map[string]interface{}
+		json.Unmarshal(rr.Body.Bytes(), &body)
+		return rr.Code, body
+	}
+
+	code, body := find("key=orders&field=id&value=b")
+	if code != http.StatusOK || body["index"] != float64(1) || body["cached"] != false {
+		t.Fatalf("expected index 1 from a fresh scan, got %d %v", code, body)
+	}
+	code, body = find("key=orders&field=id&value=42")
+	if code != http.StatusOK || body["index"] != float64(2) || body["cached"] != true {
+		t.Errorf("expected index 2 from the cached index, got %d %v", code, body)
+	}
+
+	// A change in length invalidates the index
+	mr.Lpush("orders", `{"id":"z"}`)
+	code, body = find("key=orders&field=id&value=b")
+	if code != http.StatusOK || body["index"] != float64(2) || body["cached"] != false {
+		t.Errorf("expected a rebuilt index after LPUSH, got %d %v", code, body)
+	}
+
+	// A hit on an element that moved at the same length is not trusted
+	mr.Lpush("orders", `{"id":"y"}`)
+	mr.RPop("orders")
+	code, body = find("key=orders&field=id&value=b")
+	if code != http.StatusOK || body["index"] != float64(3) || body["cached"] != false {
+		t.Errorf("expected a rebuilt index after the list shifted, got %d %v", code, body)
+	}
+
+	if code, _ := find("key=orders&field=id&value=nope"); code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown value, got %d", code)
+	}
+	if code, _ := find("key=orders&field=id"); code != http.StatusBadRequest {
+		t.Errorf("expected 400 without a value, got %d", code)
+	}
+}
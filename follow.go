@@ -0,0 +1,284 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/its-the-vibe/RediScan/inspector"
+)
+
+const (
+	followBatchSize       = 500  // Most new elements returned per poll
+	followPollInterval    = 2    // Seconds between polls for new elements
+	followDefaultMaxLines = 1000 // Elements kept on the follow page before the oldest are dropped
+)
+
+// liveIdleTimeout is how long the follow page and the dashboard keep polling
+// without user activity before pausing (LIVE_IDLE_TIMEOUT), so a forgotten
+// tab stops querying Redis. 0 polls for as long as the page is open.
+var liveIdleTimeout = 5 * time.Minute
+
+// tailAPIHandler returns the elements pushed to a list since the caller last
+// saw it had `seen` elements, for the follow page to poll. Without seen, only
+// the newest element is returned.
+func tailAPIHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	key := query.Get("key")
+	if key == "" {
+		writeAPIError(w, http.StatusBadRequest, apiErrInvalidParameter, "missing 'key' parameter")
+		return
+	}
+	seen := int64(-1)
+	if seenStr := query.Get("seen"); seenStr != "" {
+		n, err := strconv.ParseInt(seenStr, 10, 64)
+		if err != nil || n < 0 {
+			writeAPIError(w, http.StatusBadRequest, apiErrInvalidParameter, "invalid 'seen' parameter: expected a non-negative integer")
+			return
+		}
+		seen = n
+	}
+
+	headIsNewest := inspector.MatchDirection(directionRules, key).HeadIsNewest()
+	result, err := inspector.Tail(ctx, redisClient, key, seen, headIsNewest, followBatchSize)
+	var wrongType *inspector.WrongTypeError
+	if errors.As(err, &wrongType) {
+		writeAPIError(w, http.StatusConflict, apiErrWrongType, fmt.Sprintf("key '%s' is not a list (type: %s)", displayKey(key), wrongType.Type))
+		return
+	}
+	if err != nil {
+		log.Printf("Error tailing %q: %v", key, err)
+		writeAPIError(w, http.StatusInternalServerError, apiErrInternal, err.Error())
+		return
+	}
+
+	values := result.Values
+	if values == nil {
+		values = []string{}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"length":  result.Length,
+		"values":  values,
+		"skipped": result.Skipped,
+		"reset":   result.Reset,
+	})
+}
+
+// followHandler shows a list's newest element and appends elements as they
+// are pushed, like tail -f, polling /api/tail.
+func followHandler(w http.ResponseWriter, r *http.Request) {
+	key := r.URL.Query().Get("key")
+	if key == "" {
+		renderNotFound(w, "Missing 'key' parameter")
+		return
+	}
+
+	tmplStr := `<!DOCTYPE html>
+<html>
+<head>
+    <title>Follow {{displayKey .Key}} - RediScan</title>
+    <style>
+        body {
+            font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif;
+            max-width: 1200px;
+            margin: 0 auto;
+            padding: 20px;
+            background-color: #f5f5f5;
+        }
+        h1 a {
+            color: #333;
+            text-decoration: none;
+        }
+        h1 a:hover {
+            text-decoration: underline;
+        }
+        .follow {
+            background-color: white;
+            padding: 20px;
+            border-radius: 5px;
+            box-shadow: 0 2px 4px rgba(0,0,0,0.1);
+            margin-bottom: 20px;
+        }
+        .follow h2 {
+            margin-top: 0;
+            color: #333;
+            word-break: break-all;
+        }
+        .controls {
+            display: flex;
+            gap: 10px;
+            align-items: center;
+            margin-bottom: 10px;
+        }
+        .controls button {
+            background-color: #2196F3;
+            color: white;
+            padding: 8px 16px;
+            border: none;
+            border-radius: 3px;
+            cursor: pointer;
+        }
+        .controls input {
+            width: 80px;
+            padding: 6px;
+        }
+        .status {
+            color: #666;
+            font-size: 14px;
+        }
+        #lines {
+            background-color: #263238;
+            color: #eceff1;
+            font-family: monospace;
+            font-size: 13px;
+            height: 60vh;
+            overflow-y: auto;
+            padding: 10px;
+            border-radius: 3px;
+        }
+        .line {
+            white-space: pre-wrap;
+            word-break: break-all;
+            border-bottom: 1px solid #37474f;
+            padding: 2px 0;
+        }
+        .line.notice {
+            color: #ffb74d;
+            font-style: italic;
+        }
+        .back-link {
+            color: #2196F3;
+            text-decoration: none;
+            margin-right: 15px;
+        }
+    </style>
+    {{customCSSLink}}
+    <script src="{{staticURL "idle.js"}}"></script>
+</head>
+<body>
+    {{persistenceBanner}}
+    <h1><a href="/">RediScan - Redis List Inspector</a></h1>
+    <div class="follow">
+        <h2>Following {{displayKey .Key}}</h2>
+        <div class="controls">
+            <button type="button" id="toggle">Stop</button>
+            <label for="maxLines">Keep last</label>
+            <input type="number" id="maxLines" min="1" value="{{.MaxLines}}">
+            <span>elements</span>
+            <span id="status" class="status">Loading&hellip;</span>
+        </div>
+        <div id="lines"></div>
+    </div>
+    <a href="{{lindexPath}}?key={{.KeyQuery}}" class="back-link">← Browse list</a>
+    <a href="/" class="back-link">Home</a>
+
+    <script>
+        // URL-encoded in Go, as a key's bytes may not survive as a JS string
+        const keyQuery = {{.KeyQuery}};
+        const pollInterval = {{.PollInterval}} * 1000;
+        const lines = document.getElementById('lines');
+        let seen = null;
+        let running = true;
+        let timer = null;
+        const activity = idleTracker({{.IdleTimeout}} * 1000);
+
+        function addLine(text, notice) {
+            // Only keep scrolling with new lines if already at the bottom
+            const atBottom = lines.scrollTop + lines.clientHeight >= lines.scrollHeight - 5;
+            const line = document.createElement('div');
+            line.className = notice ? 'line notice' : 'line';
+            line.textContent = text;
+            lines.appendChild(line);
+            const maxLines = parseInt(document.getElementById('maxLines').value) || {{.MaxLines}};
+            while (lines.childElementCount > maxLines) {
+                lines.firstElementChild.remove();
+            }
+            if (atBottom) {
+                lines.scrollTop = lines.scrollHeight;
+            }
+        }
+
+        function poll() {
+            const url = '/api/tail?key=' + keyQuery + (seen === null ? '' : '&seen=' + seen);
+            fetch(url)
+                .then(function(response) {
+                    return response.json().then(function(body) {
+                        if (!response.ok) {
+                            throw new Error(body.error ? body.error.message : response.statusText);
+                        }
+                        return body;
+                    });
+                })
+                .then(function(body) {
+                    if (body.reset) {
+                        addLine('— list shrank to ' + body.length + ' elements (consumed or trimmed); following from here —', true);
+                    }
+                    if (body.skipped) {
+                        addLine('— ' + body.skipped + ' elements skipped —', true);
+                    }
+                    body.values.forEach(function(value) {
+                        addLine(value);
+                    });
+                    seen = body.length;
+                    document.getElementById('status').textContent = 'Length ' + body.length + ' — updated ' + new Date().toLocaleTimeString();
+                })
+                .catch(function(err) {
+                    document.getElementById('status').textContent = 'Could not fetch new elements: ' + err.message;
+                })
+                .finally(function() {
+                    if (running && activity.idle()) {
+                        // Nobody is watching, so stop querying Redis
+                        running = false;
+                        document.getElementById('toggle').textContent = 'Resume';
+                        document.getElementById('status').textContent = 'Paused after ' + activity.describe() + ' without activity';
+                    } else if (running) {
+                        timer = setTimeout(poll, pollInterval);
+                    }
+                });
+        }
+
+        document.getElementById('toggle').addEventListener('click', function() {
+            running = !running;
+            this.textContent = running ? 'Stop' : 'Start';
+            if (running) {
+                activity.reset();
+                poll();
+            } else {
+                clearTimeout(timer);
+                document.getElementById('status').textContent = 'Stopped';
+            }
+        });
+        poll();
+    </script>
+</body>
+</html>`
+
+	tmpl, err := parseTemplate("follow", tmplStr)
+	if err != nil {
+		renderError(w, fmt.Sprintf("Template error: %v", err))
+		return
+	}
+
+	data := struct {
+		Key          string
+		KeyQuery     string
+		PollInterval int
+		MaxLines     int
+		IdleTimeout  int
+	}{
+		Key:          key,
+		KeyQuery:     url.QueryEscape(key),
+		PollInterval: followPollInterval,
+		MaxLines:     followDefaultMaxLines,
+		IdleTimeout:  int(liveIdleTimeout / time.Second),
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("Error rendering template: %v", err)
+	}
+}
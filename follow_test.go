@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTailAPIHandler(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("log", "a", "b")
+
+	var resp struct {
+		Length int64    `json:"length"`
+		Values []string `json:"values"`
+	}
+	rr := httptest.NewRecorder()
+	tailAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/tail?key=log", nil))
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Length != 2 || !reflect.DeepEqual(resp.Values, []string{"b"}) {
+		t.Errorf("expected only the newest element, got %+v", resp)
+	}
+
+	mr.RPush("log", "c")
+	rr = httptest.NewRecorder()
+	tailAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/tail?key=log&seen=2", nil))
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Length != 3 || !reflect.DeepEqual(resp.Values, []string{"c"}) {
+		t.Errorf("expected the new element, got %+v", resp)
+	}
+
+	mr.Set("plain", "x")
+	rr = httptest.NewRecorder()
+	tailAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/tail?key=plain", nil))
+	if rr.Code != http.StatusConflict || !strings.Contains(rr.Body.String(), apiErrWrongType) {
+		t.Errorf("expected WRONG_TYPE for a string key, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	tailAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/tail?key=log&seen=-3", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a negative seen, got %d", rr.Code)
+	}
+}
+
+func TestFollowHandler(t *testing.T) {
+	rr := httptest.NewRecorder()
+	followHandler(rr, httptest.NewRequest(http.MethodGet, "/follow?key=app:log", nil))
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "Following app:log") {
+		t.Errorf("expected follow page, got %d", rr.Code)
+	}
+
+	// Keys are polled for in their URL-encoded form, which survives any bytes
+	rr = httptest.NewRecorder()
+	followHandler(rr, httptest.NewRequest(http.MethodGet, "/follow?key=bin%3A%FF+key", nil))
+	if body := rr.Body.String(); !strings.Contains(body, `const keyQuery = "bin%3A%FF+key";`) || !strings.Contains(body, `Following bin:\xff key`) {
+		t.Errorf("expected the URL-encoded key for polling, got: %s", body)
+	}
+
+	// Polling pauses after LIVE_IDLE_TIMEOUT without activity
+	prev := liveIdleTimeout
+	defer func() { liveIdleTimeout = prev }()
+	liveIdleTimeout = 90 * time.Second
+	rr = httptest.NewRecorder()
+	followHandler(rr, httptest.NewRequest(http.MethodGet, "/follow?key=app:log", nil))
+	body := rr.Body.String()
+	if !strings.Contains(body, `<script src="`+staticURL("idle.js")+`"></script>`) || !strings.Contains(body, "idleTracker( 90  * 1000)") {
+		t.Errorf("expected the idle tracker with the configured timeout, got: %s", body)
+	}
+}
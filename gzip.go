@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// gzipMinSize is the smallest response body worth compressing.
+const gzipMinSize = 1024
+
+// compressibleTypes are the media types gzipHandler compresses. Anything
+// else (images, already-compressed archives, event streams) is passed through.
+var compressibleTypes = map[string]bool{
+	"text/html":              true,
+	"text/css":               true,
+	"text/plain":             true,
+	"text/javascript":        true,
+	"application/javascript": true,
+	"application/json":       true,
+}
+
+// gzipHandler compresses responses with gzip when the client accepts it and
+// the body is a compressible type of at least gzipMinSize bytes.
+func gzipHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		gw := &gzipResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		defer gw.Close()
+		next.ServeHTTP(gw, r)
+	})
+}
+
+// acceptsGzip reports whether the request's Accept-Encoding allows gzip.
+func acceptsGzip(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		if !strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+			continue
+		}
+		// gzip;q=0 explicitly refuses it
+		if name, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(name) == "q" {
+			q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			return err == nil && q > 0
+		}
+		return true
+	}
+	return false
+}
+
+// gzipResponseWriter buffers the start of a response until it knows whether
+// the body is worth compressing, then either streams it through a gzip
+// writer or writes it unchanged.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	decided     bool
+	buf         bytes.Buffer
+	gz          *gzip.Writer
+}
+
+func (w *gzipResponseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.status = status
+	}
+}
+
+func (w *gzipResponseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	if !w.decided {
+		w.buf.Write(p)
+		if w.buf.Len() < gzipMinSize {
+			return len(p), nil
+		}
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	if w.gz != nil {
+		return w.gz.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// decide sends the headers, choosing gzip when large is set and the
+// response is a compressible type that is not already encoded, and writes
+// out anything buffered so far.
+func (w *gzipResponseWriter) decide(large bool) error {
+	w.decided = true
+	header := w.Header()
+	if large && header.Get("Content-Encoding") == "" && w.compressible() {
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		w.gz = gzip.NewWriter(w.ResponseWriter)
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	var err error
+	if w.gz != nil {
+		_, err = w.gz.Write(w.buf.Bytes())
+	} else {
+		_, err = w.ResponseWriter.Write(w.buf.Bytes())
+	}
+	w.buf.Reset()
+	return err
+}
+
+func (w *gzipResponseWriter) compressible() bool {
+	contentType := w.Header().Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(w.buf.Bytes())
+		w.Header().Set("Content-Type", contentType)
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && compressibleTypes[mediaType]
+}
+
+// Flush sends whatever has been written so far. A response flushed before
+// reaching gzipMinSize is treated as a stream and compressed only if its
+// type allows.
+func (w *gzipResponseWriter) Flush() {
+	if !w.decided {
+		w.decide(true)
+	}
+	if w.gz != nil {
+		w.gz.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Close finishes the response, writing out a small body uncompressed.
+func (w *gzipResponseWriter) Close() error {
+	if !w.decided {
+		if !w.wroteHeader {
+			return nil
+		}
+		return w.decide(false)
+	}
+	if w.gz != nil {
+		return w.gz.Close()
+	}
+	return nil
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (w *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
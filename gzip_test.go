@@ -0,0 +1,101 @@
+package main
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func serveGzip(handler http.HandlerFunc, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rr := httptest.NewRecorder()
+	gzipHandler(handler).ServeHTTP(rr, req)
+	return rr
+}
+
+func TestGzipHandler_CompressesLargeHTML(t *testing.T) {
+	body := strings.Repeat("<p>hello</p>", 500)
+	rr := serveGzip(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, body)
+	}, "deflate, gzip")
+
+	if rr.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected gzip encoding, got headers %v", rr.Header())
+	}
+	zr, err := gzip.NewReader(rr.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != body {
+		t.Errorf("decompressed body does not match")
+	}
+}
+
+func TestGzipHandler_CompressesScripts(t *testing.T) {
+	body := strings.Repeat("console.log('hello');\n", 100)
+	for _, contentType := range []string{"text/javascript; charset=utf-8", "application/javascript"} {
+		rr := serveGzip(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", contentType)
+			io.WriteString(w, body)
+		}, "gzip")
+		if rr.Header().Get("Content-Encoding") != "gzip" {
+			t.Errorf("%s: expected gzip encoding, got headers %v", contentType, rr.Header())
+		}
+	}
+
+	// The embedded scripts are served as text/javascript
+	req := httptest.NewRequest(http.MethodGet, staticRoute+"prefs.js", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rr := httptest.NewRecorder()
+	gzipHandler(http.HandlerFunc(staticHandler)).ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK || rr.Header().Get("Content-Encoding") != "gzip" {
+		t.Errorf("expected prefs.js gzip-compressed, got %d %v", rr.Code, rr.Header())
+	}
+}
+
+func TestGzipHandler_PassesThrough(t *testing.T) {
+	large := strings.Repeat("x", 2*gzipMinSize)
+	tests := map[string]struct {
+		acceptEncoding string
+		contentType    string
+		encoding       string
+		body           string
+	}{
+		"not accepted":       {"", "text/html", "", large},
+		"refused":            {"gzip;q=0", "text/html", "", large},
+		"small":              {"gzip", "text/html", "", "tiny"},
+		"not compressible":   {"gzip", "image/png", "", large},
+		"already compressed": {"gzip", "application/json", "br", large},
+	}
+	for name, tt := range tests {
+		rr := serveGzip(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", tt.contentType)
+			if tt.encoding != "" {
+				w.Header().Set("Content-Encoding", tt.encoding)
+			}
+			w.WriteHeader(http.StatusTeapot)
+			io.WriteString(w, tt.body)
+		}, tt.acceptEncoding)
+
+		if rr.Header().Get("Content-Encoding") != tt.encoding {
+			t.Errorf("%s: unexpected Content-Encoding %q", name, rr.Header().Get("Content-Encoding"))
+		}
+		if rr.Code != http.StatusTeapot {
+			t.Errorf("%s: expected status to be preserved, got %d", name, rr.Code)
+		}
+		if rr.Body.String() != tt.body {
+			t.Errorf("%s: expected body unchanged", name)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package inspector
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// KeyAccess describes how recently or how often a key has been read or
+// written, as far as the server's eviction policy tracks it.
+type KeyAccess struct {
+	IdleTime time.Duration // Time since the key was last accessed, under an LRU or no eviction policy
+	LFU      bool          // The server uses an LFU policy, so only Freq is known
+	Freq     int64         // The logarithmic access frequency counter, when LFU is set
+}
+
+// Access reads OBJECT IDLETIME for key, or OBJECT FREQ when the server's LFU
+// eviction policy means idle times are not tracked. Neither command counts as
+// an access, but any other read does, so call this before reading the key.
+func Access(ctx context.Context, client redis.UniversalClient, key string) (*KeyAccess, error) {
+	idle, err := client.ObjectIdleTime(ctx, key).Result()
+	if err == nil {
+		return &KeyAccess{IdleTime: idle}, nil
+	}
+	// Redis refuses IDLETIME with "An LFU maxmemory policy is selected, idle
+	// time not tracked"
+	if !strings.Contains(err.Error(), "LFU") {
+		return nil, err
+	}
+	freq, err := client.ObjectFreq(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+	return &KeyAccess{LFU: true, Freq: freq}, nil
+}
@@ -0,0 +1,65 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestAccess_IdleTime(t *testing.T) {
+	mr, client := newTestClient(t)
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	mr.SetTime(start)
+	mr.RPush("jobs", "a")
+	mr.SetTime(start.Add(90 * time.Second))
+
+	access, err := Access(context.Background(), client, "jobs")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if access.LFU || access.IdleTime != 90*time.Second {
+		t.Errorf("expected an idle time of 90s, got %+v", access)
+	}
+}
+
+// lfuHook makes the server look as if an LFU eviction policy were selected.
+type lfuHook struct{}
+
+func (lfuHook) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (lfuHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		args := cmd.Args()
+		if len(args) < 2 || args[0] != "object" {
+			return next(ctx, cmd)
+		}
+		switch args[1] {
+		case "idletime":
+			cmd.SetErr(errors.New("ERR An LFU maxmemory policy is selected, idle time not tracked. Please note that when switching between policies at runtime LRU and LFU data will take some time to adjust."))
+		case "freq":
+			cmd.(*redis.IntCmd).SetVal(7)
+		}
+		return cmd.Err()
+	}
+}
+
+func (lfuHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func TestAccess_LFU(t *testing.T) {
+	mr, client := newTestClient(t)
+	mr.RPush("jobs", "a")
+	client.AddHook(lfuHook{})
+
+	access, err := Access(context.Background(), client, "jobs")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !access.LFU || access.Freq != 7 {
+		t.Errorf("expected the LFU frequency, got %+v", access)
+	}
+}
@@ -0,0 +1,145 @@
+package inspector
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// aggregateBatchSize is how many elements are fetched per LRANGE while
+// aggregating, keeping memory bounded on long lists.
+const aggregateBatchSize = 1000
+
+// NoFieldBucket collects elements that are not JSON or lack the field.
+const NoFieldBucket = "(none)"
+
+// FieldBucket is one distinct value of an aggregated field and how many
+// elements carry it.
+type FieldBucket struct {
+	Value   string
+	Count   int64
+	Percent float64
+}
+
+// LookupField follows a dot-separated path (e.g. "order.items.0.sku") through
+// a decoded JSON document. Numeric segments index into arrays.
+func LookupField(doc interface{}, path string) (interface{}, bool) {
+	current := doc
+	for _, segment := range strings.Split(path, ".") {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[segment]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case []interface{}:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			current = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// bucketName returns the label an element is counted under for the field at
+// path.
+func bucketName(value, path string) string {
+	if text, ok := FieldText(value, path); ok {
+		return text
+	}
+	return NoFieldBucket
+}
+
+// FieldText returns the field at path in the JSON element value as text:
+// strings as they are, anything else JSON-encoded. It returns false when
+// value is not JSON or lacks the field.
+func FieldText(value, path string) (string, bool) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(value), &doc); err != nil {
+		return "", false
+	}
+	field, ok := LookupField(doc, path)
+	if !ok {
+		return "", false
+	}
+	if s, ok := field.(string); ok {
+		return s, true
+	}
+	encoded, err := json.Marshal(field)
+	if err != nil {
+		return "", false
+	}
+	return string(encoded), true
+}
+
+// AggregateList tallies every element of the list at key by the value of the
+// field at path, reading the list in batches. Buckets are ordered by count,
+// largest first, and the total number of elements is returned alongside.
+func AggregateList(ctx context.Context, client redis.UniversalClient, key, path string) ([]FieldBucket, int64, error) {
+	counts := make(map[string]int64)
+	var total int64
+
+	for start := int64(0); ; start += aggregateBatchSize {
+		values, err := client.LRange(ctx, key, start, start+aggregateBatchSize-1).Result()
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, value := range values {
+			counts[bucketName(value, path)]++
+		}
+		total += int64(len(values))
+		if len(values) < aggregateBatchSize {
+			break
+		}
+	}
+
+	buckets := make([]FieldBucket, 0, len(counts))
+	for value, count := range counts {
+		buckets = append(buckets, FieldBucket{
+			Value:   value,
+			Count:   count,
+			Percent: float64(count) * 100 / float64(total),
+		})
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		if buckets[i].Count != buckets[j].Count {
+			return buckets[i].Count > buckets[j].Count
+		}
+		return buckets[i].Value < buckets[j].Value
+	})
+	return buckets, total, nil
+}
+
+// IndexField maps each text of the field at path (see FieldText) to the index
+// of the first element from the head carrying it, reading the list at key in
+// batches. The number of elements read is returned alongside.
+func IndexField(ctx context.Context, client redis.UniversalClient, key, path string) (map[string]int64, int64, error) {
+	positions := make(map[string]int64)
+	var total int64
+	for start := int64(0); ; start += aggregateBatchSize {
+		values, err := client.LRange(ctx, key, start, start+aggregateBatchSize-1).Result()
+		if err != nil {
+			return nil, 0, err
+		}
+		for i, value := range values {
+			text, ok := FieldText(value, path)
+			if _, seen := positions[text]; ok && !seen {
+				positions[text] = start + int64(i)
+			}
+		}
+		total += int64(len(values))
+		if len(values) < aggregateBatchSize {
+			break
+		}
+	}
+	return positions, total, nil
+}
@@ -0,0 +1,69 @@
+package inspector
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBucketName(t *testing.T) {
+	tests := []struct {
+		value, path, want string
+	}{
+		{`{"status":"failed"}`, "status", "failed"},
+		{`{"order":{"state":"new"}}`, "order.state", "new"},
+		{`{"items":[{"sku":"A1"}]}`, "items.0.sku", "A1"},
+		{`{"retries":3}`, "retries", "3"},
+		{`{"ok":true}`, "ok", "true"},
+		{`{"other":1}`, "status", NoFieldBucket},
+		{`{"items":[]}`, "items.0", NoFieldBucket},
+		{`not json`, "status", NoFieldBucket},
+	}
+	for _, tt := range tests {
+		if got := bucketName(tt.value, tt.path); got != tt.want {
+			t.Errorf("bucketName(%s, %q) = %q, want %q", tt.value, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestAggregateList(t *testing.T) {
+	mr, client := newTestClient(t)
+	mr.RPush("events",
+		`{"status":"failed"}`,
+		`{"status":"success"}`,
+		`{"status":"success"}`,
+		`plain text`,
+	)
+
+	buckets, total, err := AggregateList(context.Background(), client, "events", "status")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 4 || len(buckets) != 3 {
+		t.Fatalf("expected 4 elements in 3 buckets, got %d in %+v", total, buckets)
+	}
+	if buckets[0].Value != "success" || buckets[0].Count != 2 || buckets[0].Percent != 50 {
+		t.Errorf("expected 'success' to be the largest bucket at 50%%, got %+v", buckets[0])
+	}
+}
+
+func TestIndexField(t *testing.T) {
+	mr, client := newTestClient(t)
+	mr.RPush("orders",
+		`{"id":"a"}`,
+		`plain text`,
+		`{"id":"b","n":1}`,
+		`{"id":"a"}`,
+		`{"id":7}`,
+	)
+
+	positions, total, err := IndexField(context.Background(), client, "orders", "id")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 5 || len(positions) != 3 {
+		t.Fatalf("expected 3 ids from 5 elements, got %v from %d", positions, total)
+	}
+	if positions["a"] != 0 || positions["b"] != 2 || positions["7"] != 4 {
+		t.Errorf("expected the first index of each id, got %v", positions)
+	}
+}
@@ -0,0 +1,72 @@
+package inspector
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// AlertRule flags elements of lists whose key matches Pattern when their raw
+// value matches any of Alerts.
+type AlertRule struct {
+	Pattern *Pattern
+	Alerts  []*regexp.Regexp
+}
+
+// ParseAlertRules parses a JSON array of
+// {"pattern": "...", "alerts": ["regex", ...]} objects.
+func ParseAlertRules(config string) ([]AlertRule, error) {
+	var entries []struct {
+		Pattern string   `json:"pattern"`
+		Alerts  []string `json:"alerts"`
+	}
+	if err := json.Unmarshal([]byte(config), &entries); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	rules := make([]AlertRule, 0, len(entries))
+	for _, entry := range entries {
+		pattern, err := CompilePattern(entry.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", entry.Pattern, err)
+		}
+		if len(entry.Alerts) == 0 {
+			return nil, fmt.Errorf("pattern %q has no alerts", entry.Pattern)
+		}
+		rule := AlertRule{Pattern: pattern}
+		for _, expr := range entry.Alerts {
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				return nil, fmt.Errorf("pattern %q has invalid alert %q: %w", entry.Pattern, expr, err)
+			}
+			rule.Alerts = append(rule.Alerts, re)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// MatchAlerts returns the first rule matching key, or nil if none applies.
+func MatchAlerts(rules []AlertRule, key string) *AlertRule {
+	for i := range rules {
+		if rules[i].Pattern.Match(key) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// Alerting returns the indices of the raw values matching any of the rule's
+// alerts.
+func (r *AlertRule) Alerting(values []string) []int64 {
+	var alerting []int64
+	for i, value := range values {
+		for _, re := range r.Alerts {
+			if re.MatchString(value) {
+				alerting = append(alerting, int64(i))
+				break
+			}
+		}
+	}
+	return alerting
+}
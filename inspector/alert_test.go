@@ -0,0 +1,38 @@
+package inspector
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAlertRules(t *testing.T) {
+	rules, err := ParseAlertRules(`[
+		{"pattern": "orders:*", "alerts": ["\"status\":\\s*\"error\"", "timeout"]}
+	]`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rule := MatchAlerts(rules, "orders:eu")
+	if rule == nil {
+		t.Fatal("expected orders:eu to match")
+	}
+	if MatchAlerts(rules, "jobs:email") != nil {
+		t.Error("expected no rule for an unmatched key")
+	}
+
+	values := []string{`{"status":"ok"}`, `{"status": "error"}`, `upstream timeout`, `{"status":"ok"}`}
+	if got := rule.Alerting(values); !reflect.DeepEqual(got, []int64{1, 2}) {
+		t.Errorf("Alerting = %v, want [1 2]", got)
+	}
+
+	for _, config := range []string{
+		`[{"pattern": "*", "alerts": ["("]}]`,
+		`[{"pattern": "*", "alerts": []}]`,
+		`not json`,
+	} {
+		if _, err := ParseAlertRules(config); err == nil {
+			t.Errorf("expected an error for %s", config)
+		}
+	}
+}
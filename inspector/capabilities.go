@@ -0,0 +1,56 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// probeKey is the key introspection commands are tried on. It need not
+// exist: a missing key still shows whether the command is allowed.
+const probeKey = "rediscan:capability-probe"
+
+// Capabilities records which introspection commands a server accepts.
+// Managed Redis services often disable or rename them, or ACLs refuse them.
+type Capabilities struct {
+	ObjectIdleTime bool // OBJECT IDLETIME and FREQ
+	ObjectEncoding bool // OBJECT ENCODING
+	MemoryUsage    bool // MEMORY USAGE
+	ConfigGet      bool // CONFIG GET
+}
+
+// AllCapabilities assumes every command is available, as used before (or
+// instead of) probing.
+func AllCapabilities() Capabilities {
+	return Capabilities{ObjectIdleTime: true, ObjectEncoding: true, MemoryUsage: true, ConfigGet: true}
+}
+
+// ProbeCapabilities tries each introspection command once. A command is
+// reported unavailable only when the server refuses it; one that fails for
+// another reason, such as a dropped connection, is assumed available so
+// callers still try it and handle the error then.
+func ProbeCapabilities(ctx context.Context, client redis.UniversalClient) Capabilities {
+	return Capabilities{
+		ObjectIdleTime: !CommandRefused(client.ObjectIdleTime(ctx, probeKey).Err()),
+		ObjectEncoding: !CommandRefused(client.ObjectEncoding(ctx, probeKey).Err()),
+		MemoryUsage:    !CommandRefused(client.MemoryUsage(ctx, probeKey).Err()),
+		ConfigGet:      !CommandRefused(client.ConfigGet(ctx, "save").Err()),
+	}
+}
+
+// CommandRefused reports whether err is the server refusing a command as
+// unknown, disabled or not permitted, rather than a missing key or a
+// connection problem.
+func CommandRefused(err error) bool {
+	if err == nil || errors.Is(err, redis.Nil) {
+		return false
+	}
+	var redisErr redis.Error
+	if !errors.As(err, &redisErr) {
+		return false
+	}
+	// OBJECT IDLETIME is refused under an LFU policy, but FREQ then works
+	return !strings.Contains(err.Error(), "LFU")
+}
@@ -0,0 +1,38 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestProbeCapabilities(t *testing.T) {
+	_, client := newTestClient(t)
+
+	// miniredis implements OBJECT IDLETIME and MEMORY USAGE but not OBJECT
+	// ENCODING or CONFIG, much like a locked-down managed service
+	caps := ProbeCapabilities(context.Background(), client)
+	want := Capabilities{ObjectIdleTime: true, MemoryUsage: true}
+	if caps != want {
+		t.Errorf("expected %+v, got %+v", want, caps)
+	}
+}
+
+func TestCommandRefused(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{redis.Nil, false},
+		{errors.New("dial tcp: connection refused"), false},
+		{redis.ErrClosed, false},
+	}
+	for _, tt := range tests {
+		if got := CommandRefused(tt.err); got != tt.want {
+			t.Errorf("CommandRefused(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
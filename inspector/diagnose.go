@@ -0,0 +1,191 @@
+package inspector
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+)
+
+// maxDiagnoseStages bounds the decoding pipeline Diagnose suggests.
+const maxDiagnoseStages = 4
+
+// gzipMagic starts every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// DiagnosisCheck is the outcome of one probe of a value.
+type DiagnosisCheck struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail"`
+}
+
+// Diagnosis reports what a value appears to be, to help choose how to decode
+// a value that looks like garbage.
+type Diagnosis struct {
+	Bytes     int              `json:"bytes"`
+	Checks    []DiagnosisCheck `json:"checks"`
+	Suggested []string         `json:"suggested"` // Transform pipeline that decodes the value, empty if none was found
+}
+
+// Diagnose probes value with the same decoders the transform pipelines use,
+// without changing anything: UTF-8 validity, JSON, gzip, base64, hex and
+// msgpack. It also suggests a pipeline of transforms that ends in JSON or
+// readable text, when one can be found.
+func Diagnose(value string) *Diagnosis {
+	data := []byte(value)
+	d := &Diagnosis{Bytes: len(data), Suggested: suggestTransforms(data)}
+
+	if utf8.Valid(data) {
+		d.add("UTF-8", true, fmt.Sprintf("valid, %d characters", utf8.RuneCount(data)))
+	} else {
+		d.add("UTF-8", false, fmt.Sprintf("invalid from byte %d", invalidUTF8Offset(data)))
+	}
+
+	if kind := jsonKind(data); kind != "" {
+		d.add("JSON", true, "valid "+kind)
+	} else {
+		d.add("JSON", false, "not valid JSON")
+	}
+
+	switch out, err := gunzip(data); {
+	case !bytes.HasPrefix(data, gzipMagic):
+		d.add("gzip", false, "no gzip magic bytes (1f 8b)")
+	case err != nil:
+		d.add("gzip", false, "gzip magic bytes present, but decompressing failed: "+err.Error())
+	default:
+		d.add("gzip", true, fmt.Sprintf("decompresses to %d bytes", len(out)))
+	}
+
+	if out, err := decodeBase64(data); err == nil {
+		d.add("base64", true, fmt.Sprintf("decodes to %d bytes%s", len(out), describeDecoded(out)))
+	} else {
+		d.add("base64", false, "not valid base64")
+	}
+
+	if out, err := decodeHex(data); err == nil {
+		d.add("hex", true, fmt.Sprintf("decodes to %d bytes%s", len(out), describeDecoded(out)))
+	} else {
+		d.add("hex", false, "not valid hex")
+	}
+
+	if isMsgpackContainer(data) {
+		d.add("msgpack", true, "decodes as a msgpack map or array")
+	} else {
+		d.add("msgpack", false, "not a msgpack map or array")
+	}
+	return d
+}
+
+func (d *Diagnosis) add(name string, ok bool, detail string) {
+	d.Checks = append(d.Checks, DiagnosisCheck{Name: name, OK: ok, Detail: detail})
+}
+
+// suggestTransforms peels off encodings one at a time while each decoded
+// layer is recognisable, returning the stages that reach JSON or text.
+func suggestTransforms(data []byte) []string {
+	stages := []string{}
+	for len(stages) < maxDiagnoseStages {
+		if jsonKind(data) != "" {
+			if len(stages) > 0 {
+				stages = append(stages, "json")
+			}
+			return stages
+		}
+		if out, err := gunzip(data); err == nil && bytes.HasPrefix(data, gzipMagic) {
+			stages, data = append(stages, "gzip"), out
+			continue
+		}
+		if isMsgpackContainer(data) {
+			return append(stages, "msgpack")
+		}
+		// Plain words are often valid base64 or hex too, so those are only
+		// suggested when they decode to something recognisable
+		if out, err := decodeHex(data); err == nil && recognisable(out) {
+			stages, data = append(stages, "hex"), out
+			continue
+		}
+		if out, err := decodeBase64(data); err == nil && recognisable(out) {
+			stages, data = append(stages, "base64"), out
+			continue
+		}
+		break
+	}
+	if len(stages) > 0 && !utf8.Valid(data) {
+		// The layers found do not lead anywhere readable
+		return []string{}
+	}
+	return stages
+}
+
+// recognisable reports whether decoded data is JSON, gzip or msgpack.
+func recognisable(data []byte) bool {
+	return jsonKind(data) != "" || bytes.HasPrefix(data, gzipMagic) || isMsgpackContainer(data)
+}
+
+// describeDecoded names what decoded data looks like, for check details.
+func describeDecoded(data []byte) string {
+	switch {
+	case jsonKind(data) != "":
+		return " of JSON"
+	case bytes.HasPrefix(data, gzipMagic):
+		return " starting with gzip magic bytes"
+	case isMsgpackContainer(data):
+		return " of msgpack"
+	case utf8.Valid(data):
+		return " of UTF-8 text"
+	default:
+		return " of binary data"
+	}
+}
+
+// jsonKind returns "object", "array", "string", "number", "boolean" or
+// "null" for a valid JSON document, or "" if data is not JSON.
+func jsonKind(data []byte) string {
+	var parsed interface{}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return ""
+	}
+	switch parsed.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return "null"
+	}
+}
+
+// isMsgpackContainer reports whether data is exactly one msgpack map or
+// array. Nearly any byte decodes as some msgpack scalar, so scalars are not
+// taken as evidence of msgpack.
+func isMsgpackContainer(data []byte) bool {
+	v, err := decodeMsgpack(data)
+	if err != nil {
+		return false
+	}
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		return true
+	}
+	return false
+}
+
+// invalidUTF8Offset returns the offset of the first byte that is not part of
+// a valid UTF-8 sequence.
+func invalidUTF8Offset(data []byte) int {
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		if r == utf8.RuneError && size == 1 {
+			return i
+		}
+		i += size
+	}
+	return len(data)
+}
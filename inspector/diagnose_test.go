@@ -0,0 +1,61 @@
+package inspector
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func gzipped(t *testing.T, s string) string {
+	t.Helper()
+	var b bytes.Buffer
+	w := gzip.NewWriter(&b)
+	if _, err := w.Write([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	return b.String()
+}
+
+func TestDiagnose(t *testing.T) {
+	zipped := gzipped(t, `{"id": 1}`)
+	tests := []struct {
+		name      string
+		value     string
+		ok        []string
+		suggested []string
+	}{
+		{"json", `{"id": 1}`, []string{"UTF-8", "JSON"}, []string{}},
+		{"text", "hello world", []string{"UTF-8"}, []string{}},
+		{"gzip", zipped, []string{"gzip"}, []string{"gzip", "json"}},
+		{"base64 gzip", base64.StdEncoding.EncodeToString([]byte(zipped)), []string{"UTF-8", "base64"}, []string{"base64", "gzip", "json"}},
+		{"hex json", "7b226964223a317d", []string{"UTF-8", "base64", "hex"}, []string{"hex", "json"}},
+		{"msgpack", "\x81\xa2id\x01", []string{"msgpack"}, []string{"msgpack"}},
+	}
+	for _, tt := range tests {
+		d := Diagnose(tt.value)
+		if d.Bytes != len(tt.value) {
+			t.Errorf("%s: expected %d bytes, got %d", tt.name, len(tt.value), d.Bytes)
+		}
+		var ok []string
+		for _, check := range d.Checks {
+			if check.OK {
+				ok = append(ok, check.Name)
+			}
+		}
+		if !reflect.DeepEqual(ok, tt.ok) {
+			t.Errorf("%s: expected passing checks %v, got %+v", tt.name, tt.ok, d.Checks)
+		}
+		if !reflect.DeepEqual(d.Suggested, tt.suggested) {
+			t.Errorf("%s: expected suggested pipeline %v, got %v", tt.name, tt.suggested, d.Suggested)
+		}
+	}
+
+	d := Diagnose("ok\xffbad")
+	if d.Checks[0].OK || !strings.Contains(d.Checks[0].Detail, "byte 2") {
+		t.Errorf("expected invalid UTF-8 to be located, got %+v", d.Checks[0])
+	}
+}
@@ -0,0 +1,66 @@
+package inspector
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// PushDirection records which end of a list producers push to. Redis does not
+// store this, so it is configured per key pattern.
+type PushDirection string
+
+const (
+	// PushLeft means producers LPUSH, so the head (index 0) is newest.
+	PushLeft PushDirection = "lpush"
+	// PushRight means producers RPUSH, so the tail is newest.
+	PushRight PushDirection = "rpush"
+)
+
+// HeadIsNewest reports whether index 0 holds the most recently pushed element.
+func (d PushDirection) HeadIsNewest() bool {
+	return d == PushLeft
+}
+
+// DirectionRule annotates keys matching Pattern with their push direction.
+type DirectionRule struct {
+	Pattern   *Pattern
+	Direction PushDirection
+}
+
+// ParseDirectionRules parses a JSON array of
+// {"pattern": "...", "direction": "lpush" | "rpush"} objects.
+func ParseDirectionRules(config string) ([]DirectionRule, error) {
+	var entries []struct {
+		Pattern   string `json:"pattern"`
+		Direction string `json:"direction"`
+	}
+	if err := json.Unmarshal([]byte(config), &entries); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	rules := make([]DirectionRule, 0, len(entries))
+	for _, entry := range entries {
+		pattern, err := CompilePattern(entry.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", entry.Pattern, err)
+		}
+		direction := PushDirection(strings.ToLower(entry.Direction))
+		if direction != PushLeft && direction != PushRight {
+			return nil, fmt.Errorf("pattern %q has unknown direction %q (want lpush or rpush)", entry.Pattern, entry.Direction)
+		}
+		rules = append(rules, DirectionRule{Pattern: pattern, Direction: direction})
+	}
+	return rules, nil
+}
+
+// MatchDirection returns the direction of the first rule matching key, or ""
+// if the direction is unknown.
+func MatchDirection(rules []DirectionRule, key string) PushDirection {
+	for _, rule := range rules {
+		if rule.Pattern.Match(key) {
+			return rule.Direction
+		}
+	}
+	return ""
+}
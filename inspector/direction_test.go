@@ -0,0 +1,27 @@
+package inspector
+
+import "testing"
+
+func TestParseDirectionRules(t *testing.T) {
+	rules, err := ParseDirectionRules(`[
+		{"pattern": "logs:*", "direction": "LPUSH"},
+		{"pattern": "jobs:*", "direction": "rpush"}
+	]`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if d := MatchDirection(rules, "logs:app"); d != PushLeft || !d.HeadIsNewest() {
+		t.Errorf("expected logs:app to be LPUSH with newest at head, got %q", d)
+	}
+	if d := MatchDirection(rules, "jobs:email"); d != PushRight || d.HeadIsNewest() {
+		t.Errorf("expected jobs:email to be RPUSH, got %q", d)
+	}
+	if d := MatchDirection(rules, "other"); d != "" {
+		t.Errorf("expected unknown direction for unmatched key, got %q", d)
+	}
+
+	if _, err := ParseDirectionRules(`[{"pattern": "*", "direction": "sideways"}]`); err == nil {
+		t.Error("expected an error for an unknown direction")
+	}
+}
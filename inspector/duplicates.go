@@ -0,0 +1,14 @@
+package inspector
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Occurrences returns the index of every element of the list at key equal to
+// value, in order from the head, with one LPOS key value RANK 1 COUNT 0. LPOS
+// reads the whole list, so callers should limit it to lists of modest length.
+func Occurrences(ctx context.Context, client redis.UniversalClient, key, value string) ([]int64, error) {
+	return client.LPosCount(ctx, key, value, 0, redis.LPosArgs{Rank: 1}).Result()
+}
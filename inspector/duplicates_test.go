@@ -0,0 +1,24 @@
+package inspector
+
+import (
+	"context"
+	"testing"
+)
+
+func TestOccurrences(t *testing.T) {
+	mr, client := newTestClient(t)
+	mr.RPush("jobs", "a", "b", "a", "c", "a")
+	ctx := context.Background()
+
+	indices, err := Occurrences(ctx, client, "jobs", "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(indices) != 3 || indices[0] != 0 || indices[1] != 2 || indices[2] != 4 {
+		t.Errorf("expected every index of a, got %v", indices)
+	}
+
+	if indices, err := Occurrences(ctx, client, "jobs", "z"); err != nil || len(indices) != 0 {
+		t.Errorf("expected no indices for a missing value, got %v (%v)", indices, err)
+	}
+}
@@ -0,0 +1,382 @@
+// Package inspector provides the Redis inspection logic behind RediScan:
+// discovering lists, reading their elements, and rendering stored values
+// (pretty-printing, decoding pipelines, schema validation) for display. It has
+// no HTTP dependencies so it can be embedded in other tools.
+package inspector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrKeyNotFound is returned when the inspected key does not exist.
+var ErrKeyNotFound = errors.New("key does not exist")
+
+// ErrEmptyList is returned when the inspected list has no elements.
+var ErrEmptyList = errors.New("list is empty")
+
+// WrongTypeError is returned when the inspected key holds something other
+// than a list.
+type WrongTypeError struct {
+	Type string
+}
+
+func (e *WrongTypeError) Error() string {
+	return fmt.Sprintf("key is not a list (type: %s)", e.Type)
+}
+
+// ListInfo contains information about a Redis list
+type ListInfo struct {
+	Name string
+	Size int64
+}
+
+// List is a loaded Redis list, or a window of one.
+type List struct {
+	Key    string
+	Length int64
+	Offset int64 // Index of Values[0], non-zero when only part of the list was loaded
+	Values []string
+}
+
+// AvailableKeys retrieves up to limit Redis list keys with their sizes, using
+// SCAN with pipelined TYPE and LLEN calls.
+func AvailableKeys(ctx context.Context, client redis.UniversalClient, limit int) ([]ListInfo, error) {
+	return MatchingLists(ctx, client, "*", limit)
+}
+
+// MatchingLists retrieves up to limit list keys matching the glob pattern with
+// their sizes.
+func MatchingLists(ctx context.Context, client redis.UniversalClient, pattern string, limit int) ([]ListInfo, error) {
+	scan, err := ScanLists(ctx, client, pattern, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scan.Lists, nil
+}
+
+// ListScan is the result of scanning for lists, and how far the scan got.
+type ListScan struct {
+	Lists []ListInfo
+	// Scanned is the number of keys of any type examined.
+	Scanned int64
+	// Complete reports whether the scan covered every key matching the
+	// pattern rather than stopping at the limit.
+	Complete bool
+	// FailedShards lists the Redis Cluster shards that could not be
+	// scanned, whose lists are missing. It is empty for a single server.
+	FailedShards []ShardError
+}
+
+// ShardError reports a Redis Cluster shard that could not be scanned.
+type ShardError struct {
+	Addr string
+	Err  error
+}
+
+func (e ShardError) Error() string {
+	return fmt.Sprintf("shard %s: %v", e.Addr, e.Err)
+}
+
+// ScanLists is MatchingLists, also reporting whether the limit cut the scan
+// short, in which case more lists may exist.
+func ScanLists(ctx context.Context, client redis.UniversalClient, pattern string, limit int) (*ListScan, error) {
+	scan := &ListScan{Complete: true}
+	failed, err := scanTypes(ctx, client, pattern, make(map[string]struct{}), func(keys, types []string) bool {
+		var listKeys []string
+		for i, key := range keys {
+			if types[i] == "list" {
+				listKeys = append(listKeys, key)
+			}
+		}
+		scan.Scanned += int64(len(keys))
+		for _, list := range listSizes(ctx, client, listKeys) {
+			scan.Lists = append(scan.Lists, list)
+			if len(scan.Lists) >= limit {
+				scan.Complete = false
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	scan.FailedShards = failed
+	return scan, nil
+}
+
+// scanTypes walks the keys matching pattern with SCAN, calling visit with each
+// batch of keys and their types until the scan completes or visit returns
+// false. SCAN may return a key more than once while the keyspace is changing,
+// so keys recorded in seen are dropped and the rest recorded, visiting each
+// key at most once. A nil seen only drops repeats within a batch, keeping
+// memory flat however many keys there are. Types come from a pipeline of TYPE
+// calls; a key whose type could not be read is reported with an empty type.
+//
+// On a Redis Cluster each master is scanned in turn. A shard that fails is
+// reported in the returned ShardErrors and the scan moves on to the next, so
+// an outage of part of the cluster leaves the rest browsable; only when
+// every shard fails is an error returned.
+func scanTypes(ctx context.Context, client redis.UniversalClient, pattern string, seen map[string]struct{}, visit func(keys, types []string) bool) ([]ShardError, error) {
+	cluster, ok := client.(*redis.ClusterClient)
+	if !ok {
+		_, err := scanNode(ctx, client, pattern, seen, visit)
+		return nil, err
+	}
+	masters, err := clusterMasters(ctx, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("listing cluster shards: %w", err)
+	}
+	return scanShards(ctx, masters, pattern, seen, visit)
+}
+
+// scanShards scans each shard in turn, skipping those that fail, until visit
+// returns false.
+func scanShards(ctx context.Context, shards []*redis.Client, pattern string, seen map[string]struct{}, visit func(keys, types []string) bool) ([]ShardError, error) {
+	var failed []ShardError
+	for _, shard := range shards {
+		stopped, err := scanNode(ctx, shard, pattern, seen, visit)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			log.Printf("Warning: Could not scan cluster shard %s, skipping it: %v", shard.Options().Addr, err)
+			failed = append(failed, ShardError{Addr: shard.Options().Addr, Err: err})
+			continue
+		}
+		if stopped {
+			break
+		}
+	}
+	if len(shards) > 0 && len(failed) == len(shards) {
+		return nil, failed[0]
+	}
+	return failed, nil
+}
+
+// clusterMasters returns a client for each master of the cluster, ordered by
+// address so scans visit shards in a stable order.
+func clusterMasters(ctx context.Context, cluster *redis.ClusterClient) ([]*redis.Client, error) {
+	var mu sync.Mutex
+	var masters []*redis.Client
+	err := cluster.ForEachMaster(ctx, func(ctx context.Context, master *redis.Client) error {
+		mu.Lock()
+		defer mu.Unlock()
+		masters = append(masters, master)
+		return nil
+	})
+	sort.Slice(masters, func(i, j int) bool {
+		return masters[i].Options().Addr < masters[j].Options().Addr
+	})
+	return masters, err
+}
+
+// scanNode runs the SCAN loop of scanTypes against one server, reporting
+// whether visit stopped it early.
+func scanNode(ctx context.Context, client redis.UniversalClient, pattern string, seen map[string]struct{}, visit func(keys, types []string) bool) (bool, error) {
+	// Use SCAN instead of KEYS for better performance
+	var cursor uint64
+	for {
+		batch, next, err := client.Scan(ctx, cursor, pattern, 100).Result()
+		if err != nil {
+			return false, err
+		}
+		cursor = next
+
+		batchSeen := seen
+		if batchSeen == nil {
+			batchSeen = make(map[string]struct{}, len(batch))
+		}
+		keys := batch[:0]
+		for _, key := range batch {
+			if _, ok := batchSeen[key]; !ok {
+				batchSeen[key] = struct{}{}
+				keys = append(keys, key)
+			}
+		}
+
+		if len(keys) > 0 {
+			types, err := KeyTypes(ctx, client, keys)
+			if err != nil {
+				// Skip this batch if pipeline fails, log and continue with next scan iteration
+				log.Printf("Warning: Pipeline error, skipping batch: %v", err)
+			} else if !visit(keys, types) {
+				return true, nil
+			}
+		}
+
+		if cursor == 0 {
+			return false, nil
+		}
+	}
+}
+
+// listSizes returns the lengths of the given list keys using a pipeline of
+// LLEN calls. Keys whose length could not be read are omitted.
+func listSizes(ctx context.Context, client redis.UniversalClient, keys []string) []ListInfo {
+	if len(keys) == 0 {
+		return nil
+	}
+	pipe := client.Pipeline()
+	llenCmds := make([]*redis.IntCmd, len(keys))
+	for i, key := range keys {
+		llenCmds[i] = pipe.LLen(ctx, key)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("Warning: Pipeline error getting list sizes, skipping batch: %v", err)
+		return nil
+	}
+
+	lists := make([]ListInfo, 0, len(keys))
+	for i, key := range keys {
+		size, err := llenCmds[i].Result()
+		if err != nil {
+			continue
+		}
+		lists = append(lists, ListInfo{Name: key, Size: size})
+	}
+	return lists
+}
+
+// KeyType returns the Redis type of key ("none" if it does not exist).
+func KeyType(ctx context.Context, client redis.UniversalClient, key string) (string, error) {
+	return client.Type(ctx, key).Result()
+}
+
+// KeyTypes returns the Redis types of keys, in the same order, from one
+// pipeline of TYPE calls. A key that does not exist has type "none".
+func KeyTypes(ctx context.Context, client redis.UniversalClient, keys []string) ([]string, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	// Use pipeline to batch TYPE commands for better performance
+	pipe := client.Pipeline()
+	typeCmds := make([]*redis.StatusCmd, len(keys))
+	for i, key := range keys {
+		typeCmds[i] = pipe.Type(ctx, key)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+	types := make([]string, len(keys))
+	for i := range keys {
+		types[i] = typeCmds[i].Val()
+	}
+	return types, nil
+}
+
+// ListLength checks that key holds a non-empty list and returns its length.
+// It returns ErrKeyNotFound, a *WrongTypeError or ErrEmptyList when the key
+// cannot be inspected as a list.
+func ListLength(ctx context.Context, client redis.UniversalClient, key string) (int64, error) {
+	keyType, err := KeyType(ctx, client, key)
+	if err != nil {
+		return 0, fmt.Errorf("checking key: %w", err)
+	}
+	if keyType == "none" {
+		return 0, ErrKeyNotFound
+	}
+	if keyType != "list" {
+		return 0, &WrongTypeError{Type: keyType}
+	}
+
+	llen, err := client.LLen(ctx, key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("getting list length: %w", err)
+	}
+	if llen == 0 {
+		return 0, ErrEmptyList
+	}
+	return llen, nil
+}
+
+// InspectList loads every element of the list at key. It fails like
+// ListLength when key is not a non-empty list.
+func InspectList(ctx context.Context, client redis.UniversalClient, key string) (*List, error) {
+	if _, err := ListLength(ctx, client, key); err != nil {
+		return nil, err
+	}
+
+	values, err := client.LRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("getting list elements: %w", err)
+	}
+	// The list may have changed since LLEN, so report the length actually read
+	if len(values) == 0 {
+		return nil, ErrEmptyList
+	}
+	return &List{Key: key, Length: int64(len(values)), Values: values}, nil
+}
+
+// InspectRange loads the elements of the list at key between start and stop
+// inclusive, which may be negative to count from the tail as with LRANGE. The
+// length and elements are read in one transaction so the returned Offset
+// matches the elements. It fails like ListLength when key is not a non-empty
+// list.
+func InspectRange(ctx context.Context, client redis.UniversalClient, key string, start, stop int64) (*List, error) {
+	pipe := client.TxPipeline()
+	typeCmd := pipe.Type(ctx, key)
+	llenCmd := pipe.LLen(ctx, key)
+	rangeCmd := pipe.LRange(ctx, key, start, stop)
+	_, err := pipe.Exec(ctx)
+	switch keyType := typeCmd.Val(); {
+	case keyType == "none":
+		return nil, ErrKeyNotFound
+	case keyType != "" && keyType != "list":
+		return nil, &WrongTypeError{Type: keyType}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting list elements: %w", err)
+	}
+
+	list := &List{Key: key, Length: llenCmd.Val(), Values: rangeCmd.Val()}
+	if len(list.Values) == 0 {
+		return nil, ErrEmptyList
+	}
+	if start < 0 {
+		start += list.Length
+	}
+	if start > 0 {
+		list.Offset = start
+	}
+	return list, nil
+}
+
+// TrimAround drops elements from whichever end of Values is farther from
+// index until they total at most budget bytes, or only index is left, and
+// reports whether any were dropped. A budget of 0 or less keeps them all.
+func (l *List) TrimAround(index int64, budget int) bool {
+	if budget <= 0 {
+		return false
+	}
+	size := 0
+	for _, value := range l.Values {
+		size += len(value)
+	}
+	trimmed := false
+	for size > budget && len(l.Values) > 1 {
+		last := l.Offset + int64(len(l.Values)) - 1
+		if index-l.Offset > last-index {
+			size -= len(l.Values[0])
+			l.Values = l.Values[1:]
+			l.Offset++
+		} else {
+			size -= len(l.Values[len(l.Values)-1])
+			l.Values = l.Values[:len(l.Values)-1]
+		}
+		trimmed = true
+	}
+	if trimmed {
+		// Copy so the dropped elements are not kept alive by the backing array
+		l.Values = slices.Clone(l.Values)
+	}
+	return trimmed
+}
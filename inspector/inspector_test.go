@@ -0,0 +1,265 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestClient returns an in-memory Redis server and a client connected to it.
+func newTestClient(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return mr, client
+}
+
+func TestAvailableKeys(t *testing.T) {
+	mr, client := newTestClient(t)
+	mr.RPush("queue:a", "1", "2")
+	mr.RPush("queue:b", "1")
+	mr.Set("plain", "value")
+	mr.HSet("hash", "f", "v")
+
+	lists, err := AvailableKeys(context.Background(), client, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sizes := make(map[string]int64)
+	for _, l := range lists {
+		sizes[l.Name] = l.Size
+	}
+	if len(sizes) != 2 || sizes["queue:a"] != 2 || sizes["queue:b"] != 1 {
+		t.Errorf("expected only the two lists with their sizes, got %+v", lists)
+	}
+
+	lists, err = AvailableKeys(context.Background(), client, 1)
+	if err != nil || len(lists) != 1 {
+		t.Errorf("expected the limit to cap results at 1, got %+v (err=%v)", lists, err)
+	}
+}
+
+func TestScanLists(t *testing.T) {
+	mr, client := newTestClient(t)
+	mr.RPush("queue:a", "1")
+	mr.RPush("queue:b", "1")
+	mr.Set("plain", "value")
+
+	scan, err := ScanLists(context.Background(), client, "*", 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(scan.Lists) != 2 || scan.Scanned != 3 || !scan.Complete {
+		t.Errorf("expected a complete scan of 3 keys finding 2 lists, got %+v", scan)
+	}
+
+	scan, _ = ScanLists(context.Background(), client, "*", 1)
+	if len(scan.Lists) != 1 || scan.Complete {
+		t.Errorf("expected the limit to cut the scan short, got %+v", scan)
+	}
+}
+
+func TestScanLists_Cluster(t *testing.T) {
+	mr := miniredis.RunT(t)
+	mr.RPush("queue:a", "1", "2")
+	mr.Set("plain", "value")
+	cluster := redis.NewClusterClient(&redis.ClusterOptions{Addrs: []string{mr.Addr()}})
+	t.Cleanup(func() { cluster.Close() })
+
+	scan, err := ScanLists(context.Background(), cluster, "*", 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(scan.Lists) != 1 || scan.Lists[0].Size != 2 || len(scan.FailedShards) != 0 {
+		t.Errorf("expected the list on the only shard, got %+v", scan)
+	}
+}
+
+func TestScanShards_SkipsFailedShard(t *testing.T) {
+	mr, healthy := newTestClient(t)
+	mr.RPush("queue:a", "1")
+	down := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	t.Cleanup(func() { down.Close() })
+
+	var found []string
+	visit := func(keys, types []string) bool {
+		found = append(found, keys...)
+		return true
+	}
+	failed, err := scanShards(context.Background(), []*redis.Client{down, healthy}, "*", make(map[string]struct{}), visit)
+	if err != nil {
+		t.Fatalf("expected the healthy shard to be scanned, got %v", err)
+	}
+	if !reflect.DeepEqual(found, []string{"queue:a"}) {
+		t.Errorf("expected the key on the healthy shard, got %v", found)
+	}
+	if len(failed) != 1 || failed[0].Addr != "127.0.0.1:1" {
+		t.Errorf("expected the shard that is down to be reported, got %+v", failed)
+	}
+
+	if _, err := scanShards(context.Background(), []*redis.Client{down}, "*", make(map[string]struct{}), visit); err == nil {
+		t.Error("expected an error when no shard could be scanned")
+	}
+}
+
+func TestInspectList(t *testing.T) {
+	mr, client := newTestClient(t)
+	ctx := context.Background()
+	mr.RPush("mylist", "a", "b", "c")
+	mr.Set("plain", "value")
+
+	list, err := InspectList(ctx, client, "mylist")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if list.Length != 3 || len(list.Values) != 3 || list.Values[2] != "c" {
+		t.Errorf("unexpected list: %+v", list)
+	}
+
+	if _, err := InspectList(ctx, client, "missing"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("expected ErrKeyNotFound, got %v", err)
+	}
+
+	var wrongType *WrongTypeError
+	if _, err := InspectList(ctx, client, "plain"); !errors.As(err, &wrongType) || wrongType.Type != "string" {
+		t.Errorf("expected WrongTypeError for a string key, got %v", err)
+	}
+}
+
+func TestInspectRange(t *testing.T) {
+	mr, client := newTestClient(t)
+	ctx := context.Background()
+	mr.RPush("mylist", "a", "b", "c", "d", "e")
+	mr.Set("plain", "value")
+
+	list, err := InspectRange(ctx, client, "mylist", -2, -1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if list.Length != 5 || list.Offset != 3 || !reflect.DeepEqual(list.Values, []string{"d", "e"}) {
+		t.Errorf("expected the newest two elements at offset 3, got %+v", list)
+	}
+
+	list, _ = InspectRange(ctx, client, "mylist", -10, 1)
+	if list.Offset != 0 || !reflect.DeepEqual(list.Values, []string{"a", "b"}) {
+		t.Errorf("expected a start before the head to be clamped, got %+v", list)
+	}
+
+	list, _ = InspectRange(ctx, client, "mylist", 1, 2)
+	if list.Offset != 1 || !reflect.DeepEqual(list.Values, []string{"b", "c"}) {
+		t.Errorf("expected elements 1 and 2, got %+v", list)
+	}
+
+	if _, err := InspectRange(ctx, client, "missing", 0, 9); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("expected ErrKeyNotFound, got %v", err)
+	}
+	var wrongType *WrongTypeError
+	if _, err := InspectRange(ctx, client, "plain", 0, 9); !errors.As(err, &wrongType) {
+		t.Errorf("expected WrongTypeError for a string key, got %v", err)
+	}
+}
+
+func TestTrimAround(t *testing.T) {
+	values := make([]string, 2500)
+	for i := range values {
+		values[i] = fmt.Sprintf("%04d", i)
+	}
+	newList := func() *List {
+		return &List{Key: "mylist", Length: 2500, Values: slices.Clone(values)}
+	}
+
+	// Without a budget every element is kept
+	list := newList()
+	if list.TrimAround(1200, 0) || len(list.Values) != 2500 {
+		t.Errorf("expected the whole list kept, got %d elements", len(list.Values))
+	}
+
+	// Ten 4-byte elements fit in 40 bytes, centred on the index
+	list = newList()
+	if !list.TrimAround(1200, 40) || list.Offset != 1195 || !reflect.DeepEqual(list.Values, values[1195:1205]) {
+		t.Errorf("expected elements 1195-1204, got %d elements at offset %d", len(list.Values), list.Offset)
+	}
+
+	// Near the head, the window extends towards the tail
+	list = newList()
+	list.TrimAround(1, 40)
+	if list.Offset != 0 || !reflect.DeepEqual(list.Values, values[:10]) {
+		t.Errorf("expected elements 0-9, got %d elements at offset %d", len(list.Values), list.Offset)
+	}
+
+	// The element at the index is kept even when it alone exceeds the budget
+	list = newList()
+	list.TrimAround(2499, 1)
+	if list.Offset != 2499 || !reflect.DeepEqual(list.Values, values[2499:]) {
+		t.Errorf("expected only element 2499, got %d elements at offset %d", len(list.Values), list.Offset)
+	}
+}
+
+// duplicateScanHook answers SCAN with fixed pages that repeat keys across
+// cursors, as a real server may while keys are being added and removed.
+type duplicateScanHook struct {
+	pages map[uint64][]string
+	next  map[uint64]uint64
+}
+
+func (h duplicateScanHook) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (h duplicateScanHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if scan, ok := cmd.(*redis.ScanCmd); ok {
+			cursor := cmd.Args()[1].(uint64)
+			scan.SetVal(h.pages[cursor], h.next[cursor])
+			return nil
+		}
+		return next(ctx, cmd)
+	}
+}
+
+func (h duplicateScanHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func TestMatchingLists_DuplicateScanKeys(t *testing.T) {
+	mr, client := newTestClient(t)
+	mr.RPush("queue:a", "1")
+	mr.RPush("queue:b", "1", "2")
+	mr.RPush("queue:c", "1")
+	client.AddHook(duplicateScanHook{
+		pages: map[uint64][]string{0: {"queue:a", "queue:b"}, 7: {"queue:b", "queue:c", "queue:a"}},
+		next:  map[uint64]uint64{0: 7, 7: 0},
+	})
+
+	lists, err := MatchingLists(context.Background(), client, "*", 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(lists) != 3 {
+		t.Errorf("expected each key once, got %+v", lists)
+	}
+
+	lists, err = MatchingLists(context.Background(), client, "*", 3)
+	if err != nil || len(lists) != 3 {
+		t.Errorf("expected duplicates not to count toward the limit, got %+v (%v)", lists, err)
+	}
+}
+
+func TestKeyTypes(t *testing.T) {
+	mr, client := newTestClient(t)
+	mr.RPush("jobs", "a")
+	mr.Set("plain", "v")
+
+	types, err := KeyTypes(context.Background(), client, []string{"plain", "missing", "jobs"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(types, []string{"string", "none", "list"}) {
+		t.Errorf("expected types in key order, got %v", types)
+	}
+}
@@ -0,0 +1,119 @@
+package inspector
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// KeyInfo is a key of any type found while scanning the keyspace.
+type KeyInfo struct {
+	Name string
+	Type string
+}
+
+// KeyScan is the result of scanning for keys of every type.
+type KeyScan struct {
+	Keys []KeyInfo
+	// FailedShards lists the Redis Cluster shards that could not be
+	// scanned, whose keys are missing. It is empty for a single server.
+	FailedShards []ShardError
+}
+
+// ScanKeys returns up to limit keys matching pattern along with their types.
+// Keys whose type could not be read are omitted.
+func ScanKeys(ctx context.Context, client redis.UniversalClient, pattern string, limit int) ([]KeyInfo, error) {
+	scan, err := ScanKeyspace(ctx, client, pattern, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scan.Keys, nil
+}
+
+// ScanKeyspace is ScanKeys, also reporting the cluster shards that could not
+// be scanned.
+func ScanKeyspace(ctx context.Context, client redis.UniversalClient, pattern string, limit int) (*KeyScan, error) {
+	scan := &KeyScan{}
+	failed, err := scanTypes(ctx, client, pattern, make(map[string]struct{}), func(keys, types []string) bool {
+		for i, key := range keys {
+			if types[i] == "" || types[i] == "none" {
+				continue
+			}
+			scan.Keys = append(scan.Keys, KeyInfo{Name: key, Type: types[i]})
+			if len(scan.Keys) >= limit {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	scan.FailedShards = failed
+	return scan, nil
+}
+
+// WalkKeyspace scans every key matching pattern, calling visit with each
+// batch of keys as it is found, so callers can stream a whole keyspace
+// without holding it in memory. No names are kept between batches, so a key
+// SCAN returns twice while the keyspace changes may be visited twice. An
+// error from visit stops the scan and is returned.
+func WalkKeyspace(ctx context.Context, client redis.UniversalClient, pattern string, visit func(keys []KeyInfo) error) ([]ShardError, error) {
+	var visitErr error
+	failed, err := scanTypes(ctx, client, pattern, nil, func(keys, types []string) bool {
+		batch := make([]KeyInfo, 0, len(keys))
+		for i, key := range keys {
+			if types[i] == "" || types[i] == "none" {
+				continue
+			}
+			batch = append(batch, KeyInfo{Name: key, Type: types[i]})
+		}
+		if len(batch) == 0 {
+			return true
+		}
+		visitErr = visit(batch)
+		return visitErr == nil
+	})
+	if visitErr != nil {
+		return failed, visitErr
+	}
+	return failed, err
+}
+
+// KeySizes returns the size of each key with one pipeline: the number of
+// elements of a list, hash, set, sorted set or stream, or the length in bytes
+// of a string. Keys of other types, or whose size could not be read (deleted
+// since the scan, say), get -1.
+func KeySizes(ctx context.Context, client redis.UniversalClient, keys []KeyInfo) []int64 {
+	sizes := make([]int64, len(keys))
+	if len(keys) == 0 {
+		return sizes
+	}
+	pipe := client.Pipeline()
+	cmds := make([]*redis.IntCmd, len(keys))
+	for i, key := range keys {
+		switch key.Type {
+		case "list":
+			cmds[i] = pipe.LLen(ctx, key.Name)
+		case "hash":
+			cmds[i] = pipe.HLen(ctx, key.Name)
+		case "set":
+			cmds[i] = pipe.SCard(ctx, key.Name)
+		case "zset":
+			cmds[i] = pipe.ZCard(ctx, key.Name)
+		case "stream":
+			cmds[i] = pipe.XLen(ctx, key.Name)
+		case "string":
+			cmds[i] = pipe.StrLen(ctx, key.Name)
+		}
+	}
+	// Failures are reported per key below
+	_, _ = pipe.Exec(ctx)
+	for i, cmd := range cmds {
+		sizes[i] = -1
+		if cmd != nil && cmd.Err() == nil {
+			sizes[i] = cmd.Val()
+		}
+	}
+	return sizes
+}
@@ -0,0 +1,86 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestScanKeys(t *testing.T) {
+	mr, client := newTestClient(t)
+	mr.RPush("queue", "1")
+	mr.Set("plain", "value")
+	mr.HSet("hash", "f", "v")
+
+	keys, err := ScanKeys(context.Background(), client, "*", 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	types := make(map[string]string)
+	for _, k := range keys {
+		types[k.Name] = k.Type
+	}
+	if len(types) != 3 || types["queue"] != "list" || types["plain"] != "string" || types["hash"] != "hash" {
+		t.Errorf("unexpected keys %+v", keys)
+	}
+
+	keys, err = ScanKeys(context.Background(), client, "*", 2)
+	if err != nil || len(keys) != 2 {
+		t.Errorf("expected the limit to be respected, got %+v (%v)", keys, err)
+	}
+}
+
+func TestWalkKeyspace(t *testing.T) {
+	mr, client := newTestClient(t)
+	for i := 0; i < 250; i++ {
+		mr.RPush(fmt.Sprintf("queue:%d", i), "a", "b")
+	}
+	mr.Set("plain", "value")
+	mr.HSet("hash", "f", "v")
+	mr.SAdd("set", "x", "y", "z")
+	ctx := context.Background()
+
+	// Every matching key is visited, however many there are
+	var found []KeyInfo
+	_, err := WalkKeyspace(ctx, client, "queue:*", func(keys []KeyInfo) error {
+		found = append(found, keys...)
+		return nil
+	})
+	if err != nil || len(found) != 250 {
+		t.Errorf("expected all 250 matching keys, got %d (%v)", len(found), err)
+	}
+
+	// Repeats are dropped within a batch, but no names are kept across them
+	client.AddHook(duplicateScanHook{
+		pages: map[uint64][]string{0: {"queue:1", "queue:1", "queue:2"}, 7: {"queue:2"}},
+		next:  map[uint64]uint64{0: 7, 7: 0},
+	})
+	var names []string
+	WalkKeyspace(ctx, client, "queue:*", func(keys []KeyInfo) error {
+		for _, key := range keys {
+			names = append(names, key.Name)
+		}
+		return nil
+	})
+	if strings.Join(names, ",") != "queue:1,queue:2,queue:2" {
+		t.Errorf("expected repeats dropped only within a batch, got %v", names)
+	}
+
+	stop := errors.New("stop")
+	if _, err := WalkKeyspace(ctx, client, "*", func([]KeyInfo) error { return stop }); !errors.Is(err, stop) {
+		t.Errorf("expected the visit error to stop the walk, got %v", err)
+	}
+
+	sizes := KeySizes(ctx, client, []KeyInfo{
+		{Name: "queue:0", Type: "list"},
+		{Name: "plain", Type: "string"},
+		{Name: "hash", Type: "hash"},
+		{Name: "set", Type: "set"},
+		{Name: "module", Type: "ReJSON-RL"},
+	})
+	if fmt.Sprint(sizes) != "[2 5 1 3 -1]" {
+		t.Errorf("unexpected sizes %v", sizes)
+	}
+}
@@ -0,0 +1,190 @@
+package inspector
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	markdownHeading     = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	markdownRule        = regexp.MustCompile(`^\s{0,3}(-(\s*-){2,}|\*(\s*\*){2,}|_(\s*_){2,})\s*$`)
+	markdownBullet      = regexp.MustCompile(`^\s{0,3}[-*+]\s+(.*)$`)
+	markdownNumbered    = regexp.MustCompile(`^\s{0,3}\d{1,9}[.)]\s+(.*)$`)
+	markdownFence       = regexp.MustCompile("^\\s{0,3}(```|~~~)")
+	markdownQuote       = regexp.MustCompile(`^\s{0,3}>\s?(.*)$`)
+	markdownLinkSchemes = map[string]bool{"http": true, "https": true, "mailto": true}
+)
+
+// RenderMarkdown renders common Markdown (headings, paragraphs, emphasis,
+// code, lists, block quotes, rules and links) as HTML. The input is treated
+// as untrusted: all text is escaped, raw HTML is shown as text rather than
+// passed through, links are kept only for http, https and mailto URLs, and
+// images are rendered as links so viewing a value never fetches anything.
+func RenderMarkdown(src string) string {
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	var b strings.Builder
+	renderMarkdownBlocks(&b, lines)
+	return b.String()
+}
+
+// renderMarkdownBlocks renders lines as a sequence of block elements.
+func renderMarkdownBlocks(b *strings.Builder, lines []string) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+
+		case markdownFence.MatchString(line):
+			fence := markdownFence.FindStringSubmatch(line)[1]
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				code = append(code, lines[i])
+			}
+			i++ // Closing fence
+			b.WriteString("<pre><code>" + html.EscapeString(strings.Join(code, "\n")) + "</code></pre>\n")
+
+		case markdownHeading.MatchString(line):
+			m := markdownHeading.FindStringSubmatch(line)
+			level := string(rune('0' + len(m[1])))
+			b.WriteString("<h" + level + ">" + renderMarkdownInline(m[2]) + "</h" + level + ">\n")
+			i++
+
+		case markdownRule.MatchString(line):
+			b.WriteString("<hr>\n")
+			i++
+
+		case markdownQuote.MatchString(line):
+			var quoted []string
+			for ; i < len(lines) && markdownQuote.MatchString(lines[i]); i++ {
+				quoted = append(quoted, markdownQuote.FindStringSubmatch(lines[i])[1])
+			}
+			b.WriteString("<blockquote>\n")
+			renderMarkdownBlocks(b, quoted)
+			b.WriteString("</blockquote>\n")
+
+		case markdownBullet.MatchString(line):
+			i = renderMarkdownList(b, lines, i, "ul", markdownBullet)
+
+		case markdownNumbered.MatchString(line):
+			i = renderMarkdownList(b, lines, i, "ol", markdownNumbered)
+
+		default:
+			var para []string
+			for ; i < len(lines) && strings.TrimSpace(lines[i]) != "" && !startsMarkdownBlock(lines[i]); i++ {
+				para = append(para, strings.TrimSpace(lines[i]))
+			}
+			b.WriteString("<p>" + renderMarkdownInline(strings.Join(para, "\n")) + "</p>\n")
+		}
+	}
+}
+
+// startsMarkdownBlock reports whether line begins a block other than a
+// paragraph, and so ends the paragraph before it.
+func startsMarkdownBlock(line string) bool {
+	return markdownFence.MatchString(line) || markdownHeading.MatchString(line) ||
+		markdownRule.MatchString(line) || markdownQuote.MatchString(line) ||
+		markdownBullet.MatchString(line) || markdownNumbered.MatchString(line)
+}
+
+// renderMarkdownList renders the list items starting at lines[i] as a tag
+// list, returning the index of the first line after it. Indented lines
+// continue the previous item.
+func renderMarkdownList(b *strings.Builder, lines []string, i int, tag string, item *regexp.Regexp) int {
+	b.WriteString("<" + tag + ">\n")
+	for i < len(lines) {
+		m := item.FindStringSubmatch(lines[i])
+		if m == nil {
+			break
+		}
+		text := m[1]
+		for i++; i < len(lines) && strings.HasPrefix(lines[i], "  ") && strings.TrimSpace(lines[i]) != ""; i++ {
+			text += "\n" + strings.TrimSpace(lines[i])
+		}
+		b.WriteString("<li>" + renderMarkdownInline(text) + "</li>\n")
+	}
+	b.WriteString("</" + tag + ">\n")
+	return i
+}
+
+// renderMarkdownInline renders code spans, emphasis and links within a block,
+// escaping everything else.
+func renderMarkdownInline(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == '\\' && i+1 < len(text) && strings.IndexByte("\\`*_[]()#+-.!>~", text[i+1]) >= 0:
+			b.WriteString(html.EscapeString(text[i+1 : i+2]))
+			i += 2
+			continue
+
+		case c == '`':
+			if end := strings.IndexByte(text[i+1:], '`'); end >= 0 {
+				b.WriteString("<code>" + html.EscapeString(text[i+1:i+1+end]) + "</code>")
+				i += end + 2
+				continue
+			}
+
+		case c == '*' || c == '_':
+			delim := string(c)
+			tag := "em"
+			if strings.HasPrefix(text[i:], delim+delim) {
+				delim += delim
+				tag = "strong"
+			}
+			rest := text[i+len(delim):]
+			if end := strings.Index(rest, delim); end > 0 && rest[0] != ' ' {
+				b.WriteString("<" + tag + ">" + renderMarkdownInline(rest[:end]) + "</" + tag + ">")
+				i += len(delim)*2 + end
+				continue
+			}
+
+		case c == '[' || (c == '!' && strings.HasPrefix(text[i:], "![")):
+			start := i
+			if c == '!' {
+				start++
+			}
+			if label, href, n, ok := markdownLink(text[start:]); ok {
+				if safeMarkdownURL(href) {
+					b.WriteString(`<a href="` + html.EscapeString(href) + `" rel="noopener noreferrer nofollow">` + renderMarkdownInline(label) + "</a>")
+				} else {
+					b.WriteString(renderMarkdownInline(label))
+				}
+				i = start + n
+				continue
+			}
+		}
+		b.WriteString(html.EscapeString(text[i : i+1]))
+		i++
+	}
+	return b.String()
+}
+
+// markdownLink parses "[label](href)" at the start of text, returning its
+// parts and length.
+func markdownLink(text string) (label, href string, n int, ok bool) {
+	closeLabel := strings.Index(text, "](")
+	if !strings.HasPrefix(text, "[") || closeLabel < 0 {
+		return "", "", 0, false
+	}
+	closeHref := strings.IndexByte(text[closeLabel+2:], ')')
+	if closeHref < 0 {
+		return "", "", 0, false
+	}
+	href = strings.TrimSpace(text[closeLabel+2 : closeLabel+2+closeHref])
+	// Drop an optional title: [label](href "title")
+	if space := strings.IndexAny(href, " \t"); space >= 0 {
+		href = href[:space]
+	}
+	return text[1:closeLabel], href, closeLabel + 3 + closeHref, true
+}
+
+// safeMarkdownURL reports whether href may be used as a link target, which
+// rules out javascript: and data: URLs among others.
+func safeMarkdownURL(href string) bool {
+	u, err := url.Parse(href)
+	return err == nil && markdownLinkSchemes[strings.ToLower(u.Scheme)]
+}
@@ -0,0 +1,51 @@
+package inspector
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"heading", "## Order *shipped*", "<h2>Order <em>shipped</em></h2>\n"},
+		{"paragraph", "Hello **world**,\nsee `a<b`", "<p>Hello <strong>world</strong>,\nsee <code>a&lt;b</code></p>\n"},
+		{"bullets", "- one\n- two", "<ul>\n<li>one</li>\n<li>two</li>\n</ul>\n"},
+		{"numbered", "1. one\n2. two", "<ol>\n<li>one</li>\n<li>two</li>\n</ol>\n"},
+		{"quote", "> quoted", "<blockquote>\n<p>quoted</p>\n</blockquote>\n"},
+		{"fence", "```\n<b>x</b>\n```", "<pre><code>&lt;b&gt;x&lt;/b&gt;</code></pre>\n"},
+		{"rule", "a\n\n---", "<p>a</p>\n<hr>\n"},
+		{"link", "[docs](https://example.com/a?b=1&c=2)", `<p><a href="https://example.com/a?b=1&amp;c=2" rel="noopener noreferrer nofollow">docs</a></p>` + "\n"},
+		{"escaped", `\*not em\*`, "<p>*not em*</p>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderMarkdown(tt.src); got != tt.want {
+				t.Errorf("RenderMarkdown(%q) = %q, want %q", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderMarkdown_Sanitizes(t *testing.T) {
+	unsafe := []string{
+		`<script>alert(1)</script>`,
+		`<img src=x onerror=alert(1)>`,
+		`[click](javascript:alert(1))`,
+		`[click](JAVASCRIPT:alert(1))`,
+		`[click](data:text/html;base64,PHNjcmlwdD4=)`,
+		`![pixel](https://tracker.example/p.gif)`,
+		`[x](https://example.com/" onmouseover="alert(1))`,
+	}
+	for _, src := range unsafe {
+		got := RenderMarkdown(src)
+		for _, bad := range []string{"<script", "<img", "javascript:", "JAVASCRIPT:", "data:", `" onmouseover`} {
+			if strings.Contains(got, bad) {
+				t.Errorf("RenderMarkdown(%q) = %q contains %q", src, got, bad)
+			}
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package inspector
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// errMsgpackShort is returned when a msgpack value is truncated.
+var errMsgpackShort = errors.New("msgpack: unexpected end of data")
+
+// decodeMsgpack decodes a single msgpack value into the same generic types
+// encoding/json produces (maps with string keys, []interface{}, float64/int64,
+// string, bool, nil), so the result can be re-encoded as JSON. Binary and
+// extension payloads are returned as strings.
+func decodeMsgpack(data []byte) (interface{}, error) {
+	d := &msgpackDecoder{data: data}
+	v, err := d.value()
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(d.data) {
+		return nil, fmt.Errorf("msgpack: %d trailing bytes", len(d.data)-d.pos)
+	}
+	return v, nil
+}
+
+type msgpackDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *msgpackDecoder) next(n int) ([]byte, error) {
+	if n < 0 || d.pos+n > len(d.data) {
+		return nil, errMsgpackShort
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+func (d *msgpackDecoder) uint(n int) (uint64, error) {
+	b, err := d.next(n)
+	if err != nil {
+		return 0, err
+	}
+	switch n {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	default:
+		return binary.BigEndian.Uint64(b), nil
+	}
+}
+
+func (d *msgpackDecoder) value() (interface{}, error) {
+	b, err := d.next(1)
+	if err != nil {
+		return nil, err
+	}
+	c := b[0]
+
+	switch {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c&0xf0 == 0x80:
+		return d.mapOf(int(c & 0x0f))
+	case c&0xf0 == 0x90:
+		return d.arrayOf(int(c & 0x0f))
+	case c&0xe0 == 0xa0:
+		return d.str(int(c & 0x1f))
+	}
+
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6, 0xd9, 0xda, 0xdb:
+		size := map[byte]int{0xc4: 1, 0xc5: 2, 0xc6: 4, 0xd9: 1, 0xda: 2, 0xdb: 4}[c]
+		n, err := d.uint(size)
+		if err != nil {
+			return nil, err
+		}
+		return d.str(int(n))
+	case 0xc7, 0xc8, 0xc9:
+		n, err := d.uint(map[byte]int{0xc7: 1, 0xc8: 2, 0xc9: 4}[c])
+		if err != nil {
+			return nil, err
+		}
+		return d.ext(int(n))
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return d.ext(1 << (c - 0xd4))
+	case 0xca:
+		n, err := d.uint(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(uint32(n))), nil
+	case 0xcb:
+		n, err := d.uint(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(n), nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		n, err := d.uint(1 << (c - 0xcc))
+		if err != nil {
+			return nil, err
+		}
+		if n > math.MaxInt64 {
+			return float64(n), nil
+		}
+		return int64(n), nil
+	case 0xd0:
+		n, err := d.uint(1)
+		return int64(int8(n)), err
+	case 0xd1:
+		n, err := d.uint(2)
+		return int64(int16(n)), err
+	case 0xd2:
+		n, err := d.uint(4)
+		return int64(int32(n)), err
+	case 0xd3:
+		n, err := d.uint(8)
+		return int64(n), err
+	case 0xdc, 0xdd:
+		n, err := d.uint(map[byte]int{0xdc: 2, 0xdd: 4}[c])
+		if err != nil {
+			return nil, err
+		}
+		return d.arrayOf(int(n))
+	case 0xde, 0xdf:
+		n, err := d.uint(map[byte]int{0xde: 2, 0xdf: 4}[c])
+		if err != nil {
+			return nil, err
+		}
+		return d.mapOf(int(n))
+	}
+	return nil, fmt.Errorf("msgpack: invalid type byte 0x%02x at offset %d", c, d.pos-1)
+}
+
+func (d *msgpackDecoder) str(n int) (interface{}, error) {
+	b, err := d.next(n)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (d *msgpackDecoder) ext(n int) (interface{}, error) {
+	if _, err := d.next(1); err != nil { // extension type
+		return nil, err
+	}
+	return d.str(n)
+}
+
+func (d *msgpackDecoder) arrayOf(n int) (interface{}, error) {
+	if n > len(d.data)-d.pos {
+		return nil, errMsgpackShort
+	}
+	arr := make([]interface{}, n)
+	for i := range arr {
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		arr[i] = v
+	}
+	return arr, nil
+}
+
+func (d *msgpackDecoder) mapOf(n int) (interface{}, error) {
+	if n > len(d.data)-d.pos {
+		return nil, errMsgpackShort
+	}
+	m := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		k, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		m[fmt.Sprint(k)] = v
+	}
+	return m, nil
+}
@@ -0,0 +1,68 @@
+package inspector
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Pattern is a compiled Redis-style glob (as used by SCAN MATCH and KEYS) for
+// matching per-key configuration against key names.
+type Pattern struct {
+	glob string
+	re   *regexp.Regexp
+}
+
+// CompilePattern converts a glob supporting *, ?, [...] and backslash escapes
+// into a Pattern.
+func CompilePattern(glob string) (*Pattern, error) {
+	var b strings.Builder
+	b.WriteString(`(?s)^`)
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			b.WriteString(`.*`)
+		case '?':
+			b.WriteString(`.`)
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "^") {
+				class = "^" + regexp.QuoteMeta(class[1:])
+			} else {
+				class = regexp.QuoteMeta(class)
+			}
+			b.WriteString("[" + strings.ReplaceAll(class, `\-`, "-") + "]")
+			i += end + 1
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+				b.WriteString(regexp.QuoteMeta(string(glob[i])))
+			} else {
+				b.WriteString(`\\`)
+			}
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString(`$`)
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, err
+	}
+	return &Pattern{glob: glob, re: re}, nil
+}
+
+// Match reports whether key matches the pattern.
+func (p *Pattern) Match(key string) bool {
+	return p.re.MatchString(key)
+}
+
+// String returns the original glob.
+func (p *Pattern) String() string {
+	return p.glob
+}
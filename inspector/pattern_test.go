@@ -0,0 +1,35 @@
+package inspector
+
+import "testing"
+
+func TestPatternMatch(t *testing.T) {
+	tests := []struct {
+		pattern, key string
+		want         bool
+	}{
+		{"*", "anything", true},
+		{"jobs:*", "jobs:email", true},
+		{"jobs:*", "jobs:a/b", true},
+		{"jobs:*", "other:email", false},
+		{"h?llo", "hello", true},
+		{"h?llo", "heello", false},
+		{"h[ae]llo", "hallo", true},
+		{"h[ae]llo", "hillo", false},
+		{"h[^e]llo", "hallo", true},
+		{"h[^e]llo", "hello", false},
+		{"h[a-c]llo", "hbllo", true},
+		{`star\*`, "star*", true},
+		{`star\*`, "starry", false},
+		{"a.b", "axb", false},
+		{"line*", "line\nbreak", true},
+	}
+	for _, tt := range tests {
+		p, err := CompilePattern(tt.pattern)
+		if err != nil {
+			t.Fatalf("CompilePattern(%q): %v", tt.pattern, err)
+		}
+		if got := p.Match(tt.key); got != tt.want {
+			t.Errorf("%q.Match(%q) = %v, want %v", tt.pattern, tt.key, got, tt.want)
+		}
+	}
+}
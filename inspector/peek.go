@@ -0,0 +1,110 @@
+package inspector
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Peek is a single element read from a list without loading the rest of it.
+type Peek struct {
+	Key   string
+	Size  int64
+	Index int64
+	Value string
+	Found bool // False if the element could not be read (e.g. the list was emptied)
+}
+
+// PeekLists reads one element from each list using a single pipeline of
+// LINDEX calls. indexFor chooses the index to read for each key, so callers
+// can pick the newest end per list.
+func PeekLists(ctx context.Context, client redis.UniversalClient, lists []ListInfo, indexFor func(key string) int64) ([]Peek, error) {
+	peeks := make([]Peek, len(lists))
+	for i, list := range lists {
+		peeks[i] = Peek{Key: list.Name, Size: list.Size, Index: indexFor(list.Name)}
+	}
+	return readPeeks(ctx, client, peeks)
+}
+
+// PeekIndices reads the elements at the given indices of the list at key
+// using a single pipeline of LINDEX calls, without loading the rest of the
+// list. size is recorded on each Peek as the list's length.
+func PeekIndices(ctx context.Context, client redis.UniversalClient, key string, size int64, indices []int64) ([]Peek, error) {
+	peeks := make([]Peek, len(indices))
+	for i, index := range indices {
+		peeks[i] = Peek{Key: key, Size: size, Index: index}
+	}
+	return readPeeks(ctx, client, peeks)
+}
+
+// SampleIndices returns n indices spread evenly over a list of length size,
+// from the head (0) to the tail (size-1), for a cheap overview of a list too
+// large to load. Shorter lists yield every index once.
+func SampleIndices(size int64, n int) []int64 {
+	if size <= 0 || n <= 0 {
+		return nil
+	}
+	if int64(n) >= size {
+		n = int(size)
+	}
+	if n == 1 {
+		return []int64{0}
+	}
+	indices := make([]int64, n)
+	for i := range indices {
+		// Rounded, so the spacing stays even and the last index is the tail
+		indices[i] = (int64(i)*(size-1)*2 + int64(n-1)) / (int64(n-1) * 2)
+	}
+	return indices
+}
+
+// readPeeks fills in the value of each peek with one pipeline of LINDEX calls.
+func readPeeks(ctx context.Context, client redis.UniversalClient, peeks []Peek) ([]Peek, error) {
+	if len(peeks) == 0 {
+		return nil, nil
+	}
+
+	pipe := client.Pipeline()
+	cmds := make([]*redis.StringCmd, len(peeks))
+	for i, peek := range peeks {
+		cmds[i] = pipe.LIndex(ctx, peek.Key, peek.Index)
+	}
+	// Individual LINDEX failures (e.g. redis.Nil) are reported per peek below
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		allFailed := true
+		for _, cmd := range cmds {
+			if cmd.Err() == nil || cmd.Err() == redis.Nil {
+				allFailed = false
+				break
+			}
+		}
+		if allFailed {
+			return nil, err
+		}
+	}
+
+	for i, cmd := range cmds {
+		if value, err := cmd.Result(); err == nil {
+			peeks[i].Value = value
+			peeks[i].Found = true
+		}
+	}
+	return peeks, nil
+}
+
+// Truncate shortens s to at most n runes, appending an ellipsis when
+// anything was cut. It never splits a multibyte character.
+func Truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	count := 0
+	for i := range s {
+		if count == n {
+			return s[:i] + "…"
+		}
+		count++
+	}
+	return s
+}
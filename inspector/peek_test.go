@@ -0,0 +1,95 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestPeekLists(t *testing.T) {
+	mr, client := newTestClient(t)
+	ctx := context.Background()
+	mr.RPush("jobs:a", "old-a", "new-a")
+	mr.RPush("jobs:b", "only-b")
+	mr.RPush("other", "x")
+
+	lists, err := MatchingLists(ctx, client, "jobs:*", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lists) != 2 {
+		t.Fatalf("expected 2 matching lists, got %+v", lists)
+	}
+
+	peeks, err := PeekLists(ctx, client, lists, func(string) int64 { return -1 })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	values := make(map[string]string)
+	for _, p := range peeks {
+		if !p.Found {
+			t.Errorf("expected element for %s", p.Key)
+		}
+		values[p.Key] = p.Value
+	}
+	if values["jobs:a"] != "new-a" || values["jobs:b"] != "only-b" {
+		t.Errorf("expected newest element of each list, got %v", values)
+	}
+}
+
+func TestPeekIndices(t *testing.T) {
+	mr, client := newTestClient(t)
+	mr.RPush("jobs", "a", "b", "c", "d")
+
+	peeks, err := PeekIndices(context.Background(), client, "jobs", 4, []int64{3, 0, -2, 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []string
+	for _, p := range peeks {
+		got = append(got, p.Value)
+	}
+	if len(peeks) != 4 || got[0] != "d" || got[1] != "a" || got[2] != "c" || peeks[3].Found {
+		t.Errorf("unexpected peeks: %+v", peeks)
+	}
+}
+
+func TestSampleIndices(t *testing.T) {
+	tests := []struct {
+		size int64
+		n    int
+		want []int64
+	}{
+		{101, 5, []int64{0, 25, 50, 75, 100}},
+		{1000000, 3, []int64{0, 500000, 999999}},
+		{10, 4, []int64{0, 3, 6, 9}},
+		{3, 20, []int64{0, 1, 2}},
+		{50, 1, []int64{0}},
+		{0, 20, nil},
+	}
+	for _, tt := range tests {
+		got := SampleIndices(tt.size, tt.n)
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("SampleIndices(%d, %d) = %v, want %v", tt.size, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		in   string
+		n    int
+		want string
+	}{
+		{"short", 10, "short"},
+		{"exactly", 7, "exactly"},
+		{"truncate me", 8, "truncate…"},
+		{"héllo wörld", 4, "héll…"},
+		{"日本語テキスト", 3, "日本語…"},
+	}
+	for _, tt := range tests {
+		if got := Truncate(tt.in, tt.n); got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.in, tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// PersistenceStatus reports how a Redis server saves its data to disk.
+type PersistenceStatus struct {
+	AOF bool // appendonly is enabled
+	RDB bool // At least one "save" snapshot rule is configured
+}
+
+// Ephemeral reports whether the server persists nothing, so its data is lost
+// on restart.
+func (p *PersistenceStatus) Ephemeral() bool {
+	return !p.AOF && !p.RDB
+}
+
+// Persistence reads whether AOF is enabled from INFO persistence and whether
+// RDB snapshots are scheduled from CONFIG GET save. Managed services often
+// disable CONFIG, in which case an error is returned rather than guessing.
+func Persistence(ctx context.Context, client redis.UniversalClient) (*PersistenceStatus, error) {
+	info, err := client.Info(ctx, "persistence").Result()
+	if err != nil {
+		return nil, fmt.Errorf("reading INFO persistence: %w", err)
+	}
+	aof, ok := infoField(info, "aof_enabled")
+	if !ok {
+		return nil, fmt.Errorf("INFO persistence has no aof_enabled field")
+	}
+
+	config, err := client.ConfigGet(ctx, "save").Result()
+	if err != nil {
+		return nil, fmt.Errorf("reading CONFIG GET save: %w", err)
+	}
+	return &PersistenceStatus{
+		AOF: aof == "1",
+		RDB: strings.TrimSpace(config["save"]) != "",
+	}, nil
+}
+
+// infoField returns the value of name in an INFO reply, whose lines are
+// "name:value" under "# Section" headings.
+func infoField(info, name string) (string, bool) {
+	for _, line := range strings.Split(info, "\n") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(line), name+":"); ok {
+			return value, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,34 @@
+package inspector
+
+import (
+	"context"
+	"testing"
+)
+
+func TestInfoField(t *testing.T) {
+	info := "# Persistence\r\nloading:0\r\naof_enabled:1\r\naof_rewrite_in_progress:0\r\n"
+	if value, ok := infoField(info, "aof_enabled"); !ok || value != "1" {
+		t.Errorf("expected aof_enabled 1, got %q (%v)", value, ok)
+	}
+	if _, ok := infoField(info, "aof"); ok {
+		t.Error("expected a prefix of a field name not to match")
+	}
+}
+
+func TestPersistenceStatus_Ephemeral(t *testing.T) {
+	if !(&PersistenceStatus{}).Ephemeral() {
+		t.Error("expected no AOF and no RDB to be ephemeral")
+	}
+	if (&PersistenceStatus{RDB: true}).Ephemeral() {
+		t.Error("expected RDB snapshots to count as persistence")
+	}
+}
+
+func TestPersistence_Unsupported(t *testing.T) {
+	// miniredis supports neither INFO persistence nor CONFIG, like some
+	// managed services, so the check must fail rather than report ephemeral
+	_, client := newTestClient(t)
+	if status, err := Persistence(context.Background(), client); err == nil {
+		t.Errorf("expected an error, got %+v", status)
+	}
+}
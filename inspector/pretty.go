@@ -0,0 +1,331 @@
+package inspector
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DepthElided replaces objects and arrays nested deeper than the maximum
+// depth passed to PrettyPrintDepth.
+const DepthElided = "[deeper levels hidden]"
+
+// PrettyPrint indents value if it is valid JSON, returning it unchanged
+// otherwise.
+func PrettyPrint(value string) string {
+	return PrettyPrintDepth(value, 0)
+}
+
+// PrettyPrintDepth is PrettyPrint, but objects and arrays nested more than
+// maxDepth levels deep are replaced with DepthElided so pathological payloads
+// stay readable. A maxDepth of 0 means unlimited.
+func PrettyPrintDepth(value string, maxDepth int) string {
+	var jsonData interface{}
+	if err := json.Unmarshal([]byte(value), &jsonData); err != nil {
+		// Not valid JSON, return as-is
+		return value
+	}
+	if maxDepth > 0 {
+		jsonData = elideDeeper(jsonData, 1, maxDepth)
+	}
+
+	prettyJSON, err := json.MarshalIndent(jsonData, "", "  ")
+	if err != nil {
+		// Fallback to original value
+		return value
+	}
+
+	return string(prettyJSON)
+}
+
+// PrettyPrintStored is PrettyPrintDepth, but object keys keep the order they
+// were stored in rather than being sorted, and numbers keep their original
+// text (1.50 stays 1.50, large integers are not rounded). Duplicate keys are
+// all shown. The value is decoded token by token, so this is slower than
+// PrettyPrintDepth on large documents.
+func PrettyPrintStored(value string, maxDepth int) string {
+	dec := json.NewDecoder(strings.NewReader(value))
+	dec.UseNumber()
+	jsonData, err := decodeOrdered(dec)
+	if err != nil {
+		return value
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		// Trailing data after the document, which json.Unmarshal also rejects
+		return value
+	}
+	if maxDepth > 0 {
+		jsonData = elideDeeper(jsonData, 1, maxDepth)
+	}
+
+	prettyJSON, err := json.MarshalIndent(jsonData, "", "  ")
+	if err != nil {
+		return value
+	}
+	return string(prettyJSON)
+}
+
+// orderedObject is a JSON object whose fields are kept in document order.
+type orderedObject []orderedField
+
+type orderedField struct {
+	Key   string
+	Value interface{}
+}
+
+// MarshalJSON writes the fields in order.
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(field.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(field.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// decodeOrdered reads the next JSON value from dec, decoding objects as
+// orderedObject and arrays as []interface{}.
+func decodeOrdered(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		// A string, json.Number, bool or nil
+		return tok, nil
+	}
+
+	switch delim {
+	case '{':
+		obj := orderedObject{}
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, orderedField{Key: keyTok.(string), Value: value})
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return obj, nil
+	case '[':
+		arr := []interface{}{}
+		for dec.More() {
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, value)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return arr, nil
+	}
+	return nil, fmt.Errorf("unexpected %v", delim)
+}
+
+// elideDeeper returns v, which sits at the given depth, with non-empty
+// containers below maxDepth replaced by DepthElided.
+func elideDeeper(v interface{}, depth, maxDepth int) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if len(v) > 0 && depth > maxDepth {
+			return DepthElided
+		}
+		for k, child := range v {
+			v[k] = elideDeeper(child, depth+1, maxDepth)
+		}
+	case orderedObject:
+		if len(v) > 0 && depth > maxDepth {
+			return DepthElided
+		}
+		for i := range v {
+			v[i].Value = elideDeeper(v[i].Value, depth+1, maxDepth)
+		}
+	case []interface{}:
+		if len(v) > 0 && depth > maxDepth {
+			return DepthElided
+		}
+		for i, child := range v {
+			v[i] = elideDeeper(child, depth+1, maxDepth)
+		}
+	}
+	return v
+}
+
+// PrettyCache is a bounded LRU cache of pretty-printed values keyed by a hash
+// of the raw value, so re-rendering unchanged list elements skips the JSON
+// parse/marshal round trip. A nil *PrettyCache is valid and caches nothing.
+type PrettyCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[[sha256.Size]byte]*list.Element
+}
+
+type prettyCacheEntry struct {
+	key   [sha256.Size]byte
+	value string
+}
+
+// NewPrettyCache returns a cache holding at most capacity entries.
+func NewPrettyCache(capacity int) *PrettyCache {
+	return &PrettyCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[[sha256.Size]byte]*list.Element, capacity),
+	}
+}
+
+// PrettyPrint returns PrettyPrint(value), served from the cache when the same
+// raw value has been rendered before.
+func (c *PrettyCache) PrettyPrint(value string) string {
+	return c.PrettyPrintDepth(value, 0)
+}
+
+// PrettyPrintDepth returns PrettyPrintDepth(value, maxDepth), served from the
+// cache when the same raw value has been rendered at that depth before.
+func (c *PrettyCache) PrettyPrintDepth(value string, maxDepth int) string {
+	if c == nil {
+		return PrettyPrintDepth(value, maxDepth)
+	}
+
+	key := cacheKey(value, maxDepth, false)
+	if cached, ok := c.get(key); ok {
+		return cached
+	}
+	result := PrettyPrintDepth(value, maxDepth)
+	c.put(key, result)
+	return result
+}
+
+// PrettyPrintStored returns PrettyPrintStored(value, maxDepth), served from
+// the cache when the same raw value has been rendered that way before.
+func (c *PrettyCache) PrettyPrintStored(value string, maxDepth int) string {
+	if c == nil {
+		return PrettyPrintStored(value, maxDepth)
+	}
+
+	key := cacheKey(value, maxDepth, true)
+	if cached, ok := c.get(key); ok {
+		return cached
+	}
+	result := PrettyPrintStored(value, maxDepth)
+	c.put(key, result)
+	return result
+}
+
+// cacheKey identifies a raw value rendered at a given depth, with keys sorted
+// or in stored order.
+func cacheKey(raw string, maxDepth int, stored bool) string {
+	if maxDepth == 0 && !stored {
+		return raw
+	}
+	prefix := strconv.Itoa(maxDepth)
+	if stored {
+		prefix += "s"
+	}
+	return prefix + "\x00" + raw
+}
+
+// Len returns the number of cached entries.
+func (c *PrettyCache) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Capacity returns the maximum number of cached entries, 0 for a nil cache.
+func (c *PrettyCache) Capacity() int {
+	if c == nil {
+		return 0
+	}
+	return c.capacity
+}
+
+// get returns the cached rendering of raw, if present.
+func (c *PrettyCache) get(raw string) (string, bool) {
+	key := sha256.Sum256([]byte(raw))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*prettyCacheEntry).value, true
+}
+
+// put stores the rendering of raw, evicting the least recently used entry
+// when the cache is full.
+func (c *PrettyCache) put(raw, value string) {
+	key := sha256.Sum256([]byte(raw))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*prettyCacheEntry).value = value
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&prettyCacheEntry{key: key, value: value})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*prettyCacheEntry).key)
+	}
+}
+
+// ScalarKind reports whether value is a bare JSON scalar, returning
+// "number", "boolean" or "null", or "" for anything else (objects, arrays,
+// quoted strings and non-JSON text). Such values look the same pretty-printed
+// as raw, so the kind helps confirm what was stored.
+func ScalarKind(value string) string {
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+		return ""
+	}
+	switch parsed.(type) {
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	}
+	return ""
+}
@@ -0,0 +1,198 @@
+package inspector
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPrettyPrint_ValidJSON(t *testing.T) {
+	result := PrettyPrint(`{"name":"Alice","age":30}`)
+	if !strings.Contains(result, "\n") || !strings.Contains(result, `"name"`) {
+		t.Errorf("expected pretty-printed JSON, got: %s", result)
+	}
+}
+
+func TestPrettyPrint_InvalidJSON(t *testing.T) {
+	if result := PrettyPrint("not json"); result != "not json" {
+		t.Errorf("expected original string for invalid JSON, got: %s", result)
+	}
+}
+
+func TestPrettyCache_GetPut(t *testing.T) {
+	c := NewPrettyCache(2)
+	c.put("a", "A")
+	if v, ok := c.get("a"); !ok || v != "A" {
+		t.Errorf("expected cached value 'A', got %q (ok=%v)", v, ok)
+	}
+	if _, ok := c.get("missing"); ok {
+		t.Errorf("expected miss for uncached value")
+	}
+}
+
+func TestPrettyCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewPrettyCache(2)
+	c.put("a", "A")
+	c.put("b", "B")
+	c.get("a") // "b" is now least recently used
+	c.put("c", "C")
+
+	if _, ok := c.get("b"); ok {
+		t.Errorf("expected 'b' to be evicted")
+	}
+	if _, ok := c.get("a"); !ok {
+		t.Errorf("expected 'a' to remain cached")
+	}
+	if c.Len() != 2 {
+		t.Errorf("expected 2 entries, got %d", c.Len())
+	}
+}
+
+func TestPrettyCache_PrettyPrint(t *testing.T) {
+	c := NewPrettyCache(10)
+
+	input := `{"name":"Alice"}`
+	first := c.PrettyPrint(input)
+	if c.Len() != 1 {
+		t.Fatalf("expected value to be cached, got %d entries", c.Len())
+	}
+	if second := c.PrettyPrint(input); second != first {
+		t.Errorf("expected cached result %q, got %q", first, second)
+	}
+
+	var disabled *PrettyCache
+	if got := disabled.PrettyPrint(input); got != first {
+		t.Errorf("expected nil cache to pretty-print directly, got %q", got)
+	}
+}
+
+// largeJSONValues builds n distinct JSON objects of a few KB each.
+func largeJSONValues(n int) []string {
+	values := make([]string, n)
+	for i := range values {
+		items := make([]map[string]interface{}, 50)
+		for j := range items {
+			items[j] = map[string]interface{}{
+				"id":    fmt.Sprintf("item-%d-%d", i, j),
+				"price": float64(j) * 1.25,
+				"tags":  []string{"alpha", "beta", "gamma"},
+			}
+		}
+		b, _ := json.Marshal(map[string]interface{}{"order": i, "items": items})
+		values[i] = string(b)
+	}
+	return values
+}
+
+func BenchmarkPrettyPrintJSON_Uncached(b *testing.B) {
+	var c *PrettyCache
+	values := largeJSONValues(100)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, v := range values {
+			c.PrettyPrint(v)
+		}
+	}
+}
+
+func BenchmarkPrettyPrintJSON_Cached(b *testing.B) {
+	c := NewPrettyCache(1000)
+	values := largeJSONValues(100)
+	for _, v := range values {
+		c.PrettyPrint(v)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, v := range values {
+			c.PrettyPrint(v)
+		}
+	}
+}
+
+func TestScalarKind(t *testing.T) {
+	tests := map[string]string{
+		"42":         "number",
+		" -1.5e3 ":   "number",
+		"true":       "boolean",
+		"false":      "boolean",
+		"null":       "null",
+		`"quoted"`:   "",
+		`{"a":1}`:    "",
+		"[1,2]":      "",
+		"plain text": "",
+		"":           "",
+		"12 monkeys": "",
+	}
+	for input, want := range tests {
+		if got := ScalarKind(input); got != want {
+			t.Errorf("ScalarKind(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestPrettyPrintDepth(t *testing.T) {
+	input := `{"a": {"b": {"c": 1}}, "list": [[1], []], "n": 1}`
+
+	if got, want := PrettyPrintDepth(input, 0), PrettyPrint(input); got != want {
+		t.Errorf("expected depth 0 to be unlimited, got %s", got)
+	}
+
+	got := PrettyPrintDepth(input, 2)
+	if !strings.Contains(got, `"b": "`+DepthElided+`"`) {
+		t.Errorf("expected the third level to be elided, got %s", got)
+	}
+	if !strings.Contains(got, `"`+DepthElided+`",`) || !strings.Contains(got, "[]") {
+		t.Errorf("expected non-empty nested arrays elided and empty ones kept, got %s", got)
+	}
+	if !strings.Contains(got, `"n": 1`) {
+		t.Errorf("expected shallow scalars to be kept, got %s", got)
+	}
+
+	cache := NewPrettyCache(10)
+	if cache.PrettyPrintDepth(input, 1) == cache.PrettyPrint(input) {
+		t.Errorf("expected renderings at different depths to be cached separately")
+	}
+}
+
+func TestPrettyPrintStored(t *testing.T) {
+	input := `{"zeta":1.50,"alpha":{"b":[1,{}],"a":[]},"big":12345678901234567890,"alpha":null}`
+	want := `{
+  "zeta": 1.50,
+  "alpha": {
+    "b": [
+      1,
+      {}
+    ],
+    "a": []
+  },
+  "big": 12345678901234567890,
+  "alpha": null
+}`
+	if got := PrettyPrintStored(input, 0); got != want {
+		t.Errorf("expected stored order, got:\n%s", got)
+	}
+
+	if got := PrettyPrintStored(`{"b":{"c":1},"a":2}`, 1); !strings.Contains(got, `"b": "`+DepthElided+`"`) || strings.Index(got, `"b"`) > strings.Index(got, `"a"`) {
+		t.Errorf("expected deeper levels hidden in stored order, got:\n%s", got)
+	}
+
+	for _, invalid := range []string{"not json", `{"a":1} trailing`, `{"a":`, `[1,]`} {
+		if got := PrettyPrintStored(invalid, 0); got != invalid {
+			t.Errorf("expected %q unchanged, got %q", invalid, got)
+		}
+	}
+}
+
+func TestPrettyCache_StoredOrderCachedSeparately(t *testing.T) {
+	c := NewPrettyCache(10)
+	input := `{"b":1,"a":2}`
+	sorted := c.PrettyPrint(input)
+	stored := c.PrettyPrintStored(input, 0)
+	if sorted == stored || strings.Index(stored, `"b"`) > strings.Index(stored, `"a"`) {
+		t.Errorf("expected distinct renderings, got sorted %q and stored %q", sorted, stored)
+	}
+	if c.Len() != 2 {
+		t.Errorf("expected 2 entries, got %d", c.Len())
+	}
+}
@@ -0,0 +1,115 @@
+package inspector
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// SchemaRule validates elements of lists whose key matches Pattern against the
+// JSON Schema at Path.
+type SchemaRule struct {
+	Pattern *Pattern
+	Path    string
+	schema  *jsonschema.Schema
+}
+
+// SchemaReport is the outcome of validating every element of a list.
+type SchemaReport struct {
+	Schema  string   // Path of the schema the list was validated against
+	Errors  []string // Validation errors per element, "" when the element conforms
+	Failing []int64  // Indices of non-conforming elements
+}
+
+// ParseSchemaRules parses a JSON array of
+// {"pattern": "...", "schema": "/path/to/schema.json"} objects, compiling each
+// schema up front.
+func ParseSchemaRules(config string) ([]SchemaRule, error) {
+	var entries []struct {
+		Pattern string `json:"pattern"`
+		Schema  string `json:"schema"`
+	}
+	if err := json.Unmarshal([]byte(config), &entries); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	rules := make([]SchemaRule, 0, len(entries))
+	for _, entry := range entries {
+		pattern, err := CompilePattern(entry.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", entry.Pattern, err)
+		}
+		schema, err := jsonschema.Compile(entry.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("schema for pattern %q: %w", entry.Pattern, err)
+		}
+		rules = append(rules, SchemaRule{Pattern: pattern, Path: entry.Schema, schema: schema})
+	}
+	return rules, nil
+}
+
+// MatchSchema returns the first rule matching key, or nil if none applies.
+func MatchSchema(rules []SchemaRule, key string) *SchemaRule {
+	for i := range rules {
+		if rules[i].Pattern.Match(key) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// ValidateList checks each raw value against the rule's schema.
+func (r *SchemaRule) ValidateList(values []string) *SchemaReport {
+	report := &SchemaReport{Schema: r.Path, Errors: make([]string, len(values))}
+	for i, value := range values {
+		if err := r.Validate(value); err != nil {
+			report.Errors[i] = err.Error()
+			report.Failing = append(report.Failing, int64(i))
+		}
+	}
+	return report
+}
+
+// Validate checks a single raw value, returning a readable description of
+// every violation.
+func (r *SchemaRule) Validate(value string) error {
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return fmt.Errorf("not valid JSON: %v", err)
+	}
+	if decoder.More() {
+		return errors.New("not valid JSON: trailing data")
+	}
+
+	err := r.schema.Validate(doc)
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return err
+	}
+
+	var msg bytes.Buffer
+	var collect func(*jsonschema.ValidationError)
+	collect = func(ve *jsonschema.ValidationError) {
+		if len(ve.Causes) == 0 {
+			location := ve.InstanceLocation
+			if location == "" {
+				location = "/"
+			}
+			if msg.Len() > 0 {
+				msg.WriteString("\n")
+			}
+			fmt.Fprintf(&msg, "%s: %s", location, ve.Message)
+		}
+		for _, cause := range ve.Causes {
+			collect(cause)
+		}
+	}
+	collect(validationErr)
+	return errors.New(msg.String())
+}
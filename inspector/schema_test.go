@@ -0,0 +1,61 @@
+package inspector
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSchema(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "order.json")
+	schema := `{
+		"type": "object",
+		"required": ["id", "status"],
+		"properties": {
+			"id": {"type": "integer"},
+			"status": {"enum": ["ok", "failed"]}
+		}
+	}`
+	if err := os.WriteFile(path, []byte(schema), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSchemaRule_ValidateList(t *testing.T) {
+	rules, err := ParseSchemaRules(`[{"pattern":"orders:*","schema":"` + writeSchema(t) + `"}]`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	report := rules[0].ValidateList([]string{
+		`{"id": 1, "status": "ok"}`,
+		`{"id": "two", "status": "ok"}`,
+		`{"id": 3}`,
+		`not json`,
+	})
+
+	if len(report.Failing) != 3 || report.Failing[0] != 1 {
+		t.Fatalf("expected elements 1-3 to fail, got %v", report.Failing)
+	}
+	if report.Errors[0] != "" {
+		t.Errorf("expected element 0 to conform, got: %s", report.Errors[0])
+	}
+	if !strings.Contains(report.Errors[1], "/id") {
+		t.Errorf("expected error to locate the bad field, got: %s", report.Errors[1])
+	}
+	if !strings.Contains(report.Errors[2], "status") {
+		t.Errorf("expected missing-property error, got: %s", report.Errors[2])
+	}
+	if !strings.Contains(report.Errors[3], "not valid JSON") {
+		t.Errorf("expected invalid JSON error, got: %s", report.Errors[3])
+	}
+}
+
+func TestLoadSchemaRules_InvalidSchemaPath(t *testing.T) {
+	if _, err := ParseSchemaRules(`[{"pattern":"*","schema":"/does/not/exist.json"}]`); err == nil {
+		t.Error("expected an error for a missing schema file")
+	}
+}
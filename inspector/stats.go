@@ -0,0 +1,78 @@
+package inspector
+
+import (
+	"context"
+	"sort"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// KeyspaceStats summarises the composition of a Redis database.
+type KeyspaceStats struct {
+	// DBSize is the total number of keys reported by DBSIZE.
+	DBSize int64
+	// Scanned is the number of keys whose type was counted.
+	Scanned int64
+	// Complete reports whether the scan covered the whole keyspace rather
+	// than stopping at the scan limit.
+	Complete bool
+	// Types counts scanned keys by Redis type (list, hash, set, zset,
+	// string, stream).
+	Types map[string]int64
+	// LargestLists holds the biggest lists seen, largest first.
+	LargestLists []ListInfo
+	// FailedShards lists the Redis Cluster shards that could not be
+	// scanned, whose keys are not counted. It is empty for a single server.
+	FailedShards []ShardError
+}
+
+// Stats scans up to scanLimit keys, counting them by type and keeping the top
+// largest lists. The scan may overshoot scanLimit by up to one SCAN batch.
+func Stats(ctx context.Context, client redis.UniversalClient, scanLimit, top int) (*KeyspaceStats, error) {
+	dbSize, err := client.DBSize(ctx).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &KeyspaceStats{DBSize: dbSize, Complete: true, Types: make(map[string]int64)}
+	stats.FailedShards, err = scanTypes(ctx, client, "*", make(map[string]struct{}), func(keys, types []string) bool {
+		var listKeys []string
+		for i, key := range keys {
+			if types[i] == "" || types[i] == "none" {
+				// Unreadable, or expired between SCAN and TYPE
+				continue
+			}
+			stats.Scanned++
+			stats.Types[types[i]]++
+			if types[i] == "list" {
+				listKeys = append(listKeys, key)
+			}
+		}
+		stats.LargestLists = largestLists(append(stats.LargestLists, listSizes(ctx, client, listKeys)...), top)
+
+		if stats.Scanned >= int64(scanLimit) {
+			stats.Complete = false
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// largestLists sorts lists by size, largest first (ties by name), and keeps
+// at most n of them.
+func largestLists(lists []ListInfo, n int) []ListInfo {
+	sort.Slice(lists, func(i, j int) bool {
+		if lists[i].Size != lists[j].Size {
+			return lists[i].Size > lists[j].Size
+		}
+		return lists[i].Name < lists[j].Name
+	})
+	if len(lists) > n {
+		lists = lists[:n]
+	}
+	return lists
+}
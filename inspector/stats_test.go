@@ -0,0 +1,46 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	mr, client := newTestClient(t)
+	mr.RPush("small", "1")
+	mr.RPush("big", "1", "2", "3")
+	mr.RPush("medium", "1", "2")
+	mr.Set("plain", "value")
+	mr.HSet("hash", "f", "v")
+	mr.SAdd("set", "m")
+
+	stats, err := Stats(context.Background(), client, 1000, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.DBSize != 6 || stats.Scanned != 6 || !stats.Complete {
+		t.Errorf("unexpected totals: %+v", stats)
+	}
+	if stats.Types["list"] != 3 || stats.Types["string"] != 1 || stats.Types["hash"] != 1 || stats.Types["set"] != 1 {
+		t.Errorf("unexpected type counts: %v", stats.Types)
+	}
+	if len(stats.LargestLists) != 2 || stats.LargestLists[0].Name != "big" || stats.LargestLists[1].Name != "medium" {
+		t.Errorf("expected the two largest lists, got %+v", stats.LargestLists)
+	}
+}
+
+func TestStats_ScanLimit(t *testing.T) {
+	mr, client := newTestClient(t)
+	for i := 0; i < 250; i++ {
+		mr.Set(fmt.Sprintf("key:%d", i), "v")
+	}
+
+	stats, err := Stats(context.Background(), client, 10, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.Complete {
+		t.Errorf("expected a partial scan, got %+v", stats)
+	}
+}
@@ -0,0 +1,129 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// StreamInfo summarizes a stream and the state of its consumer groups.
+type StreamInfo struct {
+	Length int64
+	Groups []StreamGroup
+}
+
+// StreamGroup is one consumer group of a stream, from XINFO GROUPS.
+type StreamGroup struct {
+	Name            string
+	LastDeliveredID string
+	Pending         int64 // Entries delivered but not yet acknowledged (the PEL)
+	EntriesRead     int64
+	Lag             int64 // Entries not yet delivered to the group, -1 when unknown
+	Consumers       []StreamConsumer
+}
+
+// StreamConsumer is one consumer in a group, from XINFO CONSUMERS.
+type StreamConsumer struct {
+	Name    string
+	Pending int64
+	Idle    time.Duration // Time since the consumer last interacted with the group
+}
+
+// StreamEntry is one entry of a stream.
+type StreamEntry struct {
+	ID     string
+	Fields map[string]interface{}
+}
+
+// StreamIDTime decodes the time an entry was added from its ID, which Redis
+// generates as <milliseconds since the epoch>-<sequence>. It reports false
+// for IDs that are not of that form, such as 0-0 or a bare sequence.
+func StreamIDTime(id string) (time.Time, bool) {
+	msStr, seqStr, ok := strings.Cut(id, "-")
+	if !ok {
+		return time.Time{}, false
+	}
+	ms, err := strconv.ParseInt(msStr, 10, 64)
+	if err != nil || ms <= 0 {
+		return time.Time{}, false
+	}
+	if _, err := strconv.ParseUint(seqStr, 10, 64); err != nil {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(ms), true
+}
+
+// RecentStreamEntries returns up to count of the newest entries of the stream
+// at key, newest first.
+func RecentStreamEntries(ctx context.Context, client redis.UniversalClient, key string, count int64) ([]StreamEntry, error) {
+	messages, err := client.XRevRangeN(ctx, key, "+", "-", count).Result()
+	if err != nil {
+		return nil, fmt.Errorf("reading stream entries: %w", err)
+	}
+	entries := make([]StreamEntry, len(messages))
+	for i, message := range messages {
+		entries[i] = StreamEntry{ID: message.ID, Fields: message.Values}
+	}
+	return entries, nil
+}
+
+// InspectStream reads the length and consumer group state of the stream at
+// key. It returns ErrKeyNotFound or a *WrongTypeError when the key cannot be
+// inspected as a stream.
+func InspectStream(ctx context.Context, client redis.UniversalClient, key string) (*StreamInfo, error) {
+	keyType, err := KeyType(ctx, client, key)
+	if err != nil {
+		return nil, fmt.Errorf("checking key: %w", err)
+	}
+	if keyType == "none" {
+		return nil, ErrKeyNotFound
+	}
+	if keyType != "stream" {
+		return nil, &WrongTypeError{Type: keyType}
+	}
+
+	length, err := client.XLen(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("reading stream length: %w", err)
+	}
+	groups, err := client.XInfoGroups(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("reading consumer groups: %w", err)
+	}
+	info := &StreamInfo{Length: length, Groups: make([]StreamGroup, len(groups))}
+	if len(groups) == 0 {
+		return info, nil
+	}
+
+	// Fetch every group's consumers in one round trip
+	pipe := client.Pipeline()
+	consumerCmds := make([]*redis.XInfoConsumersCmd, len(groups))
+	for i, group := range groups {
+		consumerCmds[i] = pipe.XInfoConsumers(ctx, key, group.Name)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("reading consumers: %w", err)
+	}
+
+	for i, group := range groups {
+		info.Groups[i] = StreamGroup{
+			Name:            group.Name,
+			LastDeliveredID: group.LastDeliveredID,
+			Pending:         group.Pending,
+			EntriesRead:     group.EntriesRead,
+			Lag:             group.Lag,
+		}
+		for _, consumer := range consumerCmds[i].Val() {
+			info.Groups[i].Consumers = append(info.Groups[i].Consumers, StreamConsumer{
+				Name:    consumer.Name,
+				Pending: consumer.Pending,
+				Idle:    consumer.Idle,
+			})
+		}
+	}
+	return info, nil
+}
@@ -0,0 +1,76 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestInspectStream(t *testing.T) {
+	mr, client := newTestClient(t)
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if err := client.XAdd(ctx, &redis.XAddArgs{Stream: "jobs", Values: []string{"n", "1"}}).Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := client.XGroupCreate(ctx, "jobs", "workers", "0").Err(); err != nil {
+		t.Fatal(err)
+	}
+	// One consumer reads two entries without acknowledging them
+	if err := client.XReadGroup(ctx, &redis.XReadGroupArgs{Group: "workers", Consumer: "w1", Streams: []string{"jobs", ">"}, Count: 2}).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := InspectStream(ctx, client, "jobs")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Length != 3 || len(info.Groups) != 1 {
+		t.Fatalf("unexpected stream info %+v", info)
+	}
+	group := info.Groups[0]
+	if group.Name != "workers" || group.Pending != 2 || len(group.Consumers) != 1 || group.Consumers[0].Name != "w1" || group.Consumers[0].Pending != 2 {
+		t.Errorf("unexpected group %+v", group)
+	}
+
+	mr.Set("plain", "x")
+	var wrongType *WrongTypeError
+	if _, err := InspectStream(ctx, client, "plain"); !errors.As(err, &wrongType) {
+		t.Errorf("expected a WrongTypeError, got %v", err)
+	}
+	if _, err := InspectStream(ctx, client, "missing"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("expected ErrKeyNotFound, got %v", err)
+	}
+}
+
+func TestStreamIDTime(t *testing.T) {
+	got, ok := StreamIDTime("1700000000123-4")
+	if !ok || !got.Equal(time.UnixMilli(1700000000123)) {
+		t.Errorf("expected the millisecond part decoded, got %v (%v)", got, ok)
+	}
+	for _, id := range []string{"0-0", "0-1", "abc-1", "1700000000123", "1700000000123-x", ""} {
+		if _, ok := StreamIDTime(id); ok {
+			t.Errorf("expected %q not to decode as a timestamp", id)
+		}
+	}
+}
+
+func TestRecentStreamEntries(t *testing.T) {
+	_, client := newTestClient(t)
+	ctx := context.Background()
+	for _, id := range []string{"1-0", "2-0", "3-0"} {
+		client.XAdd(ctx, &redis.XAddArgs{Stream: "events", ID: id, Values: []string{"n", id}})
+	}
+
+	entries, err := RecentStreamEntries(ctx, client, "events", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != "3-0" || entries[1].ID != "2-0" || entries[0].Fields["n"] != "3-0" {
+		t.Errorf("expected the newest two entries, newest first, got %+v", entries)
+	}
+}
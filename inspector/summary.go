@@ -0,0 +1,80 @@
+package inspector
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SummaryRule summarizes elements of lists whose key matches Pattern as a
+// single line of the values at Fields.
+type SummaryRule struct {
+	Pattern *Pattern
+	Fields  []string
+}
+
+// ParseSummaryRules parses a JSON array of
+// {"pattern": "...", "fields": ["id", "customer.name", ...]} objects.
+func ParseSummaryRules(config string) ([]SummaryRule, error) {
+	var entries []struct {
+		Pattern string   `json:"pattern"`
+		Fields  []string `json:"fields"`
+	}
+	if err := json.Unmarshal([]byte(config), &entries); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	rules := make([]SummaryRule, 0, len(entries))
+	for _, entry := range entries {
+		pattern, err := CompilePattern(entry.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", entry.Pattern, err)
+		}
+		if len(entry.Fields) == 0 {
+			return nil, fmt.Errorf("pattern %q has no fields", entry.Pattern)
+		}
+		rules = append(rules, SummaryRule{Pattern: pattern, Fields: entry.Fields})
+	}
+	return rules, nil
+}
+
+// MatchSummary returns the first rule matching key, or nil if none applies.
+func MatchSummary(rules []SummaryRule, key string) *SummaryRule {
+	for i := range rules {
+		if rules[i].Pattern.Match(key) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// Summarize renders value as "field=value" pairs for each of the rule's fields
+// it contains, e.g. `id=42 status=shipped customer.name=Ada`. It returns ""
+// when value is not JSON or has none of the fields, so callers can fall back
+// to a raw preview.
+func (r *SummaryRule) Summarize(value string) string {
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return ""
+	}
+
+	var parts []string
+	for _, path := range r.Fields {
+		field, ok := LookupField(doc, path)
+		if !ok {
+			continue
+		}
+		text, ok := field.(string)
+		if !ok {
+			encoded, err := json.Marshal(field)
+			if err != nil {
+				continue
+			}
+			text = string(encoded)
+		}
+		parts = append(parts, path+"="+text)
+	}
+	return strings.Join(parts, " ")
+}
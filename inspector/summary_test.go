@@ -0,0 +1,36 @@
+package inspector
+
+import "testing"
+
+func TestSummaryRules(t *testing.T) {
+	rules, err := ParseSummaryRules(`[{"pattern": "orders:*", "fields": ["id", "status", "customer.name", "total"]}]`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rule := MatchSummary(rules, "orders:eu")
+	if rule == nil {
+		t.Fatal("expected orders:eu to match")
+	}
+	if MatchSummary(rules, "jobs") != nil {
+		t.Error("expected no rule for an unmatched key")
+	}
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{`{"id": 42, "status": "shipped", "customer": {"name": "Ada"}, "total": 10.50}`, "id=42 status=shipped customer.name=Ada total=10.50"},
+		{`{"id": 7, "extra": true}`, "id=7"},
+		{`{"other": 1}`, ""},
+		{`not json`, ""},
+	}
+	for _, tt := range tests {
+		if got := rule.Summarize(tt.value); got != tt.want {
+			t.Errorf("Summarize(%s) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+
+	if _, err := ParseSummaryRules(`[{"pattern": "*", "fields": []}]`); err == nil {
+		t.Error("expected an error for a rule without fields")
+	}
+}
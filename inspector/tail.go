@@ -0,0 +1,72 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TailResult is what a list has gained since a previous call to Tail.
+type TailResult struct {
+	Length  int64    // Length of the list now
+	Values  []string // New elements, oldest first
+	Skipped int64    // New elements not returned because more than the batch arrived
+	Reset   bool     // The list shrank (was consumed or trimmed) since the last call
+}
+
+// Tail returns the elements pushed to the list at key since it had seen
+// elements, like tail -f on a log. Lists populated with LPUSH (headIsNewest)
+// grow at the head, others at the tail. At most batch elements are returned,
+// the newest ones; seen < 0 returns only the newest element. The length and
+// elements are read in one transaction so nothing is counted twice. A missing
+// key is treated as an empty list, as Redis deletes lists once drained.
+func Tail(ctx context.Context, client redis.UniversalClient, key string, seen int64, headIsNewest bool, batch int64) (*TailResult, error) {
+	pipe := client.TxPipeline()
+	typeCmd := pipe.Type(ctx, key)
+	llenCmd := pipe.LLen(ctx, key)
+	var rangeCmd *redis.StringSliceCmd
+	if headIsNewest {
+		rangeCmd = pipe.LRange(ctx, key, 0, batch-1)
+	} else {
+		rangeCmd = pipe.LRange(ctx, key, -batch, -1)
+	}
+	_, err := pipe.Exec(ctx)
+	if keyType := typeCmd.Val(); keyType != "" && keyType != "none" && keyType != "list" {
+		return nil, &WrongTypeError{Type: keyType}
+	}
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("reading list: %w", err)
+	}
+
+	result := &TailResult{Length: llenCmd.Val()}
+	if seen < 0 {
+		seen = result.Length - 1
+		if seen < 0 {
+			seen = 0
+		}
+	}
+	if seen > result.Length {
+		result.Reset = true
+		return result, nil
+	}
+
+	values := rangeCmd.Val()
+	added := result.Length - seen
+	if added > int64(len(values)) {
+		result.Skipped = added - int64(len(values))
+		added = int64(len(values))
+	}
+	if headIsNewest {
+		// The newest elements are first, so take them and reverse into
+		// oldest-first order
+		result.Values = make([]string, added)
+		for i := int64(0); i < added; i++ {
+			result.Values[i] = values[added-1-i]
+		}
+	} else {
+		result.Values = values[int64(len(values))-added:]
+	}
+	return result, nil
+}
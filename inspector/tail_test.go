@@ -0,0 +1,67 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTail(t *testing.T) {
+	mr, client := newTestClient(t)
+	ctx := context.Background()
+	mr.RPush("log", "a", "b", "c")
+
+	result, err := Tail(ctx, client, "log", -1, false, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Length != 3 || !reflect.DeepEqual(result.Values, []string{"c"}) {
+		t.Errorf("expected only the newest element first, got %+v", result)
+	}
+
+	mr.RPush("log", "d", "e")
+	result, _ = Tail(ctx, client, "log", 3, false, 10)
+	if !reflect.DeepEqual(result.Values, []string{"d", "e"}) || result.Skipped != 0 {
+		t.Errorf("expected the appended elements, got %+v", result)
+	}
+
+	mr.RPush("log", "f", "g", "h")
+	result, _ = Tail(ctx, client, "log", 5, false, 2)
+	if !reflect.DeepEqual(result.Values, []string{"g", "h"}) || result.Skipped != 1 {
+		t.Errorf("expected the newest batch with one skipped, got %+v", result)
+	}
+
+	mr.Del("log")
+	result, err = Tail(ctx, client, "log", 8, false, 10)
+	if err != nil || !result.Reset || result.Length != 0 {
+		t.Errorf("expected a drained list to report a reset, got %+v (%v)", result, err)
+	}
+}
+
+func TestTail_HeadIsNewest(t *testing.T) {
+	mr, client := newTestClient(t)
+	ctx := context.Background()
+	mr.Lpush("log", "a")
+	mr.Lpush("log", "b")
+
+	mr.Lpush("log", "c")
+	mr.Lpush("log", "d")
+	result, err := Tail(ctx, client, "log", 2, true, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result.Values, []string{"c", "d"}) {
+		t.Errorf("expected LPUSHed elements oldest first, got %+v", result)
+	}
+}
+
+func TestTail_WrongType(t *testing.T) {
+	mr, client := newTestClient(t)
+	mr.Set("plain", "value")
+
+	var wrongType *WrongTypeError
+	if _, err := Tail(context.Background(), client, "plain", -1, false, 10); !errors.As(err, &wrongType) {
+		t.Errorf("expected a WrongTypeError, got %v", err)
+	}
+}
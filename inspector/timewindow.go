@@ -0,0 +1,151 @@
+package inspector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TimestampRule names the JSON field holding each element's timestamp in
+// lists whose key matches Pattern, for lists used as a poor-man's stream.
+type TimestampRule struct {
+	Pattern *Pattern
+	Field   string
+}
+
+// ParseTimestampRules parses a JSON array of
+// {"pattern": "...", "field": "ts"} objects.
+func ParseTimestampRules(config string) ([]TimestampRule, error) {
+	var entries []struct {
+		Pattern string `json:"pattern"`
+		Field   string `json:"field"`
+	}
+	if err := json.Unmarshal([]byte(config), &entries); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	rules := make([]TimestampRule, 0, len(entries))
+	for _, entry := range entries {
+		pattern, err := CompilePattern(entry.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", entry.Pattern, err)
+		}
+		if entry.Field == "" {
+			return nil, fmt.Errorf("pattern %q has no field", entry.Pattern)
+		}
+		rules = append(rules, TimestampRule{Pattern: pattern, Field: entry.Field})
+	}
+	return rules, nil
+}
+
+// MatchTimestamp returns the first rule matching key, or nil if none applies.
+func MatchTimestamp(rules []TimestampRule, key string) *TimestampRule {
+	for i := range rules {
+		if rules[i].Pattern.Match(key) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// ParseTimestamp reads a timestamp field: RFC 3339 text, or a Unix time in
+// seconds, milliseconds, microseconds or nanoseconds, told apart by
+// magnitude. Unix times may be JSON numbers or numeric strings.
+func ParseTimestamp(field interface{}) (time.Time, bool) {
+	var number float64
+	switch v := field.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return time.Time{}, false
+		}
+		number = f
+	case float64:
+		number = v
+	case string:
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return t, true
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+		number = f
+	default:
+		return time.Time{}, false
+	}
+	if math.IsNaN(number) || math.IsInf(number, 0) || number < 0 {
+		return time.Time{}, false
+	}
+
+	// Seconds reach 1e10 in 2286, so larger values are finer units
+	switch {
+	case number < 1e10:
+		return time.Unix(0, int64(number*1e9)), true
+	case number < 1e13:
+		return time.UnixMilli(int64(number)), true
+	case number < 1e16:
+		return time.UnixMicro(int64(number)), true
+	default:
+		return time.Unix(0, int64(number)), true
+	}
+}
+
+// ElementTime returns the timestamp in the field at path of the JSON element
+// value. It returns false when value is not JSON, lacks the field, or the
+// field is not a timestamp ParseTimestamp understands.
+func ElementTime(value, path string) (time.Time, bool) {
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return time.Time{}, false
+	}
+	field, ok := LookupField(doc, path)
+	if !ok {
+		return time.Time{}, false
+	}
+	return ParseTimestamp(field)
+}
+
+// WindowResult lists the elements of a list that fall in a time window.
+type WindowResult struct {
+	Indices []int64 // Indices from the head of the first matching elements, up to the limit
+	Matched int64   // Matching elements, including any beyond the limit
+	Scanned int64   // Elements read
+}
+
+// TimeWindow finds the elements of the list at key whose timestamp at path
+// falls between from and to inclusive, reading the list in batches. A zero
+// from or to leaves that end open. Elements without a valid timestamp are
+// excluded. At most limit indices are returned.
+func TimeWindow(ctx context.Context, client redis.UniversalClient, key, path string, from, to time.Time, limit int) (*WindowResult, error) {
+	result := &WindowResult{}
+	for start := int64(0); ; start += aggregateBatchSize {
+		values, err := client.LRange(ctx, key, start, start+aggregateBatchSize-1).Result()
+		if err != nil {
+			return nil, err
+		}
+		for i, value := range values {
+			t, ok := ElementTime(value, path)
+			if !ok || (!from.IsZero() && t.Before(from)) || (!to.IsZero() && t.After(to)) {
+				continue
+			}
+			result.Matched++
+			if len(result.Indices) < limit {
+				result.Indices = append(result.Indices, start+int64(i))
+			}
+		}
+		result.Scanned += int64(len(values))
+		if len(values) < aggregateBatchSize {
+			break
+		}
+	}
+	return result, nil
+}
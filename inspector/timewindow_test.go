@@ -0,0 +1,73 @@
+package inspector
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTimestampRules(t *testing.T) {
+	rules, err := ParseTimestampRules(`[{"pattern": "logs:*", "field": "meta.ts"}]`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rule := MatchTimestamp(rules, "logs:api"); rule == nil || rule.Field != "meta.ts" {
+		t.Errorf("expected logs:api to match, got %+v", rule)
+	}
+	if MatchTimestamp(rules, "jobs") != nil {
+		t.Error("expected no rule for an unmatched key")
+	}
+	if _, err := ParseTimestampRules(`[{"pattern": "*"}]`); err == nil {
+		t.Error("expected an error for a rule without a field")
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	want := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, field := range []interface{}{
+		"2024-01-02T03:04:05Z",
+		"2024-01-02T04:04:05+01:00",
+		json.Number("1704164645"),
+		json.Number("1704164645000"),
+		json.Number("1704164645000000"),
+		json.Number("1704164645000000000"),
+		"1704164645",
+	} {
+		if got, ok := ParseTimestamp(field); !ok || !got.Equal(want) {
+			t.Errorf("ParseTimestamp(%v) = %v, %v; want %v", field, got, ok, want)
+		}
+	}
+	for _, field := range []interface{}{"yesterday", true, nil, json.Number("-5")} {
+		if _, ok := ParseTimestamp(field); ok {
+			t.Errorf("ParseTimestamp(%v): expected no timestamp", field)
+		}
+	}
+}
+
+func TestTimeWindow(t *testing.T) {
+	mr, client := newTestClient(t)
+	mr.RPush("logs",
+		`{"ts": 1000, "msg": "a"}`,
+		`{"ts": 2000, "msg": "b"}`,
+		`{"msg": "no timestamp"}`,
+		`{"ts": "soon", "msg": "invalid"}`,
+		`not json`,
+		`{"ts": 3000, "msg": "c"}`,
+		`{"ts": 4000, "msg": "d"}`,
+	)
+
+	result, err := TimeWindow(context.Background(), client, "logs", "ts", time.Unix(2000, 0), time.Unix(3000, 0), 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result.Indices, []int64{1, 5}) || result.Matched != 2 || result.Scanned != 7 {
+		t.Errorf("expected the elements within the window, got %+v", result)
+	}
+
+	result, _ = TimeWindow(context.Background(), client, "logs", "ts", time.Unix(2500, 0), time.Time{}, 1)
+	if !reflect.DeepEqual(result.Indices, []int64{5}) || result.Matched != 2 {
+		t.Errorf("expected an open end and the limit to apply, got %+v", result)
+	}
+}
@@ -0,0 +1,41 @@
+package inspector
+
+import (
+	"hash/fnv"
+	"strconv"
+)
+
+// ElementHash identifies a list element by its content, so it can be found
+// again after the list shifts. It is a 64-bit FNV-1a hash in hex: cheap, and
+// collisions only matter between elements of the same list.
+func ElementHash(value string) string {
+	h := fnv.New64a()
+	h.Write([]byte(value))
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// LocateElement returns the index of the element whose ElementHash is hash
+// among values, the elements of a list from index offset on, or -1 if there
+// is none. Of several identical elements, the one nearest hint, where the
+// element was last seen, is returned, as it has most likely moved least.
+func LocateElement(values []string, offset int64, hash string, hint int64) int64 {
+	found := int64(-1)
+	for i, value := range values {
+		index := offset + int64(i)
+		if ElementHash(value) != hash {
+			continue
+		}
+		if found < 0 || distance(index, hint) < distance(found, hint) {
+			found = index
+		}
+	}
+	return found
+}
+
+// distance returns how far apart two indices are.
+func distance(a, b int64) int64 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
@@ -0,0 +1,33 @@
+package inspector
+
+import "testing"
+
+func TestLocateElement(t *testing.T) {
+	values := []string{"a", "b", "c", "d"}
+	hash := ElementHash("c")
+
+	if index := LocateElement(values, 0, hash, 2); index != 2 {
+		t.Errorf("expected the element at its hinted index, got %d", index)
+	}
+
+	// Consuming from the head shifts the element towards it
+	if index := LocateElement(values[2:], 2, hash, 5); index != 2 {
+		t.Errorf("expected the element to be found at its new index, got %d", index)
+	}
+
+	if index := LocateElement(values[3:], 3, hash, 2); index != -1 {
+		t.Errorf("expected a consumed element not to be found, got %d", index)
+	}
+
+	// Of identical elements, the one nearest where it was last seen is picked
+	repeated := []string{"x", "job", "x", "x", "job", "x", "job"}
+	if index := LocateElement(repeated, 10, ElementHash("job"), 13); index != 14 {
+		t.Errorf("expected the copy nearest the hint, got %d", index)
+	}
+}
+
+func TestElementHash(t *testing.T) {
+	if ElementHash("a") == ElementHash("b") || ElementHash("a") != ElementHash("a") {
+		t.Error("expected hashes to identify content")
+	}
+}
@@ -0,0 +1,216 @@
+package inspector
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+)
+
+// Transform converts a raw value into a more readable form. Transforms are
+// chained in a pipeline, each receiving the previous stage's output.
+type Transform func([]byte) ([]byte, error)
+
+// Transforms are the stages available to transform pipelines, by name.
+var Transforms = map[string]Transform{
+	"base64":    decodeBase64,
+	"gzip":      gunzip,
+	"hex":       decodeHex,
+	"hexdump":   hexDump,
+	"json":      indentJSON,
+	"msgpack":   msgpackToJSON,
+	"unquote":   unquote,
+	"unwrap":    unwrap,
+	"urldecode": urlDecode,
+}
+
+// TransformRule applies a pipeline of transforms to keys matching a pattern.
+type TransformRule struct {
+	Pattern *Pattern
+	Stages  []string
+}
+
+// ParseTransformRules parses a JSON array of
+// {"pattern": "...", "transforms": ["base64", "gzip", "json"]} objects.
+func ParseTransformRules(config string) ([]TransformRule, error) {
+	var entries []struct {
+		Pattern    string   `json:"pattern"`
+		Transforms []string `json:"transforms"`
+	}
+	if err := json.Unmarshal([]byte(config), &entries); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	rules := make([]TransformRule, 0, len(entries))
+	for _, entry := range entries {
+		pattern, err := CompilePattern(entry.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", entry.Pattern, err)
+		}
+		if len(entry.Transforms) == 0 {
+			return nil, fmt.Errorf("pattern %q has no transforms", entry.Pattern)
+		}
+		for _, name := range entry.Transforms {
+			if _, ok := Transforms[name]; !ok {
+				return nil, fmt.Errorf("pattern %q uses unknown transform %q", entry.Pattern, name)
+			}
+		}
+		rules = append(rules, TransformRule{Pattern: pattern, Stages: entry.Transforms})
+	}
+	return rules, nil
+}
+
+// MatchTransforms returns the pipeline of the first rule matching key, or nil
+// if none applies.
+func MatchTransforms(rules []TransformRule, key string) []string {
+	for _, rule := range rules {
+		if rule.Pattern.Match(key) {
+			return rule.Stages
+		}
+	}
+	return nil
+}
+
+// ApplyTransforms runs value through the named stages in order. On failure the
+// error identifies the stage that failed.
+func ApplyTransforms(value string, stages []string) (string, error) {
+	data := []byte(value)
+	for i, name := range stages {
+		transform, ok := Transforms[name]
+		if !ok {
+			return "", fmt.Errorf("stage %d: unknown transform %q", i+1, name)
+		}
+		out, err := transform(data)
+		if err != nil {
+			return "", fmt.Errorf("stage %d (%s) failed: %w", i+1, name, err)
+		}
+		data = out
+	}
+	return string(data), nil
+}
+
+func decodeBase64(data []byte) ([]byte, error) {
+	trimmed := bytes.TrimSpace(data)
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if out, err := enc.DecodeString(string(trimmed)); err == nil {
+			return out, nil
+		}
+	}
+	return nil, fmt.Errorf("not valid base64")
+}
+
+// MaxGunzipBytes caps what the gzip stage decompresses, so a small value
+// that expands enormously (a gzip bomb) cannot exhaust memory.
+var MaxGunzipBytes int64 = 64 << 20
+
+func gunzip(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	// Read one byte past the cap to tell a stream that ends there from one
+	// that goes on
+	out, err := io.ReadAll(io.LimitReader(r, MaxGunzipBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(out)) > MaxGunzipBytes {
+		return nil, fmt.Errorf("decompressed data exceeds %d bytes; stopped there", MaxGunzipBytes)
+	}
+	return out, nil
+}
+
+func decodeHex(data []byte) ([]byte, error) {
+	return hex.DecodeString(string(bytes.TrimSpace(data)))
+}
+
+// hexDump shows binary data as offsets, hex bytes and printable characters,
+// like hexdump -C. It never fails, so it is usually the last stage.
+func hexDump(data []byte) ([]byte, error) {
+	return []byte(hex.Dump(data)), nil
+}
+
+func indentJSON(data []byte) ([]byte, error) {
+	var out bytes.Buffer
+	if err := json.Indent(&out, data, "", "  "); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+func msgpackToJSON(data []byte) ([]byte, error) {
+	decoded, err := decodeMsgpack(data)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(decoded, "", "  ")
+}
+
+// unquote strips one layer of string quoting, as left by a value that was
+// JSON-encoded twice.
+func unquote(data []byte) ([]byte, error) {
+	trimmed := bytes.TrimSpace(data)
+	var s string
+	if err := json.Unmarshal(trimmed, &s); err == nil {
+		return []byte(s), nil
+	}
+	// Fall back to Go-style quoting, which also allows \x escapes and backquotes
+	s, err := strconv.Unquote(string(trimmed))
+	if err != nil {
+		return nil, fmt.Errorf("not a quoted string")
+	}
+	return []byte(s), nil
+}
+
+// MaxUnwrapLayers caps the layers of string encoding UnwrapJSON peels.
+const MaxUnwrapLayers = 8
+
+// UnwrapJSON peels the layers of JSON string encoding around a JSON object or
+// array, as left by a document that was encoded more than once, up to
+// MaxUnwrapLayers. It returns the inner document and the number of layers
+// removed, or value and 0 when value is not an object or array stored as a
+// string.
+func UnwrapJSON(value []byte) ([]byte, int) {
+	inner := bytes.TrimSpace(value)
+	layers := 0
+	for layers < MaxUnwrapLayers {
+		var s string
+		if err := json.Unmarshal(inner, &s); err != nil {
+			break
+		}
+		next := bytes.TrimSpace([]byte(s))
+		if !json.Valid(next) {
+			break
+		}
+		inner = next
+		layers++
+	}
+	// Strings holding a bare number or a word are ordinary strings
+	if layers == 0 || (inner[0] != '{' && inner[0] != '[') {
+		return value, 0
+	}
+	return inner, layers
+}
+
+// unwrap strips every layer of string encoding around a JSON document.
+func unwrap(data []byte) ([]byte, error) {
+	inner, layers := UnwrapJSON(data)
+	if layers == 0 {
+		return nil, fmt.Errorf("not JSON encoded as a string")
+	}
+	return inner, nil
+}
+
+func urlDecode(data []byte) ([]byte, error) {
+	s, err := url.QueryUnescape(string(bytes.TrimSpace(data)))
+	if err != nil {
+		return nil, err
+	}
+	return []byte(s), nil
+}
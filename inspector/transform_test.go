@@ -0,0 +1,195 @@
+package inspector
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func gzipString(t *testing.T, s string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestApplyTransforms_Base64GzipJSON(t *testing.T) {
+	value := base64.StdEncoding.EncodeToString(gzipString(t, `{"a":1}`))
+
+	out, err := ApplyTransforms(value, []string{"base64", "gzip", "json"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "{\n  \"a\": 1\n}" {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestApplyTransforms_Hex(t *testing.T) {
+	out, err := ApplyTransforms(hex.EncodeToString([]byte("hello")), []string{"hex"})
+	if err != nil || out != "hello" {
+		t.Errorf("expected 'hello', got %q (err=%v)", out, err)
+	}
+}
+
+func TestApplyTransforms_Hexdump(t *testing.T) {
+	out, err := ApplyTransforms("\x00hi\xff", []string{"hexdump"})
+	want := "00000000  00 68 69 ff                                       |.hi.|\n"
+	if err != nil || out != want {
+		t.Errorf("expected %q, got %q (err=%v)", want, out, err)
+	}
+}
+
+func TestApplyTransforms_Msgpack(t *testing.T) {
+	// {"name": "Bob", "tags": [1, -1], "ok": true}
+	packed := []byte{0x83,
+		0xa4, 'n', 'a', 'm', 'e', 0xa3, 'B', 'o', 'b',
+		0xa4, 't', 'a', 'g', 's', 0x92, 0x01, 0xff,
+		0xa2, 'o', 'k', 0xc3,
+	}
+	out, err := ApplyTransforms(string(packed), []string{"msgpack"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{`"name": "Bob"`, `"ok": true`, "-1"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %s in output, got: %s", want, out)
+		}
+	}
+}
+
+func TestApplyTransforms_ReportsFailingStage(t *testing.T) {
+	value := base64.StdEncoding.EncodeToString([]byte("not gzip"))
+
+	_, err := ApplyTransforms(value, []string{"base64", "gzip"})
+	if err == nil {
+		t.Fatal("expected an error for non-gzip data")
+	}
+	if !strings.Contains(err.Error(), "stage 2 (gzip)") {
+		t.Errorf("expected error to name the failing stage, got: %v", err)
+	}
+}
+
+func TestApplyTransforms_GunzipLimit(t *testing.T) {
+	prev := MaxGunzipBytes
+	defer func() { MaxGunzipBytes = prev }()
+	MaxGunzipBytes = 100
+	value := string(gzipString(t, strings.Repeat("0", 100)))
+
+	if out, err := ApplyTransforms(value, []string{"gzip"}); err != nil || len(out) != 100 {
+		t.Errorf("expected data up to the cap to decompress, got %d bytes (%v)", len(out), err)
+	}
+	value = string(gzipString(t, strings.Repeat("0", 101)))
+	if _, err := ApplyTransforms(value, []string{"gzip"}); err == nil || !strings.Contains(err.Error(), "exceeds 100 bytes") {
+		t.Errorf("expected decompression to stop at the cap, got %v", err)
+	}
+}
+
+func TestLoadTransformRules(t *testing.T) {
+	rules, err := ParseTransformRules(`[{"pattern":"jobs:*","transforms":["base64","json"]}]`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rules) != 1 || !rules[0].Pattern.Match("jobs:email") {
+		t.Errorf("expected a rule matching jobs:*, got %+v", rules)
+	}
+
+	if _, err := ParseTransformRules(`[{"pattern":"*","transforms":["rot13"]}]`); err == nil {
+		t.Error("expected an error for an unknown transform")
+	}
+	if _, err := ParseTransformRules(`not json`); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+}
+
+func TestMatchTransforms(t *testing.T) {
+	rules, err := ParseTransformRules(`[
+		{"pattern":"b64:*","transforms":["base64"]},
+		{"pattern":"*","transforms":["json"]}
+	]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := MatchTransforms(rules, "b64:one"); len(got) != 1 || got[0] != "base64" {
+		t.Errorf("expected first matching rule to win, got %v", got)
+	}
+	if got := MatchTransforms(rules[:1], "other"); got != nil {
+		t.Errorf("expected no pipeline for unmatched key, got %v", got)
+	}
+}
+
+func TestApplyTransforms_UnquoteURLDecode(t *testing.T) {
+	tests := []struct {
+		value  string
+		stages []string
+		want   string
+	}{
+		{`"{\"id\":1}"`, []string{"unquote"}, `{"id":1}`},
+		{`"\"{\\\"id\\\":1}\""`, []string{"unquote", "unquote"}, `{"id":1}`},
+		{"`raw`", []string{"unquote"}, "raw"},
+		{"%7B%22id%22%3A1%7D", []string{"urldecode"}, `{"id":1}`},
+		{"a+b%20c", []string{"urldecode"}, "a b c"},
+	}
+	for _, tt := range tests {
+		got, err := ApplyTransforms(tt.value, tt.stages)
+		if err != nil || got != tt.want {
+			t.Errorf("ApplyTransforms(%q, %v) = %q, %v; want %q", tt.value, tt.stages, got, err, tt.want)
+		}
+	}
+
+	if _, err := ApplyTransforms("not quoted", []string{"unquote"}); err == nil {
+		t.Error("expected unquote to fail on an unquoted value")
+	}
+	if _, err := ApplyTransforms("100%", []string{"urldecode"}); err == nil {
+		t.Error("expected urldecode to fail on a malformed escape")
+	}
+}
+
+func TestUnwrapJSON(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   string
+		layers int
+	}{
+		{`"{\"id\":1}"`, `{"id":1}`, 1},
+		{` "\"[1, 2]\"" `, `[1, 2]`, 2},
+		{`{"id":1}`, `{"id":1}`, 0},
+		{`"42"`, `"42"`, 0},
+		{`"\"hello\""`, `"\"hello\""`, 0},
+		{`"{not json"`, `"{not json"`, 0},
+		{"plain text", "plain text", 0},
+	}
+	for _, tt := range tests {
+		got, layers := UnwrapJSON([]byte(tt.value))
+		if string(got) != tt.want || layers != tt.layers {
+			t.Errorf("UnwrapJSON(%q) = %q, %d; want %q, %d", tt.value, got, layers, tt.want, tt.layers)
+		}
+	}
+
+	// Peeling stops at the limit, leaving the remaining layers in place
+	value := `{"id":1}`
+	for i := 0; i < MaxUnwrapLayers+1; i++ {
+		quoted, _ := json.Marshal(value)
+		value = string(quoted)
+	}
+	if got, layers := UnwrapJSON([]byte(value)); layers != 0 || string(got) != value {
+		t.Errorf("expected a value nested past the limit to be left alone, got %d layers", layers)
+	}
+
+	if got, err := ApplyTransforms(`"\"{\\\"id\\\":1}\""`, []string{"unwrap"}); err != nil || got != `{"id":1}` {
+		t.Errorf("expected unwrap to peel both layers, got %q, %v", got, err)
+	}
+	if _, err := ApplyTransforms(`"just text"`, []string{"unwrap"}); err == nil {
+		t.Error("expected unwrap to fail on a plain string")
+	}
+}
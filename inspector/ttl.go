@@ -0,0 +1,35 @@
+package inspector
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TTLs returns the remaining time to live of each key using a single
+// pipeline of PTTL calls. Keys without an expiry, or whose TTL could not be
+// read, have a zero duration.
+func TTLs(ctx context.Context, client redis.UniversalClient, keys []string) ([]time.Duration, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	pipe := client.Pipeline()
+	cmds := make([]*redis.DurationCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.PTTL(ctx, key)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	ttls := make([]time.Duration, len(keys))
+	for i, cmd := range cmds {
+		// PTTL reports -1 for no expiry and -2 for a missing key
+		if ttl, err := cmd.Result(); err == nil && ttl > 0 {
+			ttls[i] = ttl
+		}
+	}
+	return ttls, nil
+}
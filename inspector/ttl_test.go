@@ -0,0 +1,22 @@
+package inspector
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTTLs(t *testing.T) {
+	mr, client := newTestClient(t)
+	mr.RPush("volatile", "a")
+	mr.SetTTL("volatile", 90*time.Second)
+	mr.RPush("durable", "a")
+
+	ttls, err := TTLs(context.Background(), client, []string{"volatile", "durable", "missing"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ttls[0] != 90*time.Second || ttls[1] != 0 || ttls[2] != 0 {
+		t.Errorf("unexpected TTLs: %v", ttls)
+	}
+}
@@ -2,149 +2,626 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"log"
+	"math"
 	"net/http"
+	"net/url"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/its-the-vibe/RediScan/inspector"
 	"github.com/redis/go-redis/v9"
 )
 
 var (
-	redisClient *redis.Client
-	ctx         = context.Background()
-	maxLists    = 25 // Default max number of lists to display on index page
+	redisClient    redis.UniversalClient
+	ctx            = context.Background()
+	maxLists       = 25                      // Default max number of lists to display on index page
+	prettyJSON     *inspector.PrettyCache    // Optional cache of pretty-printed values, nil when disabled
+	schemaRules    []inspector.SchemaRule    // JSON Schemas configured by JSON_SCHEMAS
+	directionRules []inspector.DirectionRule // Push directions configured by PUSH_DIRECTIONS
+	alertRules     []inspector.AlertRule     // Alert patterns configured by ALERT_PATTERNS
+	summaryRules   []inspector.SummaryRule   // One-line summary fields configured by SUMMARY_FIELDS
+	wrapMode       = "reload"                // Default behaviour when navigating past either end of a list
+	previewLength  = 80                      // Characters of each list's newest element shown on the index page, 0 to disable
+	maxJSONDepth   = 0                       // JSON nesting depth shown before deeper levels are hidden, 0 for unlimited
+	jsonKeyOrder   = "sorted"                // Order of object keys in pretty-printed JSON: "sorted" or "stored"
+	groupByType    = false                   // Whether the index page groups keys of every type by default
+	keyDelimiter   = ":"                     // Separator of key namespaces in the index page's tree view
+	homeRedirect   string                    // Local path the bare home page redirects to, "" to show the key list
+	maxPreloadSize = 8 << 20                 // Bytes of values embedded in a result page before falling back to server navigation, 0 for unlimited
+	preloadNewest  int64                     // Newest elements loaded from longer lists instead of the whole list, 0 for whole lists
 )
 
 func main() {
+	// Settings come from the optional CONFIG_FILE, overridden by the environment
+	configFile = os.Getenv("CONFIG_FILE")
+	cfg, err := loadConfig(configFile, os.LookupEnv)
+	if err != nil {
+		log.Fatalf("Invalid CONFIG_FILE: %v", err)
+	}
+
 	// Initialize Redis client
-	redisAddr := os.Getenv("REDIS_ADDR")
+	redisAddr := cfg.RedisAddr
 	if redisAddr == "" {
 		redisAddr = "localhost:6379"
 	}
 
-	redisPassword := os.Getenv("REDIS_PASSWORD")
+	redisPassword := cfg.RedisPassword
 	redisDB := 0
-	if dbStr := os.Getenv("REDIS_DB"); dbStr != "" {
+	if dbStr := cfg.RedisDB; dbStr != "" {
 		if db, err := strconv.Atoi(dbStr); err == nil {
 			redisDB = db
 		}
 	}
 
-	redisClient = redis.NewClient(&redis.Options{
-		Addr:     redisAddr,
-		Password: redisPassword,
-		DB:       redisDB,
-	})
+	// Name the connections so they can be told apart in CLIENT LIST and the
+	// slowlog
+	clientName := "rediscan"
+	if name := cfg.RedisClientName; name != "" {
+		// Redis refuses names with spaces, which would fail every connection
+		if strings.ContainsAny(name, " \t\r\n") {
+			log.Printf("Warning: Ignoring invalid REDIS_CLIENT_NAME %q: it must not contain spaces", name)
+		} else {
+			clientName = name
+		}
+	}
+
+	if logLevel := cfg.LogLevel; logLevel != "" {
+		debugLogging = strings.EqualFold(logLevel, "debug")
+	}
+
+	// Configure retries of reads that fail with transient errors
+	retry := retryHook{attempts: 3, baseDelay: 100 * time.Millisecond}
+	if attemptsStr := cfg.RedisRetryAttempts; attemptsStr != "" {
+		if attempts, err := strconv.Atoi(attemptsStr); err == nil && attempts > 0 {
+			retry.attempts = attempts
+		}
+	}
+	if delayStr := cfg.RedisRetryDelay; delayStr != "" {
+		if delay, err := time.ParseDuration(delayStr); err == nil && delay > 0 {
+			retry.baseDelay = delay
+		} else {
+			log.Printf("Warning: Ignoring invalid REDIS_RETRY_DELAY %q", delayStr)
+		}
+	}
+
+	// Connect to a Redis Cluster instead when its nodes are listed
+	var clusterAddrs []string
+	for _, addr := range strings.Split(cfg.RedisClusterAddrs, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			clusterAddrs = append(clusterAddrs, addr)
+		}
+	}
+	if len(clusterAddrs) > 0 {
+		if redisDB != 0 {
+			log.Printf("Warning: Ignoring REDIS_DB %d: a Redis Cluster only has database 0", redisDB)
+		}
+		redisClient = redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:      clusterAddrs,
+			Password:   redisPassword,
+			ClientName: clientName,
+			MaxRetries: -1,
+		})
+	} else {
+		redisClient = redis.NewClient(&redis.Options{
+			Addr:     redisAddr,
+			Password: redisPassword,
+			DB:       redisDB,
+			// CLIENT SETNAME is sent as each connection is opened
+			ClientName: clientName,
+			// Retries are handled by retryHook, which skips writes and logs each attempt
+			MaxRetries: -1,
+		})
+	}
+	// Refuse mutating commands before they are sent, and before any retry
+	if readOnlyStr := cfg.ForceReadOnly; readOnlyStr != "" {
+		forceReadOnly, _ = strconv.ParseBool(readOnlyStr)
+	}
+	if forceReadOnly {
+		redisClient.AddHook(readOnlyHook{})
+	}
+	redisClient.AddHook(retry)
+	redisRetry = retry
+
+	// Configure the longest Redis timeout a request may ask for
+	if maxTimeoutStr := cfg.RedisMaxTimeout; maxTimeoutStr != "" {
+		if timeout, err := time.ParseDuration(maxTimeoutStr); err == nil && timeout > 0 {
+			maxRequestTimeout = timeout
+		} else {
+			log.Printf("Warning: Ignoring invalid REDIS_MAX_TIMEOUT %q", maxTimeoutStr)
+		}
+	}
+
+	// Configure how many requests may use Redis at once (0 is unlimited)
+	if opsStr := cfg.MaxConcurrentRedisOps; opsStr != "" {
+		if ops, err := strconv.Atoi(opsStr); err == nil && ops >= 0 {
+			if ops > 0 {
+				redisSlots = make(chan struct{}, ops)
+			}
+		} else {
+			log.Printf("Warning: Ignoring invalid MAX_CONCURRENT_REDIS_OPS %q", opsStr)
+		}
+	}
+
+	// Configure whether the audit trail of list accesses is kept in Redis
+	if auditStr := cfg.AuditRedis; auditStr != "" {
+		auditRedis, _ = strconv.ParseBool(auditStr)
+	}
+	if maxStr := cfg.AuditMaxEntries; maxStr != "" {
+		if n, err := strconv.ParseInt(maxStr, 10, 64); err == nil && n > 0 {
+			auditMaxEntries = n
+		} else {
+			log.Printf("Warning: Ignoring invalid AUDIT_MAX_ENTRIES %q", maxStr)
+		}
+	}
+
+	// Configure how long /api/find reuses a field index
+	if ttlStr := cfg.FindIndexTTL; ttlStr != "" {
+		if ttl, err := time.ParseDuration(ttlStr); err == nil && ttl >= 0 {
+			findIndexTTL = ttl
+		} else {
+			log.Printf("Warning: Ignoring invalid FIND_INDEX_TTL %q", ttlStr)
+		}
+	}
+
+	// Configure whether to check at startup that Redis persists its data
+	if checkStr := cfg.PersistenceCheck; checkStr != "" {
+		if check, err := strconv.ParseBool(checkStr); err == nil {
+			persistenceCheck = check
+		} else {
+			log.Printf("Warning: Ignoring invalid PERSISTENCE_CHECK %q", checkStr)
+		}
+	}
+
+	// Configure the largest request body POST and PUT routes accept
+	if bodyStr := cfg.MaxRequestBodyBytes; bodyStr != "" {
+		if limit, err := strconv.ParseInt(bodyStr, 10, 64); err == nil && limit > 0 {
+			maxRequestBodyBytes = limit
+		} else {
+			log.Printf("Warning: Ignoring invalid MAX_REQUEST_BODY_BYTES %q", bodyStr)
+		}
+	}
+
+	// Configure whether the index page groups keys by type by default
+	if groupStr := cfg.IndexGroupByType; groupStr != "" {
+		groupByType, _ = strconv.ParseBool(groupStr)
+	}
+
+	// Configure the separator the index page's tree view splits keys on
+	if delimiter := cfg.KeyDelimiter; delimiter != "" {
+		keyDelimiter = delimiter
+	}
+
+	// Serve the list inspector somewhere other than /lindex
+	if route := cfg.LindexRoute; route != "" {
+		if err := checkRoutePath("lindex", route); err != nil {
+			log.Fatalf("Invalid LINDEX_ROUTE %q: %v", route, err)
+		}
+		lindexRoute = route
+	}
+
+	// Configure where the home page sends visitors instead of the key list
+	if redirect := cfg.HomeRedirect; redirect != "" {
+		if err := checkHomeRedirect(redirect); err == nil {
+			homeRedirect = redirect
+		} else {
+			log.Printf("Warning: Ignoring invalid HOME_REDIRECT %q: %v", redirect, err)
+		}
+	}
+
+	// Allow storing UI preferences per signed-in user in Redis
+	if prefsStr := cfg.PrefsEnabled; prefsStr != "" {
+		prefsEnabled, _ = strconv.ParseBool(prefsStr)
+	}
+	if header := cfg.PrefsUserHeader; header != "" {
+		prefsUserHeader = header
+	}
+
+	// Configure how long browsers may cache static assets
+	if maxAgeStr := cfg.StaticCacheMaxAge; maxAgeStr != "" {
+		if maxAge, err := time.ParseDuration(maxAgeStr); err == nil && maxAge >= 0 {
+			staticMaxAge = maxAge
+		} else {
+			log.Printf("Warning: Ignoring invalid STATIC_CACHE_MAX_AGE %q", maxAgeStr)
+		}
+	}
+
+	// Pause live pages left open without anyone using them
+	if idleStr := cfg.LiveIdleTimeout; idleStr != "" {
+		if idle, err := time.ParseDuration(idleStr); err == nil && idle >= 0 {
+			liveIdleTimeout = idle
+		} else {
+			log.Printf("Warning: Ignoring invalid LIVE_IDLE_TIMEOUT %q", idleStr)
+		}
+	}
 
 	// Configure max lists to display
-	if maxListsStr := os.Getenv("MAX_LISTS"); maxListsStr != "" {
+	if maxListsStr := cfg.MaxLists; maxListsStr != "" {
 		if ml, err := strconv.Atoi(maxListsStr); err == nil && ml > 0 {
 			maxLists = ml
 		}
 	}
 
+	// Configure how much of each list's newest element the index page shows
+	if previewLengthStr := cfg.PreviewLength; previewLengthStr != "" {
+		if pl, err := strconv.Atoi(previewLengthStr); err == nil && pl >= 0 {
+			previewLength = pl
+		}
+	}
+
+	// Allow adding elements to lists from the result page
+	if writeStr := cfg.WriteEnabled; writeStr != "" {
+		writeEnabled, _ = strconv.ParseBool(writeStr)
+	}
+
+	// Allow /api/testconn to try connections to other Redis servers
+	if testConnStr := cfg.TestConnEnabled; testConnStr != "" {
+		testConnEnabled, _ = strconv.ParseBool(testConnStr)
+	}
+
+	// Password for the admin routes
+	adminPassword = cfg.AdminPassword
+
+	// FORCE_READONLY turns off every feature that writes to Redis
+	if forceReadOnly {
+		for _, feature := range []struct {
+			name    string
+			enabled *bool
+		}{{"WRITE_ENABLED", &writeEnabled}, {"PREFS_ENABLED", &prefsEnabled}, {"AUDIT_REDIS", &auditRedis}} {
+			if *feature.enabled {
+				log.Printf("Warning: Ignoring %s because FORCE_READONLY is set", feature.name)
+				*feature.enabled = false
+			}
+		}
+	}
+
+	// Configure the timezone timestamps are displayed in
+	if tz := cfg.DisplayTimezone; tz != "" {
+		if loc, err := time.LoadLocation(tz); err == nil {
+			displayLocation = loc
+		} else {
+			log.Printf("Warning: Ignoring invalid DISPLAY_TIMEZONE %q: %v", tz, err)
+		}
+	}
+
+	// Configure what navigating past either end of a list does
+	if mode := cfg.WrapMode; mode != "" {
+		switch mode {
+		case "reload", "wrap", "stop":
+			wrapMode = mode
+		default:
+			log.Printf("Warning: Ignoring unknown WRAP_MODE %q", mode)
+		}
+	}
+
+	// Configure pretty-print cache size (0 disables caching)
+	if cacheSizeStr := cfg.PrettyCacheSize; cacheSizeStr != "" {
+		if size, err := strconv.Atoi(cacheSizeStr); err == nil && size > 0 {
+			prettyJSON = inspector.NewPrettyCache(size)
+		}
+	}
+
+	// Configure how large a list's values may be before they are not preloaded
+	if preloadStr := cfg.MaxPreloadBytes; preloadStr != "" {
+		if size, err := strconv.Atoi(preloadStr); err == nil && size >= 0 {
+			maxPreloadSize = size
+		}
+	}
+
+	// Configure how large an element may be before it is shown cut short
+	if valueStr := cfg.MaxValueBytes; valueStr != "" {
+		if size, err := strconv.Atoi(valueStr); err == nil && size >= 0 {
+			maxValueBytes = size
+		} else {
+			log.Printf("Warning: Ignoring invalid MAX_VALUE_BYTES %q", valueStr)
+		}
+	}
+
+	// Configure how many of a long list's newest elements are preloaded
+	if newestStr := cfg.PreloadNewest; newestStr != "" {
+		if n, err := strconv.ParseInt(newestStr, 10, 64); err == nil && n >= 0 {
+			preloadNewest = n
+		}
+	}
+
+	// Limit duplicate lookups, which read the whole list, to shorter lists
+	if maxStr := cfg.DuplicatesMaxLength; maxStr != "" {
+		if n, err := strconv.ParseInt(maxStr, 10, 64); err == nil && n >= 0 {
+			duplicatesMaxLength = n
+		} else {
+			log.Printf("Warning: Ignoring invalid DUPLICATES_MAX_LENGTH %q", maxStr)
+		}
+	}
+
+	// Configure how many elements the sampled overview reads
+	if countStr := cfg.SampleCount; countStr != "" {
+		if n, err := strconv.Atoi(countStr); err == nil && n >= 1 && n <= maxSampleCount {
+			sampleCount = n
+		} else {
+			log.Printf("Warning: Ignoring invalid SAMPLE_COUNT %q", countStr)
+		}
+	}
+
+	// Configure how deeply nested JSON is shown before levels are hidden
+	if depthStr := cfg.MaxJSONDepth; depthStr != "" {
+		if depth, err := strconv.Atoi(depthStr); err == nil && depth >= 0 {
+			maxJSONDepth = depth
+		}
+	}
+
+	// Configure whether pretty-printed JSON keeps its stored key order
+	if order := cfg.JSONKeyOrder; order != "" {
+		switch order {
+		case "sorted", "stored":
+			jsonKeyOrder = order
+		default:
+			log.Printf("Warning: Ignoring unknown JSON_KEY_ORDER %q", order)
+		}
+	}
+
+	// Load optional custom stylesheet
+	if cssPath := cfg.CustomCSSPath; cssPath != "" {
+		loadCustomCSS(cssPath)
+	}
+
+	// Load value transform pipelines
+	if transformConfig := cfg.ValueTransforms; transformConfig != "" {
+		rules, err := inspector.ParseTransformRules(transformConfig)
+		if err != nil {
+			log.Fatalf("Invalid VALUE_TRANSFORMS: %v", err)
+		}
+		transformRules = rules
+	}
+
+	// Configure the external command raw values are piped through, if any
+	if filterConfig := cfg.ValueFilterCmd; filterConfig != "" {
+		args, err := parseValueFilter(filterConfig)
+		if err != nil {
+			log.Fatalf("Invalid VALUE_FILTER_CMD: %v", err)
+		}
+		valueFilterArgs = args
+		log.Printf("Piping every value through external command %q", filterConfig)
+	}
+	if timeoutStr := cfg.ValueFilterTimeout; timeoutStr != "" {
+		if timeout, err := time.ParseDuration(timeoutStr); err == nil && timeout > 0 {
+			valueFilterTimeout = timeout
+		} else {
+			log.Printf("Warning: Ignoring invalid VALUE_FILTER_TIMEOUT %q", timeoutStr)
+		}
+	}
+	if maxStr := cfg.ValueFilterMaxBytes; maxStr != "" {
+		if n, err := strconv.ParseInt(maxStr, 10, 64); err == nil && n > 0 {
+			valueFilterMaxBytes = n
+		} else {
+			log.Printf("Warning: Ignoring invalid VALUE_FILTER_MAX_BYTES %q", maxStr)
+		}
+	}
+	if budgetStr := cfg.ValueFilterBudget; budgetStr != "" {
+		if budget, err := time.ParseDuration(budgetStr); err == nil && budget > 0 {
+			valueFilterBudget = budget
+		} else {
+			log.Printf("Warning: Ignoring invalid VALUE_FILTER_BUDGET %q", budgetStr)
+		}
+	}
+
+	// Configure which lists /metrics exports lengths for, and how many
+	if patternsConfig := cfg.MetricsListPatterns; patternsConfig != "" {
+		metricsPatterns = parseMetricsPatterns(patternsConfig)
+	}
+	if seriesStr := cfg.MetricsMaxSeries; seriesStr != "" {
+		if n, err := strconv.Atoi(seriesStr); err == nil && n > 0 {
+			metricsMaxSeries = n
+		}
+	}
+
+	// Configure how many keys the errored-keys report retains
+	if reportSizeStr := cfg.ErrorReportSize; reportSizeStr != "" {
+		if size, err := strconv.Atoi(reportSizeStr); err == nil && size > 0 {
+			erroredKeys = newErrorReport(size)
+		}
+	}
+
+	// Load the patterns that flag elements worth attention
+	if alertConfig := cfg.AlertPatterns; alertConfig != "" {
+		rules, err := inspector.ParseAlertRules(alertConfig)
+		if err != nil {
+			log.Fatalf("Invalid ALERT_PATTERNS: %v", err)
+		}
+		alertRules = rules
+	}
+
+	// Load the fields each element is summarized by in the table view
+	if summaryConfig := cfg.SummaryFields; summaryConfig != "" {
+		rules, err := inspector.ParseSummaryRules(summaryConfig)
+		if err != nil {
+			log.Fatalf("Invalid SUMMARY_FIELDS: %v", err)
+		}
+		summaryRules = rules
+	}
+
+	// Load the timestamp field of lists used as streams
+	if timestampConfig := cfg.TimestampFields; timestampConfig != "" {
+		rules, err := inspector.ParseTimestampRules(timestampConfig)
+		if err != nil {
+			log.Fatalf("Invalid TIMESTAMP_FIELDS: %v", err)
+		}
+		timestampRules = rules
+	}
+
+	// Load JSON Schemas to validate list elements against
+	if schemaConfig := cfg.JSONSchemas; schemaConfig != "" {
+		rules, err := inspector.ParseSchemaRules(schemaConfig)
+		if err != nil {
+			log.Fatalf("Invalid JSON_SCHEMAS: %v", err)
+		}
+		schemaRules = rules
+	}
+
+	// Load push directions used to label which end of a list is newest
+	if directionConfig := cfg.PushDirections; directionConfig != "" {
+		rules, err := inspector.ParseDirectionRules(directionConfig)
+		if err != nil {
+			log.Fatalf("Invalid PUSH_DIRECTIONS: %v", err)
+		}
+		directionRules = rules
+	}
+
 	// Test Redis connection
 	if err := redisClient.Ping(ctx).Err(); err != nil {
 		log.Printf("Warning: Could not connect to Redis at %s: %v", redisAddr, err)
 	} else {
 		log.Printf("Connected to Redis at %s", redisAddr)
+
+		// Seed example data into an empty database flagged as safe for it
+		if demo, _ := strconv.ParseBool(cfg.DemoMode); demo && forceReadOnly {
+			log.Printf("Warning: Ignoring DEMO_MODE because FORCE_READONLY is set")
+		} else if demo {
+			runDemoMode(cfg.DemoSafeDB, redisDB)
+		}
+
+		// Find out which introspection commands this server allows
+		probeCapabilities()
+
+		// Warn in the UI when the data being browsed is not persisted
+		if persistenceCheck && !redisCapabilities.ConfigGet {
+			log.Printf("Skipping the persistence check: Redis refuses CONFIG GET")
+		} else if persistenceCheck {
+			checkPersistence()
+		}
 	}
 
-	// Setup HTTP handlers
-	http.HandleFunc("/", indexHandler)
-	http.HandleFunc("/lindex", lindexHandler)
+	// Setup HTTP handlers, skipping any the operator has disabled
+	routes, err := parseDisabledRoutes(cfg.DisabledRoutes)
+	if err != nil {
+		log.Fatalf("Invalid DISABLED_ROUTES: %v", err)
+	}
+	disabledRoutes = routes
+	registerRoutes(http.DefaultServeMux, disabledRoutes)
 
-	port := os.Getenv("PORT")
+	port := cfg.Port
 	if port == "" {
 		port = "8080"
 	}
+	serverPort = port
+
+	// Serve over TLS (and therefore HTTP/2) when a certificate is configured
+	tlsCert := cfg.ServerTLSCert
+	tlsKey := cfg.ServerTLSKey
+	if tlsCert != "" || tlsKey != "" {
+		if tlsCert == "" || tlsKey == "" {
+			log.Fatal("Both SERVER_TLS_CERT and SERVER_TLS_KEY must be set to enable TLS")
+		}
+		// Load the pair up front so a bad path or mismatched key fails with a clear message
+		if _, err := tls.LoadX509KeyPair(tlsCert, tlsKey); err != nil {
+			log.Fatalf("Could not load TLS certificate: %v", err)
+		}
+		serverTLS = true
+
+		log.Printf("Starting server on port %s (TLS)", port)
+		if err := http.ListenAndServeTLS(":"+port, tlsCert, tlsKey, gzipHandler(noStoreHandler(http.DefaultServeMux))); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 
 	log.Printf("Starting server on port %s", port)
-	if err := http.ListenAndServe(":"+port, nil); err != nil {
+	if err := http.ListenAndServe(":"+port, gzipHandler(noStoreHandler(http.DefaultServeMux))); err != nil {
 		log.Fatal(err)
 	}
 }
 
-// ListInfo contains information about a Redis list
-type ListInfo struct {
-	Name string
-	Size int64
+// emptyScanTTL is how long a scan that found no lists is reused, so reloading
+// the home page of an empty instance does not rescan the keyspace each time.
+const emptyScanTTL = 5 * time.Second
+
+// lastEmptyScan is when a scan last found no lists, or zero if the last scan
+// found some.
+var lastEmptyScan struct {
+	sync.Mutex
+	at time.Time
 }
 
-// getAvailableLists retrieves a list of available Redis list keys with their sizes
-func getAvailableLists() ([]ListInfo, error) {
-	// Use SCAN instead of KEYS for better performance
-	var lists []ListInfo
-	var cursor uint64
+// getAvailableLists retrieves up to MAX_LISTS Redis list keys with their
+// sizes, reporting whether the scan stopped at the limit. An empty database
+// is answered from DBSIZE without scanning, and a scan that found no lists
+// is reused for emptyScanTTL.
+func getAvailableLists() (*inspector.ListScan, error) {
+	lastEmptyScan.Lock()
+	recentlyEmpty := !lastEmptyScan.at.IsZero() && time.Since(lastEmptyScan.at) < emptyScanTTL
+	lastEmptyScan.Unlock()
+	if recentlyEmpty {
+		return &inspector.ListScan{Complete: true}, nil
+	}
 
-	for {
-		var keys []string
-		var err error
-		keys, cursor, err = redisClient.Scan(ctx, cursor, "*", 100).Result()
-		if err != nil {
+	size, err := redisClient.DBSize(ctx).Result()
+	if err != nil {
+		return nil, err
+	}
+	scan := &inspector.ListScan{Complete: true}
+	if size > 0 {
+		if scan, err = inspector.ScanLists(ctx, redisClient, "*", maxLists); err != nil {
 			return nil, err
 		}
+	}
 
-		// Use pipeline to batch TYPE commands for better performance
-		if len(keys) > 0 {
-			pipe := redisClient.Pipeline()
-			typeCmds := make([]*redis.StatusCmd, len(keys))
-			for i, key := range keys {
-				typeCmds[i] = pipe.Type(ctx, key)
-			}
-			_, err = pipe.Exec(ctx)
-			if err != nil {
-				// Skip this batch if pipeline fails, log and continue with next scan iteration
-				log.Printf("Warning: Pipeline error, skipping batch: %v", err)
-			} else {
-				// First pass: identify which keys are lists
-				var listKeys []string
-				for i, key := range keys {
-					keyType, err := typeCmds[i].Result()
-					if err != nil {
-						continue
-					}
-					if keyType == "list" {
-						listKeys = append(listKeys, key)
-					}
-				}
-
-				// Second pass: batch LLEN commands for confirmed lists only
-				if len(listKeys) > 0 {
-					sizePipeline := redisClient.Pipeline()
-					llenCmds := make([]*redis.IntCmd, len(listKeys))
-					for i, key := range listKeys {
-						llenCmds[i] = sizePipeline.LLen(ctx, key)
-					}
-					_, err = sizePipeline.Exec(ctx)
-					if err != nil {
-						log.Printf("Warning: Pipeline error getting list sizes, skipping batch: %v", err)
-					} else {
-						for i, key := range listKeys {
-							size, err := llenCmds[i].Result()
-							if err != nil {
-								continue
-							}
-							lists = append(lists, ListInfo{Name: key, Size: size})
-							if len(lists) >= maxLists {
-								return lists, nil
-							}
-						}
-					}
-				}
-			}
-		}
+	lastEmptyScan.Lock()
+	defer lastEmptyScan.Unlock()
+	if len(scan.Lists) == 0 {
+		lastEmptyScan.at = time.Now()
+	} else {
+		lastEmptyScan.at = time.Time{}
+	}
+	return scan, nil
+}
 
-		if cursor == 0 {
-			break
+// forgetEmptyScan makes the next getAvailableLists scan again, after
+// RediScan itself may have created a list.
+func forgetEmptyScan() {
+	lastEmptyScan.Lock()
+	defer lastEmptyScan.Unlock()
+	lastEmptyScan.at = time.Time{}
+}
+
+// allowMethods replies with 405 Method Not Allowed, listing the permitted
+// methods in the Allow header, unless the request uses one of them.
+func allowMethods(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, m := range methods {
+		if r.Method == m {
+			return true
 		}
 	}
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	return false
+}
 
-	return lists, nil
+// checkHomeRedirect returns an error unless target is a path on this server
+// other than the home page itself, so HOME_REDIRECT can neither loop nor send
+// visitors elsewhere.
+func checkHomeRedirect(target string) error {
+	u, err := url.Parse(target)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "" || u.Host != "" || !strings.HasPrefix(u.Path, "/") || strings.HasPrefix(target, "//") {
+		return errors.New("must be a path on this server, such as /lindex?key=main-queue")
+	}
+	if u.Path == "/" && u.RawQuery == "" {
+		return errors.New("must not be the home page itself")
+	}
+	return nil
 }
 
 func indexHandler(w http.ResponseWriter, r *http.Request) {
@@ -152,14 +629,14 @@ func indexHandler(w http.ResponseWriter, r *http.Request) {
 		http.NotFound(w, r)
 		return
 	}
-
-	// Get available Redis lists
-	availableLists, err := getAvailableLists()
-	if err != nil {
-		log.Printf("Error fetching available lists: %v", err)
-		// Continue even if we can't fetch lists
+	// A bare visit lands on the configured page; any query string, such as
+	// /?list, still shows the key list
+	if homeRedirect != "" && r.URL.RawQuery == "" {
+		http.Redirect(w, r, homeRedirect, http.StatusFound)
+		return
 	}
 
+	// The page shell renders immediately; lists are fetched from /api/lists
 	tmpl := `<!DOCTYPE html>
 <html>
 <head>
@@ -187,6 +664,9 @@ func indexHandler(w http.ResponseWriter, r *http.Request) {
             border-radius: 5px;
             box-shadow: 0 2px 4px rgba(0,0,0,0.1);
         }
+        .peek-form {
+            margin-top: 20px;
+        }
         label {
             display: block;
             margin-bottom: 5px;
@@ -242,159 +722,1045 @@ func indexHandler(w http.ResponseWriter, r *http.Request) {
             color: #666;
             font-size: 14px;
         }
+        .list-preview {
+            display: block;
+            margin-top: 4px;
+            color: #555;
+            font-family: monospace;
+            font-size: 13px;
+            white-space: nowrap;
+            overflow: hidden;
+            text-overflow: ellipsis;
+        }
+        .list-ttl {
+            margin-left: 6px;
+            padding: 1px 6px;
+            border-radius: 3px;
+            background-color: #fff3e0;
+            color: #e65100;
+            font-size: 12px;
+        }
+        .list-binary {
+            margin-left: 6px;
+            padding: 1px 6px;
+            border-radius: 3px;
+            background-color: #ede7f6;
+            color: #4527a0;
+            font-size: 12px;
+        }
+        .copy-key {
+            background: none;
+            border: none;
+            padding: 0 4px;
+            margin-left: 4px;
+            cursor: pointer;
+            color: #999;
+            font-size: 14px;
+        }
+        .copy-key:hover {
+            color: #333;
+        }
         .no-lists {
             color: #666;
             font-style: italic;
         }
+        .scan-notice {
+            background-color: #fff8e1;
+            border: 1px solid #ffe082;
+            border-radius: 3px;
+            padding: 8px 12px;
+            margin-bottom: 10px;
+            font-size: 14px;
+        }
+        .group-toggle {
+            display: block;
+            margin-bottom: 10px;
+            color: #666;
+            font-size: 14px;
+        }
+        .type-group summary {
+            cursor: pointer;
+            font-weight: bold;
+            margin: 10px 0 5px;
+            color: #333;
+        }
+        .key-tree-group {
+            margin-left: 16px;
+        }
+        #listContainer > .key-tree-group {
+            margin-left: 0;
+        }
+        .key-tree-group summary {
+            cursor: pointer;
+            margin: 6px 0;
+            font-family: monospace;
+            font-size: 15px;
+            color: #333;
+        }
+        .key-tree-group summary .list-size {
+            font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif;
+        }
+        .key-tree-group .list-item {
+            margin-left: 16px;
+        }
+        .key-filter {
+            width: 100%;
+            padding: 8px;
+            margin-bottom: 10px;
+            border: 1px solid #ddd;
+            border-radius: 3px;
+            box-sizing: border-box;
+        }
+        .loading {
+            color: #666;
+            display: flex;
+            align-items: center;
+            gap: 10px;
+        }
+        .spinner {
+            width: 18px;
+            height: 18px;
+            border: 3px solid #ddd;
+            border-top-color: #4CAF50;
+            border-radius: 50%;
+            animation: spin 0.8s linear infinite;
+        }
+        @keyframes spin {
+            to { transform: rotate(360deg); }
+        }
     </style>
+    {{customCSSLink}}
+    {{prefsScript}}
 </head>
 <body>
+    {{persistenceBanner}}
     <h1>RediScan - Redis List Inspector</h1>
     <div class="info">
         <p>This tool allows you to inspect Redis lists with automatic JSON pretty-printing.</p>
         <p>Use cursor keys to navigate through list elements once loaded.</p>
+        <p>For ad-hoc queries, run read-only Redis commands in the <a href="/console">console</a>.</p>
     </div>
-    {{if .AvailableLists}}
     <div class="available-lists">
         <h2>Available Redis Lists</h2>
-        {{range .AvailableLists}}
-        <div class="list-item">
-            <a href="/lindex?key={{.Name | urlquery}}">{{.Name}}</a> <span class="list-size">({{.Size}} element{{if ne .Size 1}}s{{end}})</span>
+        <input type="search" id="keyFilter" class="key-filter" placeholder="Filter keys (fuzzy, e.g. usrq matches user:requests:queue)" autocomplete="off">
+        <label class="group-toggle"><input type="checkbox" id="groupByType"> Group all keys by type</label>
+        <label class="group-toggle"><input type="checkbox" id="keyTree"> Show lists as a tree of namespaces (split on "{{.KeyDelimiter}}")</label>
+        <div id="listContainer">
+            <div class="loading"><span class="spinner"></span> Scanning Redis for lists&hellip;</div>
         </div>
-        {{end}}
-    </div>
-    {{else}}
-    <div class="available-lists">
-        <h2>Available Redis Lists</h2>
-        <p class="no-lists">No Redis lists found. Create a list in Redis to get started.</p>
     </div>
-    {{end}}
-    <form action="/lindex" method="get">
+    <form action="{{lindexPath}}" method="get">
         <label for="key">Redis List Key:</label>
-        <input type="text" id="key" name="key" required placeholder="e.g., mylist">
+        <input type="text" id="key" name="key" required placeholder="e.g., mylist" list="keySuggestions" autocomplete="off">
+        <datalist id="keySuggestions"></datalist>
         
-        <label for="index">Index (optional, defaults to newest):</label>
-        <input type="number" id="index" name="index" value="" min="0" placeholder="Leave empty for newest">
+        <label for="index">Index (optional, defaults to newest; negative counts back from newest, a percentage such as 90%, or first, last or mid):</label>
+        <input type="text" id="index" name="index" value="" pattern="-?[0-9]+|[0-9]+(\.[0-9]+)?%|first|last|mid" placeholder="Leave empty for newest">
+
+        <label for="newest">Nth from newest (optional, used when index is empty):</label>
+        <input type="number" id="newest" name="newest" value="" min="1" placeholder="e.g., 5 for the 5th newest">
         
         <button type="submit">Inspect</button>
     </form>
-</body>
-</html>`
 
-	tmplParsed, err := template.New("index").Parse(tmpl)
-	if err != nil {
-		http.Error(w, fmt.Sprintf("Template error: %v", err), http.StatusInternalServerError)
-		return
-	}
+    <form action="/peek" method="get" class="peek-form">
+        <label for="pattern">Peek newest element across lists matching:</label>
+        <input type="text" id="pattern" name="pattern" required placeholder="e.g., jobs:*">
 
-	data := struct {
-		AvailableLists []ListInfo
-	}{
-		AvailableLists: availableLists,
-	}
+        <label for="peekIndex">Index to peek at instead (optional; 0 is the head of every list, -1 the tail):</label>
+        <input type="text" id="peekIndex" name="index" value="" pattern="-?[0-9]+" placeholder="Leave empty for each list's newest element">
 
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	if err := tmplParsed.Execute(w, data); err != nil {
-		log.Printf("Error rendering template: %v", err)
-	}
-}
+        <button type="submit">Peek</button>
+        <button type="submit" formaction="/dashboard">Watch lengths</button>
+    </form>
 
-func lindexHandler(w http.ResponseWriter, r *http.Request) {
-	key := r.URL.Query().Get("key")
-	indexStr := r.URL.Query().Get("index")
+    <form action="/api/keys/export" method="get" class="peek-form">
+        <label for="exportPattern">Export every key matching, with its type and size (scans the whole keyspace):</label>
+        <input type="text" id="exportPattern" name="pattern" value="*" required>
 
-	if key == "" {
-		renderNotFound(w, "Missing 'key' parameter")
-		return
+        <button type="submit" name="format" value="csv">Export CSV</button>
+        <button type="submit" name="format" value="json">Export JSON</button>
+    </form>
+
+    <script>
+        let allLists = null;
+        let allKeys = null;
+        let listScan = null;
+        let keyScan = null;
+        const groupStorageKey = 'rediscan.groupByType';
+        // Recently inspected keys, newest first, recorded by the result page.
+        // The name lacks the rediscan. prefix so history is not synced to the
+        // server as a preference
+        const recentKeysStorageKey = 'rediscanRecentKeys';
+        let groupByType = (localStorage.getItem(groupStorageKey) || String({{.GroupByType}})) === 'true';
+        const treeStorageKey = 'rediscan.keyTree';
+        let keyTree = localStorage.getItem(treeStorageKey) === 'true';
+        const keyDelimiter = {{.KeyDelimiter}};
+
+        // Score how well needle fuzzily matches key: -1 unless every character
+        // of needle appears in key in order, higher for contiguous runs, matches
+        // at the start of a segment (after : . _ - / or a space) and substrings
+        function fuzzyScore(needle, key) {
+            const haystack = key.toLowerCase();
+            if (needle === '') {
+                return 0;
+            }
+            if (haystack.includes(needle)) {
+                return 1000 - haystack.indexOf(needle) - haystack.length / 100;
+            }
+            let score = 0;
+            let run = 0;
+            let pos = 0;
+            for (const ch of needle) {
+                const found = haystack.indexOf(ch, pos);
+                if (found === -1) {
+                    return -1;
+                }
+                run = found === pos ? run + 1 : 0;
+                score += 1 + run * 2;
+                if (found === 0 || ':._-/ '.includes(haystack[found - 1])) {
+                    score += 5;
+                }
+                pos = found + 1;
+            }
+            return score - haystack.length / 100;
+        }
+
+        // Show the lists (or keys, when grouped) matching the filter, best
+        // matches first
+        function applyFilter() {
+            const needle = document.getElementById('keyFilter').value.trim().toLowerCase();
+            const render = groupByType ? renderGroups : (keyTree ? renderTree : renderLists);
+            const source = groupByType ? allKeys : allLists;
+            if (!needle) {
+                render(source);
+                return;
+            }
+            const matches = [];
+            source.forEach(function(list) {
+                const score = fuzzyScore(needle, list.display);
+                if (score >= 0) {
+                    matches.push({list: list, score: score});
+                }
+            });
+            matches.sort(function(a, b) { return b.score - a.score; });
+            render(matches.map(function(match) { return match.list; }), true);
+        }
+
+        document.getElementById('keyFilter').addEventListener('input', applyFilter);
+
+        // A button copying a key's name to the clipboard
+        function copyButton(name) {
+            const copy = document.createElement('button');
+            copy.type = 'button';
+            copy.className = 'copy-key';
+            copy.title = 'Copy key name';
+            copy.textContent = '⧉';
+            copy.addEventListener('click', function() {
+                navigator.clipboard.writeText(name).then(function() {
+                    copy.textContent = '✓';
+                    setTimeout(function() { copy.textContent = '⧉'; }, 1500);
+                }, function() {
+                    copy.title = 'Copy failed';
+                });
+            });
+            return copy;
+        }
+
+        const typeHeadings = {
+            list: 'Lists', hash: 'Hashes', set: 'Sets', zset: 'Sorted sets',
+            string: 'Strings', stream: 'Streams'
+        };
+
+        // Render keys of every type from /api/keys in a collapsible section
+        // per type, lists first
+        function renderGroups(keys, filtered) {
+            const container = document.getElementById('listContainer');
+            container.textContent = '';
+            if (keys.length === 0) {
+                const empty = document.createElement('p');
+                empty.className = 'no-lists';
+                empty.textContent = filtered ? 'No keys match the filter.' : 'No keys found.';
+                appendShardNotice(container, keyScan);
+                container.appendChild(empty);
+                return;
+            }
+            appendShardNotice(container, keyScan);
+            const groups = {};
+            keys.forEach(function(key) {
+                (groups[key.type] = groups[key.type] || []).push(key);
+            });
+            const types = Object.keys(groups).sort(function(a, b) {
+                return (a !== 'list') - (b !== 'list') || a.localeCompare(b);
+            });
+            types.forEach(function(type) {
+                const section = document.createElement('details');
+                section.className = 'type-group';
+                section.open = true;
+                const summary = document.createElement('summary');
+                summary.textContent = (typeHeadings[type] || type) + ' (' + groups[type].length + ')';
+                section.appendChild(summary);
+
+                groups[type].forEach(function(key) {
+                    const item = document.createElement('div');
+                    item.className = 'list-item';
+                    if (type === 'list' || type === 'stream') {
+                        const link = document.createElement('a');
+                        link.href = (type === 'list' ? '{{lindexPath}}?key=' : '/stream?key=') + key.query;
+                        link.textContent = key.display;
+                        item.appendChild(link);
+                    } else {
+                        // Only lists and streams can be inspected, so other keys are listed for reference
+                        item.appendChild(document.createTextNode(key.display));
+                    }
+                    item.appendChild(copyButton(key.name));
+                    section.appendChild(item);
+                });
+                container.appendChild(section);
+            });
+        }
+
+        // Render the lists returned by /api/lists in place of the spinner
+        function renderLists(lists, filtered) {
+            const container = document.getElementById('listContainer');
+            container.textContent = '';
+            if (lists.length === 0) {
+                const empty = document.createElement('p');
+                empty.className = 'no-lists';
+                empty.textContent = filtered ? 'No keys match the filter.' : 'No Redis lists found. Create a list in Redis to get started.';
+                appendShardNotice(container, listScan);
+                container.appendChild(empty);
+                return;
+            }
+            appendScanNotice(container);
+            lists.forEach(function(list) {
+                container.appendChild(listItem(list, list.display));
+            });
+        }
+
+        // Say so when the scan stopped at MAX_LISTS, so nobody assumes these
+        // are all the lists there are
+        function appendScanNotice(container) {
+            appendShardNotice(container, listScan);
+            if (listScan && listScan.truncated) {
+                const notice = document.createElement('p');
+                notice.className = 'scan-notice';
+                notice.textContent = 'Showing the first ' + allLists.length + ' lists found after scanning ' +
+                    listScan.scanned + ' keys matching "' + listScan.pattern + '"; there may be many more. ' +
+                    'Increase MAX_LISTS, or peek at a narrower pattern below.';
+                container.appendChild(notice);
+            }
+        }
+
+        // Say so when Redis Cluster shards could not be scanned, naming them,
+        // as their keys are missing from what is shown
+        function appendShardNotice(container, scan) {
+            if (!scan || !scan.failed_shards) {
+                return;
+            }
+            const notice = document.createElement('p');
+            notice.className = 'scan-notice';
+            notice.textContent = 'Results may be incomplete: ' + scan.failed_shards.length + ' cluster shard' +
+                (scan.failed_shards.length === 1 ? '' : 's') + ' could not be scanned (' +
+                scan.failed_shards.map(function(shard) { return shard.addr + ': ' + shard.error; }).join('; ') + ').';
+            container.appendChild(notice);
+        }
+
+        // A list's entry, linking label to the inspector, with its size,
+        // badges and preview
+        function listItem(list, label) {
+            const item = document.createElement('div');
+            item.className = 'list-item';
+
+            const link = document.createElement('a');
+            link.href = '{{lindexPath}}?key=' + list.query + (list.ttl ? '&ttl=' + list.ttl : '');
+            link.textContent = label;
+            if (label !== list.display) {
+                link.title = list.display;
+            }
+            item.appendChild(link);
+
+            item.appendChild(copyButton(list.name));
+
+            const size = document.createElement('span');
+            size.className = 'list-size';
+            size.textContent = ' (' + list.size + ' element' + (list.size === 1 ? '' : 's') + ')';
+            item.appendChild(size);
+
+            if (list.ttl) {
+                const ttl = document.createElement('span');
+                ttl.className = 'list-ttl';
+                ttl.title = 'This key has an expiry and may disappear';
+                ttl.textContent = 'expires in ' + list.ttl + 's';
+                item.appendChild(ttl);
+            }
+
+            if (list.binary) {
+                const binary = document.createElement('span');
+                binary.className = 'list-binary';
+                binary.title = 'The newest element is not valid UTF-8, so the list probably holds binary data';
+                binary.textContent = 'binary';
+                item.appendChild(binary);
+            }
+
+            if (list.preview) {
+                const preview = document.createElement('span');
+                preview.className = 'list-preview';
+                preview.textContent = list.preview;
+                item.appendChild(preview);
+            }
+
+            return item;
+        }
+
+        // Build a tree of the lists by splitting their names on keyDelimiter.
+        // Each node holds its child segments and the list named by its path,
+        // if there is one, so app:jobs can be both a list and a namespace
+        function buildKeyTree(lists) {
+            const root = {children: new Map(), list: null, count: 0};
+            lists.forEach(function(list) {
+                let node = root;
+                node.count++;
+                list.display.split(keyDelimiter).forEach(function(segment) {
+                    if (!node.children.has(segment)) {
+                        node.children.set(segment, {children: new Map(), list: null, count: 0});
+                    }
+                    node = node.children.get(segment);
+                    node.count++;
+                });
+                node.list = list;
+            });
+            return root;
+        }
+
+        // Render the lists as nested collapsible namespaces. A namespace with
+        // a single child and no list of its own is merged into it, so
+        // app:users:123 shows as one group rather than three. Top-level
+        // namespaces start open, and every one does while filtering
+        function renderTree(lists, filtered) {
+            const container = document.getElementById('listContainer');
+            if (lists.length === 0) {
+                renderLists(lists, filtered);
+                return;
+            }
+            container.textContent = '';
+            appendScanNotice(container);
+
+            function renderNode(parent, node, depth) {
+                Array.from(node.children.keys()).sort().forEach(function(segment) {
+                    let child = node.children.get(segment);
+                    let label = segment;
+                    while (child.list === null && child.children.size === 1) {
+                        const [next, grandchild] = child.children.entries().next().value;
+                        label += keyDelimiter + next;
+                        child = grandchild;
+                    }
+                    if (child.children.size === 0) {
+                        parent.appendChild(listItem(child.list, label));
+                        return;
+                    }
+                    const group = document.createElement('details');
+                    group.className = 'key-tree-group';
+                    group.open = filtered || depth === 0;
+                    const summary = document.createElement('summary');
+                    summary.textContent = label + keyDelimiter;
+                    const size = document.createElement('span');
+                    size.className = 'list-size';
+                    size.textContent = ' (' + child.count + ' list' + (child.count === 1 ? '' : 's') + ')';
+                    summary.appendChild(size);
+                    group.appendChild(summary);
+                    if (child.list !== null) {
+                        // The namespace's own list comes before its children
+                        group.appendChild(listItem(child.list, label));
+                    }
+                    renderNode(group, child, depth + 1);
+                    parent.appendChild(group);
+                });
+            }
+            renderNode(container, buildKeyTree(lists), 0);
+        }
+
+        // Fetch the flat lists or, when grouping, keys of every type; each is
+        // fetched once and kept for the filter
+        function load() {
+            if ((groupByType ? allKeys : allLists) !== null) {
+                applyFilter();
+                return;
+            }
+            fetch(groupByType ? '/api/keys' : '/api/lists')
+                .then(function(response) {
+                    return response.json().then(function(body) {
+                        if (!response.ok) {
+                            throw new Error(body.error ? body.error.message : response.statusText);
+                        }
+                        return body;
+                    });
+                })
+                .then(function(body) {
+                    if (body.keys) {
+                        allKeys = body.keys;
+                        keyScan = body;
+                    } else {
+                        allLists = body.lists;
+                        listScan = body;
+                    }
+                    applyFilter();
+                    updateKeySuggestions();
+                })
+                .catch(function(err) {
+                    const container = document.getElementById('listContainer');
+                    container.textContent = '';
+                    const message = document.createElement('p');
+                    message.className = 'no-lists';
+                    message.textContent = 'Could not load lists: ' + err.message;
+                    container.appendChild(message);
+                });
+        }
+
+        // Suggest recently inspected keys first, then the lists found by the
+        // scan, as the key is typed into the form
+        function updateKeySuggestions() {
+            let recent = [];
+            try {
+                recent = JSON.parse(localStorage.getItem(recentKeysStorageKey) || '[]');
+            } catch (e) {
+                // Unreadable history is ignored
+            }
+            const names = Array.isArray(recent) ? recent.filter(function(name) { return typeof name === 'string'; }) : [];
+            (allLists || []).forEach(function(list) {
+                names.push(list.name);
+            });
+            (allKeys || []).forEach(function(key) {
+                if (key.type === 'list') {
+                    names.push(key.name);
+                }
+            });
+            const options = document.createDocumentFragment();
+            new Set(names).forEach(function(name) {
+                const option = document.createElement('option');
+                option.value = name;
+                options.appendChild(option);
+            });
+            document.getElementById('keySuggestions').replaceChildren(options);
+        }
+        updateKeySuggestions();
+
+        const groupToggle = document.getElementById('groupByType');
+        groupToggle.checked = groupByType;
+        groupToggle.addEventListener('change', function() {
+            groupByType = groupToggle.checked;
+            localStorage.setItem(groupStorageKey, String(groupByType));
+            load();
+        });
+        const treeToggle = document.getElementById('keyTree');
+        treeToggle.checked = keyTree;
+        treeToggle.addEventListener('change', function() {
+            keyTree = treeToggle.checked;
+            localStorage.setItem(treeStorageKey, String(keyTree));
+            load();
+        });
+        load();
+    </script>
+</body>
+</html>`
+
+	tmplParsed, err := parseTemplate("index", tmpl)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Template error: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	data := struct {
+		GroupByType  bool
+		KeyDelimiter string
+	}{
+		GroupByType:  groupByType,
+		KeyDelimiter: keyDelimiter,
+	}
+	if err := tmplParsed.Execute(w, data); err != nil {
+		log.Printf("Error rendering template: %v", err)
+	}
+}
+
+// headList answers a HEAD request for the list at key using only TYPE and
+// LLEN: 200 with the length in X-List-Length, or 404 when key is not a
+// non-empty list, with X-Key-Type naming the type of a key of another type.
+func headList(w http.ResponseWriter, ctx context.Context, client redis.UniversalClient, key string) {
+	llen, err := inspector.ListLength(ctx, client, key)
+	var wrongType *inspector.WrongTypeError
+	switch {
+	case err == nil:
+		w.Header().Set("X-List-Length", strconv.FormatInt(llen, 10))
+		w.WriteHeader(http.StatusOK)
+	case errors.As(err, &wrongType):
+		w.Header().Set("X-Key-Type", wrongType.Type)
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(err, inspector.ErrKeyNotFound), errors.Is(err, inspector.ErrEmptyList):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		log.Printf("Error checking list %q: %v", displayKey(key), err)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func lindexHandler(w http.ResponseWriter, r *http.Request) {
+	query := lenientQuery(r.URL.RawQuery)
+	key := query.Get("key")
+	indexStr := query.Get("index")
+
+	// Scripts asking for JSON get the element and API errors rather than pages
+	asJSON := wantsJSON(r)
+	w.Header().Add("Vary", "Accept")
+
+	if key == "" {
+		lindexFail(w, asJSON, renderNotFound, http.StatusBadRequest, apiErrInvalidParameter, "Missing 'key' parameter")
+		return
 	}
 
-	// Check if key exists and is a list
-	keyType, err := redisClient.Type(ctx, key).Result()
+	// A slow read can be given longer than the default Redis timeout
+	client, reqCtx, cancel, err := requestRedis(r, query.Get("timeout"))
 	if err != nil {
-		renderError(w, fmt.Sprintf("Error checking key: %v", err))
+		lindexFail(w, asJSON, renderBadRequest, http.StatusBadRequest, apiErrInvalidParameter, err.Error())
+		return
+	}
+	defer cancel()
+
+	// HEAD is a cheap probe for monitoring, so the list is not loaded
+	if r.Method == http.MethodHead {
+		headList(w, reqCtx, client, key)
 		return
 	}
 
-	if keyType == "none" {
-		renderNotFound(w, fmt.Sprintf("Key '%s' does not exist", key))
+	// Several indices are fetched individually rather than preloading the list
+	if strings.Contains(indexStr, ",") {
+		if asJSON {
+			writeAPIError(w, http.StatusBadRequest, apiErrInvalidIndex, "Comparing several indices is only available as HTML; request each index separately")
+			return
+		}
+		renderComparison(w, r, reqCtx, client, key, indexStr)
 		return
 	}
 
-	if keyType != "list" {
-		renderNotFound(w, fmt.Sprintf("Key '%s' is not a list (type: %s)", key, keyType))
+	// How long the key sat idle is read first, as loading the list resets it
+	var access, encoding, memory string
+	if !asJSON {
+		access = describeAccess(reqCtx, client, key)
+		encoding = describeEncoding(reqCtx, client, key)
+		memory = describeMemory(reqCtx, client, key)
+	}
+
+	// Load the list, checking that the key exists and is a non-empty list.
+	// Long lists may be limited to their newest elements
+	direction := inspector.MatchDirection(directionRules, key)
+	windowed := preloadNewest > 0 && query.Get("full") == ""
+	var list *inspector.List
+	if windowed {
+		list, err = loadNewest(reqCtx, client, key, direction.HeadIsNewest())
+	} else {
+		list, err = inspector.InspectList(reqCtx, client, key)
+	}
+	if ttl, _ := strconv.Atoi(query.Get("ttl")); ttl > 0 && errors.Is(err, inspector.ErrKeyNotFound) {
+		// The link came from the index page, which saw the key with an expiry
+		lindexFail(w, asJSON, renderNotFound, http.StatusNotFound, apiErrNotFound, fmt.Sprintf("Key '%s' no longer exists. It had a TTL of %ds when it was listed, so it has most likely expired.", displayKey(key), ttl))
+		return
+	}
+	if err != nil && asJSON {
+		writeAPIListError(w, key, err)
 		return
 	}
+	if err != nil {
+		renderListError(w, key, err)
+		return
+	}
+	llen := list.Length
 
-	// Get list length
-	llen, err := redisClient.LLen(ctx, key).Result()
+	// Resolve the requested position, defaulting to tail (newest item)
+	index, err := resolveIndex(indexStr, query.Get("newest"), llen, direction.HeadIsNewest())
 	if err != nil {
-		renderError(w, fmt.Sprintf("Error getting list length: %v", err))
+		lindexFail(w, asJSON, renderBadRequest, http.StatusBadRequest, apiErrInvalidIndex, err.Error())
 		return
 	}
 
-	if llen == 0 {
-		renderNotFound(w, fmt.Sprintf("List '%s' is empty", key))
+	// A tracked element is looked for around where it was last seen, so its
+	// old index may now be past the end of the list. track=1 starts tracking
+	// the element at the index without a hash yet
+	tracking := query.Get("track") != ""
+	hash := query.Get("track")
+	locate := tracking && hash != "1"
+
+	// Check bounds
+	if !locate && (index < 0 || index >= llen) {
+		lindexFail(w, asJSON, renderNotFound, http.StatusNotFound, apiErrOutOfBounds, fmt.Sprintf("Index %d out of bounds (list length: %d)", index, llen))
 		return
 	}
+	at := min(max(index, 0), llen-1)
 
-	// Parse index, defaulting to tail (newest item) if not provided
-	var index int64
-	if indexStr == "" {
-		// Default to tail (last index, newest item)
-		index = llen - 1
-	} else {
-		index, err = strconv.ParseInt(indexStr, 10, 64)
+	// An older element than the newest window holds is shown with the
+	// elements around it instead
+	if windowed && (at < list.Offset || at >= list.Offset+int64(len(list.Values))) {
+		start := max(at-preloadNewest/2, 0)
+		list, err = inspector.InspectRange(reqCtx, client, key, start, start+preloadNewest-1)
+		if err != nil && asJSON {
+			writeAPIListError(w, key, err)
+			return
+		}
 		if err != nil {
-			renderNotFound(w, "Invalid 'index' parameter")
+			renderListError(w, key, err)
 			return
 		}
+		// The list may have shrunk since its length was read
+		llen = list.Length
 	}
 
-	// Check bounds
-	if index < 0 || index >= llen {
-		renderNotFound(w, fmt.Sprintf("Index %d out of bounds (list length: %d)", index, llen))
+	// The tracked element is followed to wherever the list has shifted it
+	// among the loaded elements
+	var trackNotice string
+	if locate {
+		switch found := inspector.LocateElement(list.Values, list.Offset, hash, index); {
+		case found < 0:
+			index = min(max(index, list.Offset), list.Offset+int64(len(list.Values))-1)
+			if int64(len(list.Values)) < llen {
+				trackNotice = fmt.Sprintf("The element you were tracking is not among the elements loaded around its last index (it may have been consumed or trimmed), so index %d is shown instead.", index)
+			} else {
+				trackNotice = fmt.Sprintf("The element you were tracking is no longer in the list (consumed or trimmed), so index %d is shown instead.", index)
+			}
+		case found != index:
+			trackNotice = fmt.Sprintf("The element you were tracking moved from index %d to %d.", index, found)
+			index = found
+		}
+	}
+
+	if index >= llen {
+		lindexFail(w, asJSON, renderNotFound, http.StatusNotFound, apiErrOutOfBounds, fmt.Sprintf("Index %d out of bounds (list length: %d)", index, llen))
 		return
 	}
+	// Values are added up from the current element outward, and those past
+	// MAX_PRELOAD_BYTES are dropped before anything is rendered; navigating
+	// beyond the rest falls back to loading from the server
+	budgeted := list.TrimAround(index, maxPreloadSize)
+	if budgeted {
+		log.Printf("Values of %q exceed MAX_PRELOAD_BYTES (%d); loaded elements %d-%d of %d", key, maxPreloadSize, list.Offset, list.Offset+int64(len(list.Values))-1, llen)
+	}
+
+	// The loaded elements are embedded in the page for instant navigation
+	allValues := list.Values
+	offset := list.Offset
 
-	// Get all elements from the list at once for instant navigation
-	// Note: All Redis lists in this system are guaranteed to be small enough to preload
-	allValues, err := redisClient.LRange(ctx, key, 0, -1).Result()
+	// Transform and pretty-print all values, adding any stages chosen for this view
+	viewTransforms, err := parseViewTransforms(query.Get("transform"))
 	if err != nil {
-		renderError(w, fmt.Sprintf("Error getting list elements: %v", err))
+		lindexFail(w, asJSON, renderBadRequest, http.StatusBadRequest, apiErrInvalidParameter, err.Error())
 		return
 	}
-
-	// Pretty-print all JSON values
 	prettyValues := make([]string, len(allValues))
+	var cuts []*valueCut
+	filters := newFilterRun()
+	render := func(i int) {
+		// Oversized elements are shown raw and cut short on the page, which
+		// loads the rest from /api/value-stream on request
+		if shown, cut := cutValue(allValues[i]); cut != nil && !asJSON {
+			if cuts == nil {
+				cuts = make([]*valueCut, len(allValues))
+			}
+			cuts[i] = cut
+			prettyValues[i] = shown
+			return
+		}
+		prettyValues[i] = renderValueWith(key, allValues[i], viewTransforms, filters)
+	}
+
+	// The current element is rendered first, so it is filtered while the
+	// VALUE_FILTER_BUDGET lasts
+	render(int(index - offset))
+	for i := range allValues {
+		if i != int(index-offset) {
+			render(i)
+		}
+	}
+	if filters.skipped > 0 {
+		log.Printf("VALUE_FILTER_BUDGET (%s) spent rendering %q; %d of %d elements shown unfiltered", valueFilterBudget, key, filters.skipped, len(allValues))
+	}
+
+	// Validate against the configured JSON Schema, if any
+	var validation *inspector.SchemaReport
+	if rule := inspector.MatchSchema(schemaRules, key); rule != nil {
+		validation = rule.ValidateList(allValues)
+		for i := range validation.Failing {
+			validation.Failing[i] += offset
+		}
+	}
+
+	// Flag elements matching the configured alert patterns, if any
+	var alerting []int64
+	if rule := inspector.MatchAlerts(alertRules, key); rule != nil {
+		alerting = rule.Alerting(allValues)
+		for i := range alerting {
+			alerting[i] += offset
+		}
+	}
+
+	// Summarize elements on one line from the configured fields, if any
+	var summaries []string
+	if rule := inspector.MatchSummary(summaryRules, key); rule != nil {
+		summaries = make([]string, len(allValues))
+		for i, value := range allValues {
+			summaries[i] = rule.Summarize(value)
+		}
+	}
+
+	// Note bare numbers, booleans and nulls, which render the same as text,
+	// values that are not UTF-8 text, which are better read as a hexdump, and
+	// JSON stored as a string, which pretty-prints as one escaped line
+	scalarKinds := make([]string, len(allValues))
+	binary := make([]bool, len(allValues))
+	wrapped := make([]int, len(allValues))
+	stats := make([]valueStats, len(allValues))
 	for i, value := range allValues {
-		prettyValues[i] = prettyPrintJSON(value)
+		stats[i] = measureValue(value)
+		scalarKinds[i] = inspector.ScalarKind(value)
+		binary[i] = !utf8.ValidString(value)
+		_, wrapped[i] = inspector.UnwrapJSON([]byte(value))
+	}
+
+	recordAccess(r, key, index)
+
+	// Tracking needs the hash of whichever element is navigated to
+	var hashes []string
+	if tracking {
+		hashes = make([]string, len(allValues))
+		for i, value := range allValues {
+			hashes[i] = inspector.ElementHash(value)
+		}
+	}
+
+	// Scripts get the selected element with what was worked out for the page
+	if asJSON {
+		position := index - offset
+		element := lindexElement{
+			Key:      key,
+			Display:  displayKey(key),
+			Index:    index,
+			Length:   llen,
+			Value:    allValues[position],
+			Rendered: prettyValues[position],
+			Alerting: slices.Contains(alerting, index),
+		}
+		if summaries != nil {
+			element.Summary = summaries[position]
+		}
+		if validation != nil {
+			valid := validation.Errors[position] == ""
+			element.SchemaValid = &valid
+			element.SchemaError = validation.Errors[position]
+		}
+		writeJSON(w, http.StatusOK, element)
+		return
 	}
 
 	// Render the result with all values preloaded
-	renderResultWithPreload(w, key, index, llen, prettyValues)
+	renderResultWithPreload(w, resultPage{
+		Key:         key,
+		Index:       index,
+		LLen:        llen,
+		Offset:      offset,
+		Values:      prettyValues,
+		Budgeted:    budgeted,
+		ScalarKinds: scalarKinds,
+		Binary:      binary,
+		Wrapped:     wrapped,
+		Stats:       stats,
+		Schema:      validation,
+		Alerting:    alerting,
+		Summaries:   summaries,
+		Direction:   direction,
+		Search:      query.Get("search"),
+		Lines:       query.Get("lines") == "1",
+		Markdown:    query.Get("md") == "1",
+		Tracking:    tracking,
+		TrackNotice: trackNotice,
+		Hashes:      hashes,
+		Access:      access,
+		Encoding:    encoding,
+		Memory:      memory,
+		Cuts:        cuts,
+		Transforms:  viewTransforms,
+	})
 }
 
-func prettyPrintJSON(value string) string {
-	var jsonData interface{}
-	if err := json.Unmarshal([]byte(value), &jsonData); err != nil {
-		// Not valid JSON, return as-is
-		return value
+// describeAccess summarises how recently the key was accessed for the result
+// page's metadata, returning "" when the server does not report it.
+func describeAccess(ctx context.Context, client redis.UniversalClient, key string) string {
+	if !redisCapabilities.ObjectIdleTime {
+		return ""
 	}
-
-	prettyJSON, err := json.MarshalIndent(jsonData, "", "  ")
+	access, err := inspector.Access(ctx, client, key)
 	if err != nil {
-		// Fallback to original value
-		return value
+		debugf("Reading access time of %q: %v", key, err)
+		return ""
+	}
+	if access.LFU {
+		return fmt.Sprintf("access frequency %d (the server uses an LFU eviction policy, so idle time is not tracked)", access.Freq)
+	}
+	return fmt.Sprintf("idle for %s before this page loaded", access.IdleTime)
+}
+
+// loadNewest loads the newest preloadNewest elements of the list at key, which
+// are at the head of lists populated with LPUSH and at the tail otherwise.
+func loadNewest(ctx context.Context, client redis.UniversalClient, key string, headIsNewest bool) (*inspector.List, error) {
+	if headIsNewest {
+		return inspector.InspectRange(ctx, client, key, 0, preloadNewest-1)
+	}
+	return inspector.InspectRange(ctx, client, key, -preloadNewest, -1)
+}
+
+// lenientQuery parses a raw query string like url.ParseQuery, but treats a
+// '%' that does not start a valid escape as a literal, so that a hand-typed
+// index=90% is not silently dropped.
+func lenientQuery(rawQuery string) url.Values {
+	var b strings.Builder
+	for i := 0; i < len(rawQuery); i++ {
+		if rawQuery[i] == '%' && (i+2 >= len(rawQuery) || !isHex(rawQuery[i+1]) || !isHex(rawQuery[i+2])) {
+			b.WriteString("%25")
+			continue
+		}
+		b.WriteByte(rawQuery[i])
+	}
+	values, _ := url.ParseQuery(b.String())
+	return values
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// renderListError renders the page for a key that could not be inspected as a
+// list, recording it in the errored-keys report where relevant.
+func renderListError(w http.ResponseWriter, key string, err error) {
+	var wrongType *inspector.WrongTypeError
+	switch {
+	case errors.Is(err, inspector.ErrKeyNotFound):
+		renderNotFound(w, fmt.Sprintf("Key '%s' does not exist", displayKey(key)))
+	case errors.As(err, &wrongType) && wrongType.Type == "stream":
+		renderNotFound(w, fmt.Sprintf("Key '%s' is a stream, not a list. Its consumer groups are shown at /stream?key=%s", displayKey(key), url.QueryEscape(key)))
+	case errors.As(err, &wrongType):
+		erroredKeys.record(key, errorKindWrongType, fmt.Sprintf("expected list, found %s", wrongType.Type))
+		renderNotFound(w, fmt.Sprintf("Key '%s' is not a list (type: %s)", displayKey(key), wrongType.Type))
+	case errors.Is(err, inspector.ErrEmptyList):
+		renderNotFound(w, fmt.Sprintf("List '%s' is empty", displayKey(key)))
+	default:
+		erroredKeys.record(key, errorKindRedis, err.Error())
+		renderError(w, "Error "+err.Error())
+	}
+}
+
+// resolveIndex converts the index and newest query parameters into an absolute
+// list index. A negative index counts back from the tail as LINDEX does (-1 is
+// the last element), an index such as "90%" selects that fraction of the way
+// from the head to the tail, the keywords first, last and mid select index 0,
+// llen-1 and llen/2, and newest=N selects the Nth most recently pushed
+// element. With neither set, the newest element is selected. The newest
+// element is at the tail unless headIsNewest is set.
+func resolveIndex(indexStr, newestStr string, llen int64, headIsNewest bool) (int64, error) {
+	switch {
+	case strings.HasSuffix(indexStr, "%"):
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(indexStr, "%"), 64)
+		if err != nil || math.IsNaN(percent) || percent < 0 || percent > 100 {
+			return 0, errors.New("Invalid 'index' percentage: expected a number from 0% to 100%")
+		}
+		return int64(math.Floor(float64(llen-1) * percent / 100)), nil
+	case indexStr == "first":
+		return 0, nil
+	case indexStr == "last":
+		return llen - 1, nil
+	case indexStr == "mid":
+		return llen / 2, nil
+	case indexStr != "":
+		index, err := strconv.ParseInt(indexStr, 10, 64)
+		if err != nil {
+			return 0, errors.New("Invalid 'index' parameter: expected an integer, a percentage such as 90%, or first, last or mid")
+		}
+		if index < 0 {
+			index += llen
+		}
+		return index, nil
+	case newestStr != "":
+		n, err := strconv.ParseInt(newestStr, 10, 64)
+		if err != nil || n < 1 {
+			return 0, errors.New("Invalid 'newest' parameter")
+		}
+		if headIsNewest {
+			return n - 1, nil
+		}
+		return llen - n, nil
+	default:
+		if headIsNewest {
+			return 0, nil
+		}
+		return llen - 1, nil
+	}
+}
+
+// prettyPrintJSON pretty-prints value, using the value cache when enabled and
+// hiding levels nested deeper than MAX_JSON_DEPTH.
+func prettyPrintJSON(value string) string {
+	if jsonKeyOrder == "stored" {
+		return prettyJSON.PrettyPrintStored(value, maxJSONDepth)
 	}
+	return prettyJSON.PrettyPrintDepth(value, maxJSONDepth)
+}
 
-	return string(prettyJSON)
+// resultPage is what the result page shows for one list.
+type resultPage struct {
+	Key         string
+	Index       int64
+	LLen        int64
+	Offset      int64                   // Index of the first element in Values, when only part of the list was loaded
+	Values      []string                // Rendered (transformed, pretty-printed) elements
+	Budgeted    bool                    // Only the elements around Index were loaded, as the values exceed MAX_PRELOAD_BYTES
+	ScalarKinds []string                // Per element, the kind of bare JSON scalar it holds, if any
+	Binary      []bool                  // Per element, whether it is not valid UTF-8
+	Wrapped     []int                   // Per element, the layers of string encoding around a JSON document
+	Stats       []valueStats            // Per element, its stored size in bytes, characters and lines
+	Schema      *inspector.SchemaReport // Schema validation results, nil when no schema applies
+	Alerting    []int64                 // Indices of elements matching an alert pattern
+	Summaries   []string                // Per element, a one-line summary from SUMMARY_FIELDS, nil when none applies
+	Direction   inspector.PushDirection
+	Search      string      // Search to run when the page loads
+	Lines       bool        // Whether the value is split into lines
+	Markdown    bool        // Whether the value is rendered as Markdown
+	Tracking    bool        // Whether the shown element is followed by its content hash
+	TrackNotice string      // Where the tracked element was found, if it moved or is gone
+	Hashes      []string    // Per element, its content hash when tracking, nil otherwise
+	Access      string      // How recently the key was accessed, from OBJECT IDLETIME or FREQ
+	Encoding    string      // The key's internal encoding from OBJECT ENCODING, "" when unavailable
+	Memory      string      // The key's memory footprint from MEMORY USAGE, "" when unavailable
+	Cuts        []*valueCut // Per element, how it was cut short when over MAX_VALUE_BYTES, nil when none was
+	Transforms  []string    // Extra transform stages applied for this view
 }
 
-func renderResultWithPreload(w http.ResponseWriter, key string, index int64, llen int64, allValues []string) {
+// copyAllBlocked returns why the result page cannot offer to copy the whole
+// list, or "" when every element is on the page in full.
+func copyAllBlocked(page resultPage) string {
+	switch {
+	case page.Budgeted:
+		return "Copy all needs every element loaded, and these values are too large (see MAX_PRELOAD_BYTES)"
+	case int64(len(page.Values)) < page.LLen:
+		return "Only part of this list is loaded; load the full list to copy every element"
+	}
+	for _, cut := range page.Cuts {
+		if cut != nil {
+			return "Some elements are too large to show in full (see MAX_VALUE_BYTES), so a copy would be incomplete"
+		}
+	}
+	return ""
+}
+
+func renderResultWithPreload(w http.ResponseWriter, page resultPage) {
 	tmplStr := `<!DOCTYPE html>
 <html>
 <head>
-    <title>RediScan - {{.Key}}[{{.Index}}]</title>
+    <title>RediScan - {{displayKey .Key}}[{{.Index}}]</title>
     <style>
         body {
             font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif;
@@ -449,6 +1815,24 @@ func renderResultWithPreload(w http.ResponseWriter, key string, index int64, lle
             background-color: #ccc;
             cursor: not-allowed;
         }
+        .navigation .follow-link {
+            color: #2196F3;
+            text-decoration: none;
+            font-size: 14px;
+        }
+        .navigation .wrap-mode {
+            font-size: 14px;
+            color: #666;
+        }
+        .navigation .copy-all {
+            display: flex;
+            gap: 4px;
+            align-items: center;
+        }
+        .navigation .copy-all button {
+            padding: 6px 12px;
+            font-size: 14px;
+        }
         .navigation .info {
             flex-grow: 1;
             text-align: center;
@@ -469,6 +1853,106 @@ func renderResultWithPreload(w http.ResponseWriter, key string, index int64, lle
             white-space: pre-wrap;
             word-wrap: break-word;
         }
+        .json-path {
+            display: flex;
+            gap: 10px;
+            align-items: center;
+            margin-bottom: 8px;
+            font-size: 13px;
+        }
+        .json-path code {
+            background-color: #e3f2fd;
+            padding: 3px 8px;
+            border-radius: 3px;
+            overflow-x: auto;
+            white-space: nowrap;
+        }
+        .json-path.pinned code {
+            background-color: #bbdefb;
+        }
+        .tabular-toggle {
+            display: inline-block;
+            margin-bottom: 8px;
+            color: #2196F3;
+            font-size: 14px;
+        }
+        .tabular-display {
+            max-height: 600px;
+            overflow: auto;
+            border: 1px solid #ddd;
+            border-radius: 3px;
+        }
+        .tabular-display table {
+            border-collapse: collapse;
+            font-family: monospace;
+            font-size: 13px;
+        }
+        .tabular-display th, .tabular-display td {
+            border: 1px solid #eee;
+            padding: 4px 8px;
+            text-align: left;
+            vertical-align: top;
+            white-space: pre-wrap;
+        }
+        .tabular-display th {
+            background-color: #f4f4f4;
+            position: sticky;
+            top: 0;
+        }
+        .tabular-display .row-number {
+            color: #999;
+        }
+        .tabular-display .json-cell {
+            color: #1565c0;
+        }
+        .lines-display {
+            background-color: #f4f4f4;
+            border: 1px solid #ddd;
+            border-radius: 3px;
+            max-height: 600px;
+            overflow-y: auto;
+            margin: 0;
+            padding: 10px 10px 10px 60px;
+            font-family: monospace;
+        }
+        .lines-display li {
+            white-space: pre-wrap;
+            word-wrap: break-word;
+            padding: 2px 0;
+            border-bottom: 1px solid #e8e8e8;
+        }
+        .lines-display li::marker {
+            color: #999;
+        }
+        .value-cut {
+            background-color: #fff8e1;
+            border: 1px solid #ffe082;
+            padding: 10px 15px;
+            border-radius: 3px;
+            margin-bottom: 15px;
+        }
+        .value-cut .error {
+            color: #c62828;
+        }
+        .markdown-display {
+            background-color: #fff;
+            border: 1px solid #ddd;
+            border-radius: 3px;
+            padding: 0 15px;
+            overflow-x: auto;
+            word-wrap: break-word;
+        }
+        .markdown-display pre {
+            background-color: #f4f4f4;
+            padding: 10px;
+            border-radius: 3px;
+        }
+        .markdown-display blockquote {
+            border-left: 4px solid #ddd;
+            margin-left: 0;
+            padding-left: 12px;
+            color: #555;
+        }
         .back-link {
             display: inline-block;
             margin-top: 20px;
@@ -478,6 +1962,40 @@ func renderResultWithPreload(w http.ResponseWriter, key string, index int64, lle
         .back-link:hover {
             text-decoration: underline;
         }
+        .shortcut-hint {
+            margin-left: 20px;
+            color: #999;
+            font-size: 14px;
+        }
+        kbd {
+            display: inline-block;
+            padding: 1px 6px;
+            border: 1px solid #ccc;
+            border-radius: 3px;
+            background-color: #f7f7f7;
+            font-family: monospace;
+            font-size: 0.9em;
+        }
+        .shortcut-overlay {
+            position: fixed;
+            inset: 0;
+            background-color: rgba(0,0,0,0.4);
+            display: flex;
+            align-items: center;
+            justify-content: center;
+        }
+        .shortcut-overlay[hidden] {
+            display: none;
+        }
+        .shortcut-dialog {
+            background-color: white;
+            padding: 20px 30px;
+            border-radius: 5px;
+            box-shadow: 0 4px 12px rgba(0,0,0,0.3);
+        }
+        .shortcut-dialog td {
+            padding: 4px 12px 4px 0;
+        }
         .slider-container {
             background-color: white;
             padding: 15px;
@@ -516,31 +2034,406 @@ func renderResultWithPreload(w http.ResponseWriter, key string, index int64, lle
             cursor: pointer;
             border: none;
         }
-        @media (max-width: 600px) {
-            .slider-container input[type="range"]::-webkit-slider-thumb {
-                width: 30px;
-                height: 30px;
-            }
-            .slider-container input[type="range"]::-moz-range-thumb {
+        .aggregate-form {
+            background-color: white;
+            padding: 15px;
+            border-radius: 5px;
+            margin-top: 20px;
+            box-shadow: 0 2px 4px rgba(0,0,0,0.1);
+            display: flex;
+            gap: 10px;
+            align-items: center;
+        }
+        .aggregate-form label {
+            font-weight: bold;
+        }
+        .aggregate-form input {
+            flex-grow: 1;
+            padding: 8px;
+            border: 1px solid #ddd;
+            border-radius: 3px;
+        }
+        .aggregate-form button {
+            background-color: #2196F3;
+            color: white;
+            padding: 8px 16px;
+            border: none;
+            border-radius: 3px;
+            cursor: pointer;
+        }
+        .push-form {
+            background-color: #fff8e1;
+            padding: 15px;
+            border-radius: 5px;
+            margin-top: 20px;
+            border-left: 4px solid #f57c00;
+            box-shadow: 0 2px 4px rgba(0,0,0,0.1);
+        }
+        .push-form h2 {
+            margin-top: 0;
+            font-size: 18px;
+        }
+        .preload-notice {
+            color: #e65100;
+        }
+        .push-form .warning {
+            color: #e65100;
+            font-weight: bold;
+        }
+        .push-form textarea {
+            width: 100%;
+            min-height: 80px;
+            padding: 8px;
+            border: 1px solid #ddd;
+            border-radius: 3px;
+            font-family: monospace;
+            box-sizing: border-box;
+        }
+        .push-form .options {
+            display: flex;
+            gap: 15px;
+            align-items: center;
+            margin-top: 10px;
+        }
+        .push-form button {
+            background-color: #f57c00;
+            color: white;
+            padding: 8px 16px;
+            border: none;
+            border-radius: 3px;
+            cursor: pointer;
+        }
+        .offset-container {
+            background-color: white;
+            padding: 15px;
+            border-radius: 5px;
+            margin-bottom: 20px;
+            box-shadow: 0 2px 4px rgba(0,0,0,0.1);
+            display: flex;
+            gap: 10px;
+            align-items: center;
+        }
+        .offset-container label {
+            font-weight: bold;
+        }
+        .offset-container input {
+            width: 100px;
+            padding: 8px;
+            border: 1px solid #ddd;
+            border-radius: 3px;
+        }
+        .offset-container button {
+            background-color: #2196F3;
+            color: white;
+            padding: 8px 16px;
+            border: none;
+            border-radius: 3px;
+            cursor: pointer;
+        }
+        .offset-container button:hover {
+            background-color: #0b7dda;
+        }
+        .track-notice {
+            background-color: #fff8e1;
+            border: 1px solid #ffe082;
+            padding: 10px 15px;
+            border-radius: 3px;
+            margin-bottom: 15px;
+        }
+        .schema-status {
+            padding: 10px 15px;
+            border-radius: 3px;
+            margin-bottom: 10px;
+            white-space: pre-wrap;
+        }
+        .schema-status.pass {
+            background-color: #e8f5e9;
+            color: #2e7d32;
+        }
+        .schema-status.fail {
+            background-color: #ffebee;
+            color: #c62828;
+            font-family: monospace;
+        }
+        .schema-failing a {
+            color: #c62828;
+            margin-right: 5px;
+        }
+        .alert-count {
+            background-color: #c62828;
+            color: white;
+            padding: 2px 8px;
+            border-radius: 10px;
+            font-size: 0.85em;
+        }
+        .alert-badge {
+            display: none;
+            background-color: #c62828;
+            color: white;
+            padding: 2px 8px;
+            border-radius: 3px;
+            font-size: 0.7em;
+            vertical-align: middle;
+        }
+        .value-container.alerting {
+            border: 2px solid #c62828;
+        }
+        .value-container.alerting .alert-badge {
+            display: inline;
+        }
+        .table-row.alerting .row-index {
+            color: #c62828;
+            font-weight: bold;
+        }
+        .view-transforms {
+            display: flex;
+            gap: 8px;
+            align-items: center;
+            margin-bottom: 10px;
+            font-size: 14px;
+        }
+        .view-transforms .applied {
+            font-family: monospace;
+            background-color: #e3f2fd;
+            padding: 2px 6px;
+            border-radius: 3px;
+        }
+        .diagnosis {
+            background-color: #f9f9f9;
+            border: 1px solid #ddd;
+            border-radius: 3px;
+            padding: 10px 15px;
+            margin-bottom: 10px;
+            font-size: 14px;
+        }
+        .diagnosis td {
+            padding: 2px 10px 2px 0;
+        }
+        .diagnosis .pass {
+            color: #2e7d32;
+        }
+        .diagnosis .fail {
+            color: #c62828;
+        }
+        .binary-hint {
+            font-size: 14px;
+            font-weight: normal;
+            color: #4527a0;
+            font-family: monospace;
+        }
+        .wrapped-hint {
+            font-size: 14px;
+            font-weight: normal;
+            color: #00695c;
+            font-family: monospace;
+        }
+        .scalar-hint {
+            font-size: 14px;
+            font-weight: normal;
+            color: #666;
+            font-family: monospace;
+        }
+        .search-container {
+            background-color: white;
+            padding: 15px;
+            border-radius: 5px;
+            margin-bottom: 20px;
+            box-shadow: 0 2px 4px rgba(0,0,0,0.1);
+            display: flex;
+            gap: 10px;
+            align-items: center;
+            flex-wrap: wrap;
+        }
+        .search-container label {
+            font-weight: bold;
+        }
+        .search-container input {
+            flex: 1;
+            min-width: 150px;
+            padding: 8px;
+            border: 1px solid #ddd;
+            border-radius: 3px;
+        }
+        .search-container button {
+            background-color: #2196F3;
+            color: white;
+            padding: 8px 16px;
+            border: none;
+            border-radius: 3px;
+            cursor: pointer;
+        }
+        .search-container button:hover {
+            background-color: #0b7dda;
+        }
+        .search-status {
+            color: #666;
+        }
+        .table-view {
+            background-color: white;
+            padding: 15px;
+            border-radius: 5px;
+            margin-bottom: 20px;
+            box-shadow: 0 2px 4px rgba(0,0,0,0.1);
+        }
+        .table-view .hint {
+            color: #666;
+            font-size: 14px;
+        }
+        .snapshot-diff {
+            margin-top: 10px;
+            font-family: monospace;
+            font-size: 13px;
+        }
+        .snapshot-diff div {
+            white-space: nowrap;
+            overflow: hidden;
+            text-overflow: ellipsis;
+        }
+        .snapshot-diff .added {
+            color: #2e7d32;
+        }
+        .snapshot-diff .removed {
+            color: #c62828;
+        }
+        .snapshot-diff .changed {
+            color: #e65100;
+        }
+        .snapshot-diff a {
+            color: inherit;
+        }
+        .changes-list {
+            margin-top: 10px;
+            max-height: 400px;
+            overflow-y: auto;
+            font-family: monospace;
+            font-size: 13px;
+        }
+        .changes-list .change {
+            padding-left: 20px;
+            white-space: pre-wrap;
+            word-break: break-all;
+        }
+        .changes-list pre {
+            margin: 4px 0 4px 20px;
+            max-height: 150px;
+            overflow: auto;
+        }
+        .changes-list a {
+            color: #2196F3;
+        }
+        .table-view summary {
+            font-weight: bold;
+            cursor: pointer;
+        }
+        .table-viewport {
+            position: relative;
+            height: 400px;
+            overflow-y: auto;
+            margin-top: 10px;
+            border: 1px solid #ddd;
+            border-radius: 3px;
+        }
+        .table-row {
+            position: absolute;
+            left: 0;
+            right: 0;
+            height: 28px;
+            line-height: 28px;
+            padding: 0 10px;
+            box-sizing: border-box;
+            font-family: monospace;
+            font-size: 13px;
+            white-space: nowrap;
+            overflow: hidden;
+            text-overflow: ellipsis;
+            cursor: pointer;
+            border-bottom: 1px solid #eee;
+        }
+        .table-row:hover {
+            background-color: #f5f5f5;
+        }
+        .table-row.current {
+            background-color: #e3f2fd;
+        }
+        .table-row .row-index {
+            display: inline-block;
+            min-width: 60px;
+            color: #666;
+        }
+        @media (max-width: 600px) {
+            .slider-container input[type="range"]::-webkit-slider-thumb {
+                width: 30px;
+                height: 30px;
+            }
+            .slider-container input[type="range"]::-moz-range-thumb {
                 width: 30px;
                 height: 30px;
             }
         }
     </style>
+    {{customCSSLink}}
+    {{prefsScript}}
 </head>
 <body>
+    {{persistenceBanner}}
     <h1><a href="/">RediScan - Redis List Inspector</a></h1>
     
     <div class="metadata">
-        <p><strong>Key:</strong> {{.Key}}</p>
+        <p><strong>Key:</strong> {{displayKey .Key}}</p>
         <p><strong>Index:</strong> {{.Index}}</p>
         <p><strong>List Length:</strong> {{.LLen}}</p>
+        <p><strong>Position:</strong> <span id="positionText"></span></p>
+        <p><strong>Same value at:</strong> <span id="duplicatesText" class="duplicates"></span></p>
+        <p><strong>Size:</strong> <span id="sizeText"></span></p>
+        {{if .Access}}<p><strong>Last Access:</strong> {{.Access}}</p>{{end}}
+        {{if .Encoding}}<p><strong>Encoding:</strong> {{.Encoding}}</p>{{end}}
+        {{if .Memory}}<p><strong>Memory:</strong> {{.Memory}} (approximate, from MEMORY USAGE)</p>{{end}}
+        {{if eq .Direction "lpush"}}
+        <p><strong>Ordering:</strong> Populated via LPUSH &rarr; head (index 0) is newest</p>
+        {{else if eq .Direction "rpush"}}
+        <p><strong>Ordering:</strong> Populated via RPUSH &rarr; tail (index {{.MaxIndex}}) is newest</p>
+        {{end}}
+        {{if .Schema}}
+        <p><strong>Schema:</strong> {{.Schema.Schema}} &mdash;
+            {{if .Schema.Failing}}{{len .Schema.Failing}} of {{len .AllValues}} elements fail
+            <span class="schema-failing">({{range $i, $idx := .Schema.Failing}}{{if lt $i 50}}<a href="#" onclick="updateToIndex({{$idx}}); return false;">{{$idx}}</a>{{end}}{{end}}{{if gt (len .Schema.Failing) 50}}&hellip;{{end}})</span>
+            {{else}}all elements conform{{end}}
+        </p>
+        {{end}}
+        {{if not .Preloaded}}
+        <p class="preload-notice">These values are too large to load at once, so only elements {{.Offset}}&ndash;{{.LastLoaded}} around this one were loaded; moving outside them loads the elements around the new position, and search only covers the loaded elements.</p>
+        {{else if .Partial}}
+        <p class="preload-notice">Only elements {{.Offset}}&ndash;{{.LastLoaded}} of this long list were loaded; moving outside them loads the elements around the new position. <a href="#" onclick="loadFullList(); return false;">Load the full list</a></p>
+        {{end}}
+        {{if .Alerting}}
+        <p><strong>Alerts:</strong> {{len .Alerting}} of {{len .AllValues}} elements match an alert pattern
+            <span class="schema-failing">({{range $i, $idx := .Alerting}}{{if lt $i 50}}<a href="#" onclick="updateToIndex({{$idx}}); return false;">{{$idx}}</a>{{end}}{{end}}{{if gt (len .Alerting) 50}}&hellip;{{end}})</span>
+        </p>
+        {{end}}
     </div>
 
     <div class="navigation">
-        <button id="prevBtn" onclick="navigate(-1)">← Older (Left Arrow)</button>
+        <button id="prevBtn" onclick="navigate(-1)">← {{if .Direction.HeadIsNewest}}Newer{{else}}Older{{end}} (Left Arrow)</button>
         <div class="info">{{.Index}} / {{.MaxIndex}}</div>
-        <button id="nextBtn" onclick="navigate(1)">Newer (Right Arrow) →</button>
+        {{if .Alerting}}<span class="alert-count" title="Elements matching an alert pattern">⚠ {{len .Alerting}} alerting</span>{{end}}
+        <button id="nextBtn" onclick="navigate(1)">{{if .Direction.HeadIsNewest}}Older{{else}}Newer{{end}} (Right Arrow) →</button>
+        <button id="randomBtn" onclick="jumpToRandom()" title="Show a randomly chosen element">Random</button>
+        <a href="/follow?key={{.KeyQuery}}" class="follow-link" title="Show new elements as they are pushed, like tail -f">Follow</a>
+        <a href="/sample?key={{.KeyQuery}}" class="follow-link" title="Read a few elements spread evenly over the whole list, without loading it">Sample</a>
+        <span class="copy-all">
+            <button type="button" id="copyAllBtn"{{if .CopyAllBlocked}} disabled title="{{.CopyAllBlocked}}"{{else}} title="Copy every element of the list, as shown, to the clipboard"{{end}}>Copy all</button>
+            <select id="copyAllFormat" aria-label="Copy all as"{{if .CopyAllBlocked}} disabled{{end}}>
+                <option value="json">as a JSON array</option>
+                <option value="lines">one per line</option>
+            </select>
+        </span>
+        <label for="wrapMode" class="wrap-mode">At the ends:
+            <select id="wrapMode">
+                <option value="reload">Reload newest</option>
+                <option value="wrap">Wrap around</option>
+                <option value="stop">Stop</option>
+            </select>
+        </label>
     </div>
 
     <div class="slider-container">
@@ -548,23 +2441,715 @@ func renderResultWithPreload(w http.ResponseWriter, key string, index int64, lle
         <input type="range" id="positionSlider" min="0" max="{{.MaxIndex}}" value="{{.Index}}" step="1">
     </div>
 
+    <div class="offset-container">
+        <label for="newestInput">Nth from newest:</label>
+        <input type="number" id="newestInput" min="1" max="{{.LLen}}" value="1">
+        <button onclick="jumpFromNewest()">Go</button>
+    </div>
+
+    <div class="search-container">
+        <label for="searchInput">Search:</label>
+        <input type="text" id="searchInput" value="{{.Search}}" placeholder="Text to find in element values">
+        <button onclick="searchNext()">Find next</button>
+        <span id="searchStatus" class="search-status"></span>
+    </div>
+
+    {{if .TrackNotice}}<div class="track-notice">{{.TrackNotice}}</div>{{end}}
     <div class="value-container">
-        <h2>Value:</h2>
-        <pre id="valueDisplay">{{index .AllValues .Index}}</pre>
+        <h2>Value: <span id="scalarHint" class="scalar-hint">{{with index .ScalarKinds .Position}}scalar: {{.}}{{end}}</span> <span id="binaryHint" class="binary-hint"{{if not (index .Binary .Position)}} hidden{{end}}>binary: not valid UTF-8 &middot; <a href="#" onclick="viewAsHexdump(); return false;">view as hexdump</a></span> <span id="wrappedHint" class="wrapped-hint"{{if not (index .Wrapped .Position)}} hidden{{end}}>{{if .Unwrapped}}unwrapped: {{end}}JSON stored as a string<span id="wrappedLayers">{{with index .Wrapped .Position}}{{if gt . 1}}, {{.}} layers deep{{end}}{{end}}</span>{{if not .Unwrapped}} &middot; <a href="#" onclick="unwrapValue(); return false;">unwrap and pretty-print</a>{{end}}</span> <span class="alert-badge">⚠ alert</span></h2>
+        <div class="view-transforms">
+            <label for="viewTransform">Decode:</label>
+            {{if .Transforms}}<span class="applied">{{range $i, $t := .Transforms}}{{if $i}} → {{end}}{{$t}}{{end}}</span>{{end}}
+            <select id="viewTransform">
+                {{range .TransformList}}<option value="{{.}}">{{.}}</option>{{end}}
+            </select>
+            <button type="button" onclick="addViewTransform()">Apply</button>
+            {{if .Transforms}}<button type="button" onclick="clearViewTransforms()">Reset</button>{{end}}
+            <button type="button" onclick="diagnose()" title="Check what encoding the stored value appears to use">Diagnose</button>
+            <label title="Show each line of the value separately, pretty-printing lines that are JSON"><input type="checkbox" id="splitLines"{{if .Lines}} checked{{end}}> Split lines</label>
+            <label title="Follow this element by its content, so refreshing finds it again after the list shifts (e.g. as a queue is consumed)"><input type="checkbox" id="trackElement"{{if .Tracking}} checked{{end}}> Track element</label>
+            <label title="Render the value as Markdown. Raw HTML is shown as text and only http, https and mailto links are kept"><input type="checkbox" id="markdownView"{{if .Markdown}} checked{{end}}> Markdown</label>
+        </div>
+        <div id="diagnosis" class="diagnosis" hidden></div>
+        {{if .Schema}}<div id="schemaStatus" class="schema-status"></div>{{end}}
+        <div id="valueCut" class="value-cut" hidden>
+            This element is too large to show in full: showing the first <span id="valueCutShown"></span> of <span id="valueCutSize"></span> bytes, without pretty-printing.
+            <button type="button" id="loadMoreBtn">Load more</button>
+            <button type="button" id="loadAllBtn">Load all</button>
+            <span id="valueCutError" class="error"></span>
+        </div>
+        <div id="jsonPath" class="json-path" hidden title="Path of the part of the value under the pointer. Click in the value to keep it, click again to follow the pointer">
+            <code id="jsonPathText">$</code>
+            <button type="button" id="copyPathBtn">Copy path</button>
+        </div>
+        <pre id="valueDisplay"{{if or .Lines .Markdown}} hidden{{end}}>{{index .AllValues .Position}}</pre>
+        <a href="#" id="tabularToggle" class="tabular-toggle" hidden>Show raw JSON</a>
+        <div id="tabularDisplay" class="tabular-display" hidden></div>
+        <ol id="linesDisplay" class="lines-display" hidden></ol>
+        <div id="markdownDisplay" class="markdown-display" hidden></div>
     </div>
 
+    <details class="table-view" id="tableView">
+        <summary>Table view</summary>
+        <div class="table-viewport" id="tableViewport">
+            <div id="tableSpacer"></div>
+        </div>
+    </details>
+
+    <details class="table-view" id="snapshotView">
+        <summary>Snapshot</summary>
+        {{if .Preloaded}}
+        <p class="hint">Take a snapshot of the loaded elements, reload later (for example after a deploy), then compare to see what was added, removed or changed since. The snapshot is kept in this browser.</p>
+        <button type="button" onclick="takeSnapshot()">Take snapshot</button>
+        <button type="button" id="snapshotDiffBtn" onclick="diffSnapshot()" disabled>Diff vs snapshot</button>
+        <span id="snapshotStatus" class="search-status"></span>
+        <div id="snapshotDiff" class="snapshot-diff" hidden></div>
+        {{else}}
+        <p class="hint">Snapshots need every element preloaded, and these values are too large.</p>
+        {{end}}
+    </details>
+
+    <details class="table-view" id="changesView">
+        <summary>Changes only</summary>
+        {{if .Preloaded}}
+        <p class="hint">Each element compared with the one before it, showing only the JSON fields that differ. Elements that are not JSON, or whose structure differs from the previous one, are shown in full.</p>
+        <div id="changesList" class="changes-list"></div>
+        {{else}}
+        <p class="hint">Comparing consecutive elements needs every element preloaded, and these values are too large.</p>
+        {{end}}
+    </details>
+
+    <form class="aggregate-form" onsubmit="groupByField(); return false;">
+        <label for="aggregateField">Group elements by JSON field:</label>
+        <input type="text" id="aggregateField" placeholder="e.g., status or order.state" required>
+        <button type="submit">Group</button>
+    </form>
+
+    {{if .WriteEnabled}}
+    <form class="push-form" action="/push" method="post" onsubmit="return confirm('Add this element to the live list? This cannot be undone from RediScan.');">
+        <h2>Add element</h2>
+        <p class="warning">⚠ This writes to the real list in Redis. Consumers of the queue may process the new element.</p>
+        <input type="hidden" name="key" value="{{.Key}}">
+        <textarea name="value" required placeholder="Value to push"></textarea>
+        <div class="options">
+            <label><input type="radio" name="direction" value="lpush"{{if .Direction.HeadIsNewest}} checked{{end}}> LPUSH (head)</label>
+            <label><input type="radio" name="direction" value="rpush"{{if not .Direction.HeadIsNewest}} checked{{end}}> RPUSH (tail)</label>
+            <label><input type="checkbox" name="validate_json" value="1" checked> Must be valid JSON</label>
+            <button type="submit">Add element</button>
+        </div>
+    </form>
+    {{end}}
+
     <a href="/" class="back-link">← Back to Home</a>
+    <span class="shortcut-hint">Press <kbd>?</kbd> for keyboard shortcuts</span>
+
+    <div id="shortcutHelp" class="shortcut-overlay" hidden>
+        <div class="shortcut-dialog" role="dialog" aria-labelledby="shortcutTitle">
+            <h2 id="shortcutTitle">Keyboard shortcuts</h2>
+            <table>
+                <tr><td><kbd>←</kbd></td><td>{{if .Direction.HeadIsNewest}}Newer{{else}}Older{{end}} element</td></tr>
+                <tr><td><kbd>→</kbd></td><td>{{if .Direction.HeadIsNewest}}Older{{else}}Newer{{end}} element</td></tr>
+                <tr><td><kbd>Home</kbd></td><td>First element (index 0)</td></tr>
+                <tr><td><kbd>End</kbd></td><td>Last element (index {{.MaxIndex}})</td></tr>
+                <tr><td><kbd>PgUp</kbd> / <kbd>PgDn</kbd></td><td>Back / forward 10 elements</td></tr>
+                <tr><td><kbd>r</kbd></td><td>Random element</td></tr>
+                <tr><td><kbd>/</kbd></td><td>Search; <kbd>Enter</kbd> finds the next match</td></tr>
+                <tr><td><kbd>?</kbd></td><td>Show this help</td></tr>
+                <tr><td><kbd>Esc</kbd></td><td>Close this help</td></tr>
+            </table>
+        </div>
+    </div>
 
     <script>
-        const key = {{.Key}};
+        const keyQuery = {{.KeyQuery}};
         let currentIndex = {{.Index}};
+
+        // Remember this key for the home page's key suggestions, newest first
+        (function() {
+            const storageKey = 'rediscanRecentKeys';
+            const maxRecentKeys = 20;
+            const key = {{.Key}};
+            let recent = [];
+            try {
+                recent = JSON.parse(localStorage.getItem(storageKey) || '[]');
+            } catch (e) {
+                // Unreadable history is replaced
+            }
+            if (!Array.isArray(recent)) {
+                recent = [];
+            }
+            recent = [key].concat(recent.filter(function(name) { return name !== key; }));
+            localStorage.setItem(storageKey, JSON.stringify(recent.slice(0, maxRecentKeys)));
+        })();
         const maxIndex = {{.MaxIndex}};
         const allValues = {{.AllValuesJSON}};
+        const scalarKinds = {{.ScalarKinds}};
+        const binaryValues = {{.Binary}};
+        const wrappedLayers = {{.Wrapped}};
+        const valueCuts = {{.Cuts}};
+        let streamed = null;
+        // Index of allValues[0], when only part of a long list was loaded
+        const offset = {{.Offset}};
+
+        // The loaded value at index, or null if it was not loaded
+        function valueAt(index) {
+            const value = allValues[index - offset];
+            return value === undefined ? null : value;
+        }
+        const wrapModeStorageKey = 'rediscan.wrapMode';
+        let wrapMode = localStorage.getItem(wrapModeStorageKey) || {{.WrapMode}};
+
+        // In stop mode, disable the buttons that would move past either end
+        function updateButtons() {
+            const stop = wrapMode === 'stop';
+            document.getElementById('prevBtn').disabled = stop && currentIndex === 0;
+            document.getElementById('nextBtn').disabled = stop && currentIndex === maxIndex;
+        }
+
+        const wrapModeSelect = document.getElementById('wrapMode');
+        wrapModeSelect.value = wrapMode;
+        wrapModeSelect.addEventListener('change', function(event) {
+            wrapMode = event.target.value;
+            localStorage.setItem(wrapModeStorageKey, wrapMode);
+            updateButtons();
+        });
+        updateButtons();
+
+        const schemaErrors = {{if .Schema}}{{.Schema.Errors}}{{else}}null{{end}};
+
+        // Show whether the element at index conforms to the list's schema
+        function updateSchemaStatus(index) {
+            if (!schemaErrors) {
+                return;
+            }
+            const status = document.getElementById('schemaStatus');
+            if (schemaErrors[index - offset]) {
+                status.className = 'schema-status fail';
+                status.textContent = '✗ Does not conform to schema:\n' + schemaErrors[index - offset];
+            } else {
+                status.className = 'schema-status pass';
+                status.textContent = '✓ Conforms to schema';
+            }
+        }
+        updateSchemaStatus(currentIndex);
+
+        const alerting = new Set({{.Alerting}});
+
+        // Flag the value panel when the element at index matches an alert pattern
+        function updateAlertStatus(index) {
+            document.querySelector('.value-container').classList.toggle('alerting', alerting.has(index));
+        }
+        updateAlertStatus(currentIndex);
+
+        // Show how far the element is from both ends and where else the same
+        // value appears, from LPOS on the server. Lookups wait for navigation
+        // to settle, as each one reads the whole list
+        const duplicatesMaxLength = {{.DuplicatesMax}};
+        const duplicatesDelay = 300;
+        const duplicatesShown = 50;
+        let duplicatesTimer = null;
+        function updateDuplicates() {
+            const index = currentIndex;
+            document.getElementById('positionText').textContent = index + ' from the head, ' + (maxIndex - index) + ' from the tail';
+            const text = document.getElementById('duplicatesText');
+            clearTimeout(duplicatesTimer);
+            if (maxIndex + 1 > duplicatesMaxLength) {
+                text.textContent = 'not checked for lists longer than ' + duplicatesMaxLength + ' elements';
+                return;
+            }
+            text.textContent = 'checking…';
+            duplicatesTimer = setTimeout(function() {
+                fetch('/api/duplicates?key=' + keyQuery + '&index=' + index).then(function(response) {
+                    return response.json();
+                }).then(function(body) {
+                    if (index !== currentIndex) {
+                        return;
+                    }
+                    if (body.error) {
+                        text.textContent = 'could not check: ' + body.error.message;
+                        return;
+                    }
+                    if (body.skipped) {
+                        text.textContent = 'not checked for lists longer than ' + body.max_length + ' elements';
+                        return;
+                    }
+                    if (body.count <= 1) {
+                        text.textContent = 'no other element (unique)';
+                        return;
+                    }
+                    text.textContent = body.count + ' elements: ';
+                    body.indices.slice(0, duplicatesShown).forEach(function(i) {
+                        const link = document.createElement(i === index ? 'strong' : 'a');
+                        link.textContent = i;
+                        if (i !== index) {
+                            link.href = '#';
+                            link.addEventListener('click', function(event) {
+                                event.preventDefault();
+                                updateToIndex(i);
+                            });
+                        }
+                        text.appendChild(link);
+                        text.appendChild(document.createTextNode(' '));
+                    });
+                    if (body.count > duplicatesShown) {
+                        text.appendChild(document.createTextNode('…'));
+                    }
+                }).catch(function(err) {
+                    if (index === currentIndex) {
+                        text.textContent = 'could not check: ' + err;
+                    }
+                });
+            }, duplicatesDelay);
+        }
+        updateDuplicates();
+
+        // Show the stored size of the element, and whether it is over the
+        // MAX_VALUE_BYTES cap on what the page shows in full
+        const valueStats = {{.Stats}};
+        const maxValueBytes = {{.ValueChunk}};
+        function updateValueStats() {
+            const stats = valueStats[currentIndex - offset];
+            let text = '';
+            if (stats) {
+                text = stats.bytes + (stats.bytes === 1 ? ' byte, ' : ' bytes, ') +
+                    stats.chars + (stats.chars === 1 ? ' character, ' : ' characters, ') +
+                    stats.lines + (stats.lines === 1 ? ' line' : ' lines');
+                if (maxValueBytes > 0 && stats.bytes > maxValueBytes) {
+                    text += ' (over MAX_VALUE_BYTES of ' + maxValueBytes + ', so shown cut short)';
+                }
+            }
+            document.getElementById('sizeText').textContent = text;
+        }
+        updateValueStats();
+
+        // The split view shows a value packing many records (log lines, CSV
+        // rows, JSON lines) as a numbered list, one line per item
+        let splitLines = {{.Lines}};
+        // The Markdown view takes precedence over the split view
+        let markdownView = {{.Markdown}};
+        // Tabular values are shown as a table until the raw JSON is asked for
+        let rawTabular = false;
+        // Whether the value shown is a JSON object or array, and whether its
+        // path breadcrumb was fixed by a click rather than following the pointer
+        let valueIsJSON = false;
+        let pathPinned = false;
+        const tabularMaxRows = 1000;
+
+        function showValue(value) {
+            const display = document.getElementById('valueDisplay');
+            const linesDisplay = document.getElementById('linesDisplay');
+            display.textContent = value;
+            display.hidden = splitLines || markdownView;
+            linesDisplay.hidden = !splitLines || markdownView;
+            document.getElementById('markdownDisplay').hidden = !markdownView;
+
+            // Arrays of rows, such as tabular exports, are shown as a table
+            // unless the raw JSON was asked for
+            const table = splitLines || markdownView ? null : tabularRows(value);
+            const toggle = document.getElementById('tabularToggle');
+            toggle.hidden = !table;
+            toggle.textContent = rawTabular ? 'Show as table' : 'Show raw JSON';
+            document.getElementById('tabularDisplay').hidden = !table || rawTabular;
+            if (table) {
+                display.hidden = !rawTabular;
+                if (!rawTabular) {
+                    renderTabular(table);
+                }
+            }
+
+            // Pointing at part of a JSON object or array shows its path
+            valueIsJSON = !display.hidden && isJSONContainer(value);
+            pathPinned = false;
+            document.getElementById('jsonPath').classList.remove('pinned');
+            document.getElementById('jsonPathText').textContent = '$';
+            document.getElementById('jsonPath').hidden = !valueIsJSON;
+            if (!splitLines || markdownView) {
+                return;
+            }
+            const lines = value.split(/\r?\n/);
+            if (lines.length > 1 && lines[lines.length - 1] === '') {
+                lines.pop();
+            }
+            const items = document.createDocumentFragment();
+            lines.forEach(function(line) {
+                const item = document.createElement('li');
+                item.textContent = line;
+                try {
+                    const parsed = JSON.parse(line);
+                    if (parsed !== null && typeof parsed === 'object') {
+                        item.textContent = JSON.stringify(parsed, null, 2);
+                    }
+                } catch (e) {
+                    // Not JSON, so shown as it is
+                }
+                items.appendChild(item);
+            });
+            linesDisplay.replaceChildren(items);
+        }
+
+        document.getElementById('splitLines').addEventListener('change', function(event) {
+            splitLines = event.target.checked;
+            // Keep the choice in the address bar for this view only
+            const params = new URLSearchParams(window.location.search);
+            if (splitLines) {
+                params.set('lines', '1');
+            } else {
+                params.delete('lines');
+            }
+            history.replaceState(null, '', '?' + params.toString());
+            showValue(document.getElementById('valueDisplay').textContent);
+        });
+        if (splitLines) {
+            showValue(document.getElementById('valueDisplay').textContent);
+        }
+
+        // A value that is a JSON array whose items are all arrays, or all
+        // objects without nested objects, is tabular. tabularRows returns its
+        // header (object keys, null for arrays) and cells, or null otherwise
+        function tabularRows(value) {
+            if (value.length > (4 << 20) || !/^\s*\[/.test(value)) {
+                return null;
+            }
+            let items;
+            try {
+                items = JSON.parse(value);
+            } catch (e) {
+                return null;
+            }
+            if (!Array.isArray(items) || items.length === 0) {
+                return null;
+            }
+            if (items.every(Array.isArray)) {
+                if (items.every(function(item) { return item.length === 0; })) {
+                    return null;
+                }
+                return {header: null, rows: items};
+            }
+            const flatObject = function(item) {
+                return item !== null && typeof item === 'object' && !Array.isArray(item) &&
+                    Object.values(item).every(function(v) { return v === null || typeof v !== 'object'; });
+            };
+            if (!items.every(flatObject)) {
+                return null;
+            }
+            const header = [];
+            const seen = new Set();
+            items.forEach(function(item) {
+                Object.keys(item).forEach(function(k) {
+                    if (!seen.has(k)) {
+                        seen.add(k);
+                        header.push(k);
+                    }
+                });
+            });
+            const rows = items.map(function(item) {
+                return header.map(function(k) { return item[k]; });
+            });
+            return {header: header, rows: rows};
+        }
+
+        function renderTabular(table) {
+            const element = document.createElement('table');
+            if (table.header) {
+                const row = element.createTHead().insertRow();
+                row.appendChild(document.createElement('th'));
+                table.header.forEach(function(name) {
+                    const th = document.createElement('th');
+                    th.textContent = name;
+                    row.appendChild(th);
+                });
+            }
+            const body = element.createTBody();
+            table.rows.slice(0, tabularMaxRows).forEach(function(cells, i) {
+                const row = body.insertRow();
+                const number = row.insertCell();
+                number.className = 'row-number';
+                number.textContent = i;
+                cells.forEach(function(cell) {
+                    const td = row.insertCell();
+                    if (cell === undefined) {
+                        return;
+                    }
+                    td.textContent = typeof cell === 'string' ? cell : JSON.stringify(cell);
+                    if (typeof cell !== 'string') {
+                        td.className = 'json-cell';
+                    }
+                });
+            });
+            const panel = document.getElementById('tabularDisplay');
+            panel.replaceChildren(element);
+            if (table.rows.length > tabularMaxRows) {
+                const note = document.createElement('p');
+                note.textContent = 'Showing the first ' + tabularMaxRows + ' of ' + table.rows.length + ' rows.';
+                panel.appendChild(note);
+            }
+        }
+
+        document.getElementById('tabularToggle').addEventListener('click', function(event) {
+            event.preventDefault();
+            rawTabular = !rawTabular;
+            showValue(document.getElementById('valueDisplay').textContent);
+        });
+        showValue(document.getElementById('valueDisplay').textContent);
+
+        function isJSONContainer(value) {
+            if (value.length > (2 << 20) || !/^\s*[\[{]/.test(value)) {
+                return false;
+            }
+            try {
+                JSON.parse(value);
+                return true;
+            } catch (e) {
+                return false;
+            }
+        }
+
+        // jsonPathAt returns the JSONPath (e.g. $.orders[2].items[0].sku) of
+        // the node at offset in the JSON text, by scanning the text up to it
+        // and tracking the key or index of every enclosing object and array
+        function jsonPathAt(text, offset) {
+            const stack = [];
+            let i = 0;
+            while (i < offset && i < text.length) {
+                const c = text[i];
+                const top = stack[stack.length - 1];
+                if (c === '"') {
+                    let end = i + 1;
+                    while (end < text.length && text[end] !== '"') {
+                        end += text[end] === '\\' ? 2 : 1;
+                    }
+                    // A string followed by a colon is an object key
+                    let next = end + 1;
+                    while (next < text.length && /\s/.test(text[next])) {
+                        next++;
+                    }
+                    if (top && top.type === 'object' && text[next] === ':') {
+                        top.key = JSON.parse(text.slice(i, end + 1));
+                    }
+                    i = end + 1;
+                    continue;
+                }
+                if (c === '{') {
+                    stack.push({type: 'object', key: null});
+                } else if (c === '[') {
+                    stack.push({type: 'array', index: 0});
+                } else if (c === '}' || c === ']') {
+                    stack.pop();
+                } else if (c === ',' && top) {
+                    if (top.type === 'array') {
+                        top.index++;
+                    } else {
+                        top.key = null;
+                    }
+                }
+                i++;
+            }
+            // A closing bracket belongs to its object or array, not the last member
+            if (text[offset] === '}' || text[offset] === ']') {
+                stack.pop();
+            }
+            let path = '$';
+            for (const entry of stack) {
+                if (entry.type === 'array') {
+                    path += '[' + entry.index + ']';
+                } else if (entry.key === null) {
+                    break;
+                } else {
+                    path += jsonPathKey(entry.key);
+                }
+            }
+            return path;
+        }
+
+        // The JSONPath step selecting key in an object
+        function jsonPathKey(key) {
+            if (/^[A-Za-z_$][A-Za-z0-9_$]*$/.test(key)) {
+                return '.' + key;
+            }
+            return "['" + key.replace(/\\/g, '\\\\').replace(/'/g, "\\'") + "']";
+        }
+
+        // The offset in the value's text of the character under the pointer
+        function valueOffsetAt(event) {
+            const display = document.getElementById('valueDisplay');
+            let node, offset;
+            if (document.caretPositionFromPoint) {
+                const position = document.caretPositionFromPoint(event.clientX, event.clientY);
+                if (!position) {
+                    return -1;
+                }
+                node = position.offsetNode;
+                offset = position.offset;
+            } else if (document.caretRangeFromPoint) {
+                const range = document.caretRangeFromPoint(event.clientX, event.clientY);
+                if (!range) {
+                    return -1;
+                }
+                node = range.startContainer;
+                offset = range.startOffset;
+            } else {
+                return -1;
+            }
+            if (node.parentNode !== display) {
+                return -1;
+            }
+            for (let sibling = node.previousSibling; sibling; sibling = sibling.previousSibling) {
+                offset += sibling.textContent.length;
+            }
+            return offset;
+        }
+
+        function showPathAt(event) {
+            if (!valueIsJSON) {
+                return;
+            }
+            const offset = valueOffsetAt(event);
+            if (offset >= 0) {
+                document.getElementById('jsonPathText').textContent =
+                    jsonPathAt(document.getElementById('valueDisplay').textContent, offset);
+            }
+        }
+
+        let pathFrame = 0;
+        document.getElementById('valueDisplay').addEventListener('mousemove', function(event) {
+            if (pathPinned || pathFrame) {
+                return;
+            }
+            pathFrame = requestAnimationFrame(function() {
+                pathFrame = 0;
+                showPathAt(event);
+            });
+        });
+        document.getElementById('valueDisplay').addEventListener('click', function(event) {
+            if (!valueIsJSON || window.getSelection().toString()) {
+                return;
+            }
+            pathPinned = !pathPinned;
+            if (pathPinned) {
+                showPathAt(event);
+            }
+            document.getElementById('jsonPath').classList.toggle('pinned', pathPinned);
+        });
+        // Copy the whole list, when every element is on the page in full
+        document.getElementById('copyAllBtn').addEventListener('click', function() {
+            const button = this;
+            const asLines = document.getElementById('copyAllFormat').value === 'lines';
+            const text = asLines ? allValues.join('\n') : JSON.stringify(allValues, null, 2);
+            navigator.clipboard.writeText(text).then(function() {
+                button.textContent = 'Copied ' + allValues.length;
+                setTimeout(function() { button.textContent = 'Copy all'; }, 1500);
+            }, function() {
+                button.textContent = 'Copy failed';
+            });
+        });
+        document.getElementById('copyPathBtn').addEventListener('click', function() {
+            const button = this;
+            navigator.clipboard.writeText(document.getElementById('jsonPathText').textContent).then(function() {
+                button.textContent = 'Copied';
+                setTimeout(function() { button.textContent = 'Copy path'; }, 1500);
+            }, function() {
+                button.textContent = 'Copy failed';
+            });
+        });
+
+        // The server renders Markdown, escaping raw HTML and dropping unsafe
+        // links, since values are untrusted
+        function updateMarkdown() {
+            if (!markdownView) {
+                return;
+            }
+            const panel = document.getElementById('markdownDisplay');
+            const index = currentIndex;
+            const params = new URLSearchParams(window.location.search);
+            let url = '/api/markdown?key=' + keyQuery + '&index=' + index;
+            if (params.get('transform')) {
+                url += '&transform=' + encodeURIComponent(params.get('transform'));
+            }
+            fetch(url).then(function(response) {
+                return response.json();
+            }).then(function(body) {
+                if (index !== currentIndex || !markdownView) {
+                    return;
+                }
+                if (body.error) {
+                    panel.textContent = 'Rendering failed: ' + body.error.message;
+                    return;
+                }
+                panel.innerHTML = body.html;
+            }).catch(function(err) {
+                panel.textContent = 'Rendering failed: ' + err;
+            });
+        }
+
+        document.getElementById('markdownView').addEventListener('change', function(event) {
+            markdownView = event.target.checked;
+            const params = new URLSearchParams(window.location.search);
+            if (markdownView) {
+                params.set('md', '1');
+            } else {
+                params.delete('md');
+            }
+            history.replaceState(null, '', '?' + params.toString());
+            showValue(document.getElementById('valueDisplay').textContent);
+            updateMarkdown();
+        });
+        if (markdownView) {
+            showValue(document.getElementById('valueDisplay').textContent);
+            updateMarkdown();
+        }
+
+        // A tracked element is kept in the URL by its content hash, so a
+        // refresh asks the server to find it again wherever it has moved
+        const hashes = {{.Hashes}};
+
+        function updateTrackedElement() {
+            if (!hashes || !document.getElementById('trackElement').checked) {
+                return;
+            }
+            const params = new URLSearchParams(window.location.search);
+            params.set('index', currentIndex);
+            params.delete('newest');
+            params.set('track', hashes[currentIndex - offset]);
+            history.replaceState(null, '', '?' + params.toString());
+        }
+
+        document.getElementById('trackElement').addEventListener('change', function(event) {
+            const params = new URLSearchParams(window.location.search);
+            if (event.target.checked && !hashes) {
+                // Reload so the server supplies the elements' hashes
+                params.set('index', currentIndex);
+                params.delete('newest');
+                params.set('track', '1');
+                window.location.search = params.toString();
+                return;
+            }
+            if (event.target.checked) {
+                updateTrackedElement();
+            } else {
+                params.delete('track');
+                history.replaceState(null, '', '?' + params.toString());
+            }
+        });
+        updateTrackedElement();
 
         // Helper function to update the UI to show a specific index
         function updateToIndex(newIndex) {
+            // Values that were too large to preload, or outside the loaded
+            // part of the list, are fetched by reloading
+            if (valueAt(newIndex) === null) {
+                const params = new URLSearchParams(window.location.search);
+                params.set('index', newIndex);
+                params.delete('newest');
+                if (params.has('track')) {
+                    // Track the newly chosen element, not the one left behind
+                    params.set('track', '1');
+                }
+                window.location.search = params.toString();
+                return;
+            }
+
             // Update the display with the preloaded value
-            document.getElementById('valueDisplay').textContent = allValues[newIndex];
+            showValue(valueAt(newIndex));
             
             // Update the metadata
             document.querySelector('.navigation .info').textContent = newIndex + ' / ' + maxIndex;
@@ -573,72 +3158,796 @@ func renderResultWithPreload(w http.ResponseWriter, key string, index int64, lle
             document.getElementById('positionSlider').value = newIndex;
             document.getElementById('sliderLabel').textContent = newIndex + ' / ' + maxIndex;
             
+            updateSchemaStatus(newIndex);
+            updateAlertStatus(newIndex);
+            document.getElementById('scalarHint').textContent = scalarKinds[newIndex - offset] ? 'scalar: ' + scalarKinds[newIndex - offset] : '';
+            document.getElementById('binaryHint').hidden = !binaryValues[newIndex - offset];
+            const layers = wrappedLayers[newIndex - offset];
+            document.getElementById('wrappedHint').hidden = !layers;
+            document.getElementById('wrappedLayers').textContent = layers > 1 ? ', ' + layers + ' layers deep' : '';
+
+            // A diagnosis describes the element it was run on
+            document.getElementById('diagnosis').hidden = true;
+
             // Update the current index for next navigation
             currentIndex = newIndex;
+            updateButtons();
+            updateDuplicates();
+            updateValueStats();
+            renderTableRows();
+            updateMarkdown();
+            updateTrackedElement();
+            updateValueCut();
+        }
+
+        // Elements over MAX_VALUE_BYTES arrive cut short. The rest is streamed
+        // from the server on request and appended as it arrives
+        function updateValueCut() {
+            const cut = valueCuts ? valueCuts[currentIndex - offset] : null;
+            const panel = document.getElementById('valueCut');
+            if (!cut) {
+                panel.hidden = true;
+                streamed = null;
+                return;
+            }
+            if (!streamed || streamed.index !== currentIndex) {
+                streamed = {index: currentIndex, shown: cut.shown, size: cut.size, text: valueAt(currentIndex), decoder: new TextDecoder()};
+                document.getElementById('valueCutError').textContent = '';
+            }
+            panel.hidden = streamed.shown >= streamed.size;
+            document.getElementById('valueCutShown').textContent = streamed.shown;
+            document.getElementById('valueCutSize').textContent = streamed.size;
         }
 
+        function loadValue(all) {
+            const state = streamed;
+            const buttons = [document.getElementById('loadMoreBtn'), document.getElementById('loadAllBtn')];
+            let url = '/api/value-stream?key=' + keyQuery + '&index=' + state.index + '&offset=' + state.shown;
+            if (!all) {
+                url += '&length=' + {{.ValueChunk}};
+            }
+            buttons.forEach(function(button) { button.disabled = true; });
+            fetch(url).then(function(response) {
+                if (!response.ok) {
+                    return response.json().then(function(body) {
+                        throw new Error(body.error.message);
+                    });
+                }
+                const reader = response.body.getReader();
+                function read() {
+                    return reader.read().then(function(result) {
+                        if (state !== streamed) {
+                            // Navigated to another element meanwhile
+                            reader.cancel();
+                            return;
+                        }
+                        if (result.done) {
+                            if (state.shown >= state.size) {
+                                state.text += state.decoder.decode();
+                            }
+                            // Split lines are rebuilt once rather than per chunk
+                            showValue(state.text);
+                            updateValueCut();
+                            return;
+                        }
+                        // Characters split across chunks are held back by the decoder
+                        const text = state.decoder.decode(result.value, {stream: true});
+                        state.shown += result.value.length;
+                        state.text += text;
+                        document.getElementById('valueDisplay').append(text);
+                        updateValueCut();
+                        return read();
+                    });
+                }
+                return read();
+            }).catch(function(err) {
+                document.getElementById('valueCutError').textContent = 'Loading failed: ' + err.message;
+            }).finally(function() {
+                buttons.forEach(function(button) { button.disabled = false; });
+            });
+        }
+        document.getElementById('loadMoreBtn').addEventListener('click', function() {
+            loadValue(false);
+        });
+        document.getElementById('loadAllBtn').addEventListener('click', function() {
+            loadValue(true);
+        });
+        updateValueCut();
+
+        // Table view: only the rows scrolled into view (plus a small margin)
+        // are kept in the DOM, so lists with thousands of elements stay responsive
+        const tableRowHeight = 28;
+        const tableOverscan = 10;
+        const tableViewport = document.getElementById('tableViewport');
+        const tableSpacer = document.getElementById('tableSpacer');
+        tableSpacer.style.height = (allValues.length * tableRowHeight) + 'px';
+
+        const summaries = {{.Summaries}};
+
+        // A row shows the element's configured summary, falling back to the
+        // start of its value
+        function rowPreview(i) {
+            if (summaries && summaries[i - offset]) {
+                return summaries[i - offset];
+            }
+            const value = valueAt(i);
+            return value === null ? '…' : value.slice(0, 200).replace(/\s+/g, ' ');
+        }
+
+        function renderTableRows() {
+            if (!document.getElementById('tableView').open) {
+                return;
+            }
+            // Rows cover the loaded elements, from offset
+            const first = Math.max(0, Math.floor(tableViewport.scrollTop / tableRowHeight) - tableOverscan) + offset;
+            const last = Math.min(allValues.length - 1,
+                Math.ceil((tableViewport.scrollTop + tableViewport.clientHeight) / tableRowHeight) + tableOverscan) + offset;
+
+            const rows = document.createDocumentFragment();
+            rows.appendChild(tableSpacer);
+            for (let i = first; i <= last; i++) {
+                const row = document.createElement('div');
+                row.className = i === currentIndex ? 'table-row current' : 'table-row';
+                if (alerting.has(i)) {
+                    row.classList.add('alerting');
+                }
+                row.style.top = ((i - offset) * tableRowHeight) + 'px';
+                row.dataset.index = i;
+
+                const index = document.createElement('span');
+                index.className = 'row-index';
+                index.textContent = i;
+                row.appendChild(index);
+                row.appendChild(document.createTextNode(rowPreview(i)));
+                rows.appendChild(row);
+            }
+            tableViewport.replaceChildren(rows);
+        }
+
+        tableViewport.addEventListener('scroll', renderTableRows);
+        tableViewport.addEventListener('click', function(event) {
+            const row = event.target.closest('.table-row');
+            if (row) {
+                updateToIndex(parseInt(row.dataset.index));
+            }
+        });
+        document.getElementById('tableView').addEventListener('toggle', function() {
+            // Bring the current element into view when the table is opened
+            if (this.open) {
+                tableViewport.scrollTop = Math.max(0, (currentIndex - offset - 5) * tableRowHeight);
+                renderTableRows();
+            }
+        });
+
         function navigate(delta) {
             let newIndex = currentIndex + delta;
             // Check for wrap around
             if (newIndex < 0) {
-                // Wrapping backwards (older than oldest): reload to get fresh data and show newest
-                window.location.href = '/lindex?key=' + encodeURIComponent(key);
-                return;
+                if (wrapMode === 'reload') {
+                    // Wrapping backwards (older than oldest): reload to get fresh data and show newest
+                    window.location.href = '{{lindexPath}}?key=' + keyQuery;
+                    return;
+                } else if (wrapMode === 'wrap') {
+                    // Wrap to newest using the data already loaded
+                    newIndex = maxIndex;
+                } else {
+                    return;
+                }
             } else if (newIndex > maxIndex) {
+                if (wrapMode === 'stop') {
+                    return;
+                }
                 // Wrapping forwards (newer than newest): wrap to oldest
                 newIndex = 0;
             }
-            
+
+
             updateToIndex(newIndex);
         }
 
-        // Handle slider changes
-        document.getElementById('positionSlider').addEventListener('input', function(event) {
-            const newIndex = parseInt(event.target.value);
+        // Select the first element at or after from (wrapping around) whose
+        // value contains the search text, ignoring case
+        function searchFrom(from) {
+            const term = document.getElementById('searchInput').value;
+            const status = document.getElementById('searchStatus');
+
+            // Keep the search in the address bar so the page can be shared
+            const params = new URLSearchParams(window.location.search);
+            if (term) {
+                params.set('search', term);
+            } else {
+                params.delete('search');
+            }
+            history.replaceState(null, '', '?' + params.toString());
+
+            if (!term) {
+                status.textContent = '';
+                return;
+            }
+            const needle = term.toLowerCase();
+            let matches = 0;
+            let found = -1;
+            // Only the loaded elements are searched, starting over from the
+            // first of them when from is outside
+            const start = from - offset >= 0 && from - offset < allValues.length ? from - offset : 0;
+            for (let i = 0; i < allValues.length; i++) {
+                if (allValues[i] !== null && allValues[i].toLowerCase().includes(needle)) {
+                    matches++;
+                    const distance = (i - start + allValues.length) % allValues.length;
+                    if (found === -1 || distance < (found - start + allValues.length) % allValues.length) {
+                        found = i;
+                    }
+                }
+            }
+            if (found === -1) {
+                status.textContent = 'No matches';
+                return;
+            }
+            status.textContent = matches + ' match' + (matches === 1 ? '' : 'es');
+            updateToIndex(found + offset);
+        }
+
+        function searchNext() {
+            searchFrom(currentIndex + 1);
+        }
+
+        document.getElementById('searchInput').addEventListener('keydown', function(event) {
+            if (event.key === 'Enter') {
+                searchNext();
+            }
+        });
+
+        // A search in the URL selects its first match when the page loads
+        if ({{.Search}}) {
+            searchFrom(0);
+        }
+
+        // Snapshots keep the loaded elements in localStorage so a later load of
+        // the list can be compared with them. The storage name lacks the
+        // rediscan. prefix so snapshots are not synced as preferences
+        const snapshotStorageKey = 'rediscanSnapshot:' + {{.Key}};
+        const snapshotMaxLength = 1 << 20;
+        const snapshotDiffLimit = 200;
+
+        function loadSnapshot() {
+            try {
+                return JSON.parse(localStorage.getItem(snapshotStorageKey));
+            } catch (e) {
+                return null;
+            }
+        }
+
+        function updateSnapshotStatus() {
+            const status = document.getElementById('snapshotStatus');
+            if (!status) {
+                return;
+            }
+            const snapshot = loadSnapshot();
+            document.getElementById('snapshotDiffBtn').disabled = !snapshot;
+            status.textContent = snapshot ? 'Snapshot of ' + snapshot.values.length + ' elements taken ' + new Date(snapshot.taken).toLocaleString() : 'No snapshot yet';
+        }
+
+        function takeSnapshot() {
+            const snapshot = JSON.stringify({taken: Date.now(), offset: offset, values: allValues});
+            if (snapshot.length > snapshotMaxLength) {
+                document.getElementById('snapshotStatus').textContent = 'The list is too large to snapshot (' + snapshot.length + ' characters, limit ' + snapshotMaxLength + ')';
+                return;
+            }
+            try {
+                localStorage.setItem(snapshotStorageKey, snapshot);
+            } catch (e) {
+                document.getElementById('snapshotStatus').textContent = 'Could not store the snapshot: ' + e.message;
+                return;
+            }
+            document.getElementById('snapshotDiff').hidden = true;
+            updateSnapshotStatus();
+        }
+
+        // Elements are matched by content, so those that merely moved as the
+        // list was consumed or appended to are unchanged. An element added at
+        // the same index one was removed from is reported as changed
+        function diffSnapshot() {
+            const snapshot = loadSnapshot();
+            if (!snapshot) {
+                return;
+            }
+            const remaining = new Map();
+            snapshot.values.forEach(function(value, i) {
+                const indices = remaining.get(value) || [];
+                indices.push(i + snapshot.offset);
+                remaining.set(value, indices);
+            });
+            const added = new Map();
+            allValues.forEach(function(value, i) {
+                const indices = remaining.get(value);
+                if (indices && indices.length) {
+                    indices.shift();
+                } else {
+                    added.set(i + offset, value);
+                }
+            });
+            const removed = new Map();
+            remaining.forEach(function(indices, value) {
+                indices.forEach(function(index) {
+                    removed.set(index, value);
+                });
+            });
+
+            const entries = [];
+            added.forEach(function(value, index) {
+                if (removed.has(index)) {
+                    entries.push({kind: 'changed', index: index, value: value});
+                    removed.delete(index);
+                } else {
+                    entries.push({kind: 'added', index: index, value: value});
+                }
+            });
+            removed.forEach(function(value, index) {
+                entries.push({kind: 'removed', index: index, value: value});
+            });
+            entries.sort(function(a, b) {
+                return a.index - b.index;
+            });
+
+            const panel = document.getElementById('snapshotDiff');
+            const counts = {added: 0, removed: 0, changed: 0};
+            entries.forEach(function(entry) {
+                counts[entry.kind]++;
+            });
+            const lines = document.createDocumentFragment();
+            const summary = document.createElement('p');
+            summary.textContent = entries.length === 0 ? 'No changes since the snapshot.' :
+                counts.added + ' added, ' + counts.removed + ' removed, ' + counts.changed + ' changed since the snapshot' +
+                (entries.length > snapshotDiffLimit ? ' (showing the first ' + snapshotDiffLimit + ')' : '') + '.';
+            lines.appendChild(summary);
+            const marks = {added: '+', removed: '−', changed: '~'};
+            entries.slice(0, snapshotDiffLimit).forEach(function(entry) {
+                const line = document.createElement('div');
+                line.className = entry.kind;
+                const preview = ' ' + entry.value.slice(0, 200).replace(/\s+/g, ' ');
+                if (entry.kind === 'removed') {
+                    line.textContent = marks[entry.kind] + ' [' + entry.index + '] (was)' + preview;
+                } else {
+                    const link = document.createElement('a');
+                    link.href = '#';
+                    link.textContent = '[' + entry.index + ']';
+                    link.addEventListener('click', function(event) {
+                        event.preventDefault();
+                        updateToIndex(entry.index);
+                    });
+                    line.append(marks[entry.kind] + ' ', link, preview);
+                }
+                lines.appendChild(line);
+            });
+            panel.replaceChildren(lines);
+            panel.hidden = false;
+        }
+        updateSnapshotStatus();
+
+        // The changes view walks the loaded elements in index order and
+        // compares each one's JSON with the previous element's
+        const changesLimit = 500;
+
+        function parseJSONContainer(value) {
+            if (value === null || !/^\s*[\[{]/.test(value)) {
+                return undefined;
+            }
+            try {
+                return JSON.parse(value);
+            } catch (e) {
+                return undefined;
+            }
+        }
+
+        function isContainer(value) {
+            return value !== null && typeof value === 'object';
+        }
+
+        // diffJSON appends {path, before, after} for every leaf that differs
+        // between before and after, descending while both sides are objects
+        // or both are arrays. A missing side is undefined
+        function diffJSON(before, after, path, changes) {
+            if (isContainer(before) && isContainer(after) && Array.isArray(before) === Array.isArray(after)) {
+                const keys = Array.isArray(after) ?
+                    Array.from({length: Math.max(before.length, after.length)}, function(_, i) { return i; }) :
+                    Array.from(new Set(Object.keys(before).concat(Object.keys(after))));
+                keys.forEach(function(key) {
+                    const step = typeof key === 'number' ? '[' + key + ']' : jsonPathKey(key);
+                    diffJSON(before[key], after[key], path + step, changes);
+                });
+            } else if (JSON.stringify(before) !== JSON.stringify(after)) {
+                changes.push({path: path, before: before, after: after});
+            }
+        }
+
+        function describeChange(change) {
+            if (change.before === undefined) {
+                return change.path + ': added ' + JSON.stringify(change.after);
+            }
+            if (change.after === undefined) {
+                return change.path + ': removed (was ' + JSON.stringify(change.before) + ')';
+            }
+            return change.path + ': ' + JSON.stringify(change.before) + ' → ' + JSON.stringify(change.after);
+        }
+
+        function renderChanges() {
+            const list = document.getElementById('changesList');
+            if (!list) {
+                return;
+            }
+            const entries = document.createDocumentFragment();
+            let previous;
+            allValues.slice(0, changesLimit).forEach(function(value, i) {
+                const index = i + offset;
+                const parsed = parseJSONContainer(value);
+                const entry = document.createElement('div');
+                const link = document.createElement('a');
+                link.href = '#';
+                link.textContent = '[' + index + ']';
+                link.addEventListener('click', function(event) {
+                    event.preventDefault();
+                    updateToIndex(index);
+                });
+                entry.appendChild(link);
+
+                const comparable = parsed !== undefined && previous !== undefined &&
+                    Array.isArray(parsed) === Array.isArray(previous);
+                if (!comparable) {
+                    // Shown in full: the first element, non-JSON values and
+                    // those whose structure differs from the previous one
+                    entry.append(i === 0 ? ' first element' : ' not comparable, shown in full');
+                    const full = document.createElement('pre');
+                    full.textContent = value;
+                    entry.appendChild(full);
+                } else {
+                    const changes = [];
+                    diffJSON(previous, parsed, '$', changes);
+                    entry.append(changes.length === 0 ? ' no changes' :
+                        ' ' + changes.length + (changes.length === 1 ? ' field changed' : ' fields changed'));
+                    changes.forEach(function(change) {
+                        const line = document.createElement('div');
+                        line.className = 'change';
+                        line.textContent = describeChange(change);
+                        entry.appendChild(line);
+                    });
+                }
+                entries.appendChild(entry);
+                previous = parsed;
+            });
+            if (allValues.length > changesLimit) {
+                const note = document.createElement('p');
+                note.className = 'hint';
+                note.textContent = 'Showing the first ' + changesLimit + ' of ' + allValues.length + ' loaded elements.';
+                entries.appendChild(note);
+            }
+            list.replaceChildren(entries);
+        }
+
+        document.getElementById('changesView').addEventListener('toggle', function() {
+            // Built on first open, as the loaded elements do not change
+            const list = document.getElementById('changesList');
+            if (this.open && list && !list.hasChildNodes()) {
+                renderChanges();
+            }
+        });
+
+        // Show what the stored (untransformed) value appears to be, with a
+        // decoding pipeline to apply when one was found
+        function diagnose() {
+            const panel = document.getElementById('diagnosis');
+            const index = currentIndex;
+            panel.hidden = false;
+            panel.textContent = 'Diagnosing…';
+            fetch('/api/diagnose?key=' + keyQuery + '&index=' + index).then(function(response) {
+                return response.json();
+            }).then(function(body) {
+                if (index !== currentIndex) {
+                    return;
+                }
+                if (body.error) {
+                    panel.textContent = 'Diagnosis failed: ' + body.error.message;
+                    return;
+                }
+                const d = body.diagnosis;
+                const table = document.createElement('table');
+                const addRow = function(mark, className, name, detail) {
+                    const row = table.insertRow();
+                    const markCell = row.insertCell();
+                    markCell.textContent = mark;
+                    markCell.className = className;
+                    row.insertCell().textContent = name;
+                    row.insertCell().textContent = detail;
+                };
+                addRow('', '', 'Length', d.bytes + ' bytes');
+                d.checks.forEach(function(check) {
+                    addRow(check.ok ? '✓' : '✗', check.ok ? 'pass' : 'fail', check.name, check.detail);
+                });
+                const suggestion = document.createElement('p');
+                // A pipeline configured for the key runs before view stages,
+                // so a suggestion for the raw value cannot simply be added
+                if (d.suggested.length > 0 && !{{.Configured}}) {
+                    suggestion.textContent = 'Suggested decoding: ' + d.suggested.join(' → ') + ' ';
+                    const apply = document.createElement('button');
+                    apply.type = 'button';
+                    apply.textContent = 'Apply';
+                    apply.addEventListener('click', function() {
+                        const params = new URLSearchParams(window.location.search);
+                        params.set('transform', d.suggested.join(','));
+                        params.set('index', index);
+                        window.location.search = params.toString();
+                    });
+                    suggestion.appendChild(apply);
+                } else if (d.suggested.length > 0) {
+                    suggestion.textContent = 'Suggested decoding of the stored value: ' + d.suggested.join(' → ') + ' (VALUE_TRANSFORMS already decodes this key)';
+                } else {
+                    suggestion.textContent = 'No decoding pipeline suggested.';
+                }
+                panel.replaceChildren(table, suggestion);
+            }).catch(function(err) {
+                panel.textContent = 'Diagnosis failed: ' + err;
+            });
+        }
+
+        // Reload with every element of the list, keeping the current position
+        function loadFullList() {
+            const params = new URLSearchParams(window.location.search);
+            params.set('full', '1');
+            params.set('index', currentIndex);
+            params.delete('newest');
+            window.location.search = params.toString();
+        }
+
+        // Reload with another decoding stage applied to every element, keeping
+        // the current position
+        function addViewTransform() {
+            const params = new URLSearchParams(window.location.search);
+            const stages = params.get('transform') ? params.get('transform').split(',') : [];
+            stages.push(document.getElementById('viewTransform').value);
+            params.set('transform', stages.join(','));
+            params.set('index', currentIndex);
+            window.location.search = params.toString();
+        }
+
+        function viewAsHexdump() {
+            document.getElementById('viewTransform').value = 'hexdump';
+            addViewTransform();
+        }
+
+        // Peel every layer of string encoding around JSON stored as a string
+        function unwrapValue() {
+            document.getElementById('viewTransform').value = 'unwrap';
+            addViewTransform();
+        }
+
+        function clearViewTransforms() {
+            const params = new URLSearchParams(window.location.search);
+            params.delete('transform');
+            params.set('index', currentIndex);
+            window.location.search = params.toString();
+        }
+
+        // Show a random element other than the current one, for spot-checking
+        function jumpToRandom() {
+            if (maxIndex === 0) {
+                return;
+            }
+            let newIndex = Math.floor(Math.random() * maxIndex);
+            if (newIndex >= currentIndex) {
+                newIndex++;
+            }
             updateToIndex(newIndex);
+        }
+
+        // Open the count-by-field view for this list
+        function groupByField() {
+            const field = document.getElementById('aggregateField').value.trim();
+            if (field) {
+                window.location.href = '/aggregate?key=' + keyQuery + '&field=' + encodeURIComponent(field);
+            }
+        }
+
+        // Jump to the Nth element counting back from the newest (1 = newest)
+        function jumpFromNewest() {
+            const n = parseInt(document.getElementById('newestInput').value);
+            if (isNaN(n) || n < 1 || n > maxIndex + 1) {
+                return;
+            }
+            updateToIndex({{if .Direction.HeadIsNewest}}n - 1{{else}}maxIndex + 1 - n{{end}});
+        }
+
+        document.getElementById('newestInput').addEventListener('keydown', function(event) {
+            if (event.key === 'Enter') {
+                jumpFromNewest();
+            }
         });
 
-        // Handle keyboard navigation
+        // Dragging the slider shows each loaded element as it passes. Elements
+        // that have to be fetched are only loaded once the slider comes to
+        // rest, so dragging across them does not reload the page at each step
+        const sliderSettleDelay = 400;
+        let sliderTimer = null;
+        const positionSlider = document.getElementById('positionSlider');
+        positionSlider.addEventListener('input', function() {
+            const newIndex = parseInt(positionSlider.value);
+            clearTimeout(sliderTimer);
+            if (valueAt(newIndex) !== null) {
+                updateToIndex(newIndex);
+                return;
+            }
+            document.querySelector('.navigation .info').textContent = newIndex + ' / ' + maxIndex;
+            document.getElementById('sliderLabel').textContent = newIndex + ' / ' + maxIndex + ' (release to load)';
+            sliderTimer = setTimeout(function() {
+                updateToIndex(newIndex);
+            }, sliderSettleDelay);
+        });
+        positionSlider.addEventListener('change', function() {
+            const newIndex = parseInt(positionSlider.value);
+            if (valueAt(newIndex) === null) {
+                clearTimeout(sliderTimer);
+                updateToIndex(newIndex);
+            }
+        });
+
+        // Elements moved by PageUp and PageDown
+        const shortcutPageSize = 10;
+
+        // Handle keyboard navigation; press ? for the list of shortcuts
         document.addEventListener('keydown', function(event) {
-            if (event.key === 'ArrowLeft' || event.key === 'Left') {
-                event.preventDefault();
+            // Leave arrow keys to form fields (other than the slider) while typing
+            const tag = event.target.tagName;
+            if ((tag === 'INPUT' || tag === 'SELECT' || tag === 'TEXTAREA') && event.target.type !== 'range') {
+                return;
+            }
+            const help = document.getElementById('shortcutHelp');
+            if (!help.hidden) {
+                if (event.key === 'Escape' || event.key === '?') {
+                    event.preventDefault();
+                    help.hidden = true;
+                }
+                return;
+            }
+            if (event.ctrlKey || event.metaKey || event.altKey) {
+                return;
+            }
+            switch (event.key) {
+            case 'ArrowLeft':
+            case 'Left':
                 navigate(-1);
-            } else if (event.key === 'ArrowRight' || event.key === 'Right') {
-                event.preventDefault();
+                break;
+            case 'ArrowRight':
+            case 'Right':
                 navigate(1);
+                break;
+            case 'Home':
+                updateToIndex(0);
+                break;
+            case 'End':
+                updateToIndex(maxIndex);
+                break;
+            case 'PageUp':
+                updateToIndex(Math.max(0, currentIndex - shortcutPageSize));
+                break;
+            case 'PageDown':
+                updateToIndex(Math.min(maxIndex, currentIndex + shortcutPageSize));
+                break;
+            case 'r':
+                jumpToRandom();
+                break;
+            case '/':
+                document.getElementById('searchInput').focus();
+                break;
+            case '?':
+                help.hidden = false;
+                break;
+            default:
+                return;
+            }
+            event.preventDefault();
+        });
+
+        // Close the shortcut help when clicking outside it
+        document.getElementById('shortcutHelp').addEventListener('click', function(event) {
+            if (event.target === this) {
+                this.hidden = true;
             }
         });
     </script>
 </body>
 </html>`
 
-	tmpl, err := template.New("result").Parse(tmplStr)
+	tmpl, err := parseTemplate("result", tmplStr)
 	if err != nil {
 		renderError(w, fmt.Sprintf("Template error: %v", err))
 		return
 	}
 
-	// Convert allValues to JSON for embedding in JavaScript
-	allValuesJSON, err := json.Marshal(allValues)
+	// Convert the values to JSON for embedding in JavaScript
+	allValuesJSON, err := json.Marshal(page.Values)
 	if err != nil {
 		renderError(w, fmt.Sprintf("Error encoding values: %v", err))
 		return
 	}
 
 	data := struct {
-		Key           string
-		Index         int64
-		LLen          int64
-		MaxIndex      int64
-		AllValues     []string
-		AllValuesJSON template.JS
+		Key            string
+		KeyQuery       string
+		Index          int64
+		LLen           int64
+		MaxIndex       int64
+		Offset         int64
+		Position       int64
+		Partial        bool
+		LastLoaded     int64
+		AllValues      []string
+		AllValuesJSON  template.JS
+		Preloaded      bool
+		ScalarKinds    []string
+		Binary         []bool
+		Wrapped        []int
+		Unwrapped      bool
+		Stats          []valueStats
+		WriteEnabled   bool
+		Transforms     []string
+		TransformList  []string
+		Configured     bool
+		DuplicatesMax  int64
+		Schema         *inspector.SchemaReport
+		Alerting       []int64
+		Summaries      []string
+		WrapMode       string
+		Direction      inspector.PushDirection
+		Search         string
+		Lines          bool
+		Markdown       bool
+		Tracking       bool
+		TrackNotice    string
+		Hashes         []string
+		Access         string
+		Encoding       string
+		Memory         string
+		Cuts           []*valueCut
+		ValueChunk     int
+		CopyAllBlocked string
 	}{
-		Key:           key,
-		Index:         index,
-		LLen:          llen,
-		MaxIndex:      llen - 1,
-		AllValues:     allValues,
-		AllValuesJSON: template.JS(allValuesJSON),
+		Key:            page.Key,
+		KeyQuery:       url.QueryEscape(page.Key),
+		Index:          page.Index,
+		LLen:           page.LLen,
+		MaxIndex:       page.LLen - 1,
+		Offset:         page.Offset,
+		Position:       page.Index - page.Offset,
+		Partial:        int64(len(page.Values)) < page.LLen,
+		LastLoaded:     page.Offset + int64(len(page.Values)) - 1,
+		AllValues:      page.Values,
+		AllValuesJSON:  template.JS(allValuesJSON),
+		Preloaded:      !page.Budgeted,
+		ScalarKinds:    page.ScalarKinds,
+		Binary:         page.Binary,
+		Wrapped:        page.Wrapped,
+		Stats:          page.Stats,
+		Unwrapped:      slices.Contains(page.Transforms, "unwrap"),
+		WriteEnabled:   writeEnabled,
+		Transforms:     page.Transforms,
+		TransformList:  transformNames(),
+		Configured:     inspector.MatchTransforms(transformRules, page.Key) != nil,
+		DuplicatesMax:  duplicatesMaxLength,
+		CopyAllBlocked: copyAllBlocked(page),
+		Schema:         page.Schema,
+		Alerting:       page.Alerting,
+		Summaries:      page.Summaries,
+		WrapMode:       wrapMode,
+		Direction:      page.Direction,
+		Search:         page.Search,
+		Lines:          page.Lines,
+		Markdown:       page.Markdown,
+		Tracking:       page.Tracking,
+		TrackNotice:    page.TrackNotice,
+		Hashes:         page.Hashes,
+		Access:         page.Access,
+		Encoding:       page.Encoding,
+		Memory:         page.Memory,
+		Cuts:           page.Cuts,
+		ValueChunk:     maxValueBytes,
 	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -647,6 +3956,46 @@ func renderResultWithPreload(w http.ResponseWriter, key string, index int64, lle
 	}
 }
 
+// displayKey returns a printable form of a Redis key. Keys may hold arbitrary
+// bytes, so anything that is not valid, printable UTF-8 is shown with Go
+// escape sequences (e.g. \n, \xff) rather than written to the page raw.
+func displayKey(key string) string {
+	if utf8.ValidString(key) {
+		printable := true
+		for _, r := range key {
+			if !unicode.IsPrint(r) {
+				printable = false
+				break
+			}
+		}
+		if printable {
+			return key
+		}
+	}
+	quoted := strconv.Quote(key)
+	return quoted[1 : len(quoted)-1]
+}
+
+// lindexPath returns the path of the list inspector for links in pages.
+func lindexPath() string {
+	return lindexRoute
+}
+
+// parseTemplate parses a page template with the helper functions shared by
+// all pages.
+func parseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(template.FuncMap{
+		"customCSSLink":     customCSSLink,
+		"prefsScript":       prefsScript,
+		"staticURL":         staticURL,
+		"lindexPath":        lindexPath,
+		"displayKey":        displayKey,
+		"formatTime":        formatTime,
+		"streamIDTime":      streamIDTime,
+		"persistenceBanner": persistenceBanner,
+	}).Parse(text)
+}
+
 func renderNotFound(w http.ResponseWriter, message string) {
 	tmplStr := `<!DOCTYPE html>
 <html>
@@ -688,8 +4037,10 @@ func renderNotFound(w http.ResponseWriter, message string) {
             text-decoration: underline;
         }
     </style>
+    {{customCSSLink}}
 </head>
 <body>
+    {{persistenceBanner}}
     <div class="error-container">
         <h1>404</h1>
         <p>{{.Message}}</p>
@@ -698,7 +4049,7 @@ func renderNotFound(w http.ResponseWriter, message string) {
 </body>
 </html>`
 
-	tmpl, err := template.New("notfound").Parse(tmplStr)
+	tmpl, err := parseTemplate("notfound", tmplStr)
 	if err != nil {
 		http.Error(w, message, http.StatusNotFound)
 		return
@@ -718,6 +4069,16 @@ func renderNotFound(w http.ResponseWriter, message string) {
 }
 
 func renderError(w http.ResponseWriter, message string) {
+	renderErrorStatus(w, http.StatusInternalServerError, "Error", message)
+}
+
+// renderBadRequest renders the error page for a malformed request.
+func renderBadRequest(w http.ResponseWriter, message string) {
+	renderErrorStatus(w, http.StatusBadRequest, "Bad Request", message)
+}
+
+// renderErrorStatus renders the error page with the given status and heading.
+func renderErrorStatus(w http.ResponseWriter, status int, title, message string) {
 	tmplStr := `<!DOCTYPE html>
 <html>
 <head>
@@ -758,30 +4119,34 @@ func renderError(w http.ResponseWriter, message string) {
             text-decoration: underline;
         }
     </style>
+    {{customCSSLink}}
 </head>
 <body>
+    {{persistenceBanner}}
     <div class="error-container">
-        <h1>Error</h1>
+        <h1>{{.Title}}</h1>
         <p>{{.Message}}</p>
         <a href="/" class="back-link">← Back to Home</a>
     </div>
 </body>
 </html>`
 
-	tmpl, err := template.New("error").Parse(tmplStr)
+	tmpl, err := parseTemplate("error", tmplStr)
 	if err != nil {
-		http.Error(w, message, http.StatusInternalServerError)
+		http.Error(w, message, status)
 		return
 	}
 
 	data := struct {
+		Title   string
 		Message string
 	}{
+		Title:   title,
 		Message: message,
 	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	w.WriteHeader(http.StatusInternalServerError)
+	w.WriteHeader(status)
 	if err := tmpl.Execute(w, data); err != nil {
 		log.Printf("Error rendering template: %v", err)
 	}
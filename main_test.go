@@ -1,12 +1,36 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/its-the-vibe/RediScan/inspector"
+	"github.com/redis/go-redis/v9"
 )
 
+// useMiniredis points redisClient at an in-memory Redis server for the
+// duration of the test.
+func useMiniredis(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	prev := redisClient
+	redisClient = redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	forgetEmptyScan()
+	t.Cleanup(func() {
+		redisClient.Close()
+		redisClient = prev
+	})
+	return mr
+}
+
 func TestPrettyPrintJSON_ValidJSON(t *testing.T) {
 	input := `{"name":"Alice","age":30}`
 	result := prettyPrintJSON(input)
@@ -42,6 +66,17 @@ func TestPrettyPrintJSON_JSONArray(t *testing.T) {
 	}
 }
 
+func TestPrettyPrintJSON_MaxDepth(t *testing.T) {
+	prev := maxJSONDepth
+	defer func() { maxJSONDepth = prev }()
+	maxJSONDepth = 1
+
+	result := prettyPrintJSON(`{"outer":{"inner":1}}`)
+	if !strings.Contains(result, inspector.DepthElided) || strings.Contains(result, "inner") {
+		t.Errorf("expected nested object to be hidden, got: %s", result)
+	}
+}
+
 func TestRenderNotFound(t *testing.T) {
 	rr := httptest.NewRecorder()
 	renderNotFound(rr, "Key 'test' does not exist")
@@ -85,6 +120,50 @@ func TestIndexHandler_NotFound(t *testing.T) {
 	}
 }
 
+func TestIndexHandler_HomeRedirect(t *testing.T) {
+	prev := homeRedirect
+	defer func() { homeRedirect = prev }()
+	homeRedirect = "/dashboard?pattern=jobs:*"
+
+	rr := httptest.NewRecorder()
+	indexHandler(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rr.Code != http.StatusFound || rr.Header().Get("Location") != homeRedirect {
+		t.Errorf("expected a redirect to %s, got %d %q", homeRedirect, rr.Code, rr.Header().Get("Location"))
+	}
+
+	rr = httptest.NewRecorder()
+	indexHandler(rr, httptest.NewRequest(http.MethodGet, "/?list", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("expected the key list with a query string, got %d", rr.Code)
+	}
+}
+
+func TestIndexHandler_KeyDelimiter(t *testing.T) {
+	prev := keyDelimiter
+	defer func() { keyDelimiter = prev }()
+	keyDelimiter = "/"
+
+	rr := httptest.NewRecorder()
+	indexHandler(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	body := rr.Body.String()
+	if !strings.Contains(body, `id="keyTree"`) || !strings.Contains(body, `const keyDelimiter = "/";`) {
+		t.Errorf("expected a tree view splitting keys on the configured delimiter, got: %s", body)
+	}
+}
+
+func TestCheckHomeRedirect(t *testing.T) {
+	for _, target := range []string{"/lindex?key=main-queue", "/dashboard?pattern=jobs:*", "/?group=1"} {
+		if err := checkHomeRedirect(target); err != nil {
+			t.Errorf("%q: unexpected error: %v", target, err)
+		}
+	}
+	for _, target := range []string{"https://example.com/", "//example.com/x", "lindex?key=a", "/", "%zz"} {
+		if err := checkHomeRedirect(target); err == nil {
+			t.Errorf("%q: expected an error", target)
+		}
+	}
+}
+
 func TestLindexHandler_MissingKey(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/lindex", nil)
 	rr := httptest.NewRecorder()
@@ -99,3 +178,577 @@ func TestLindexHandler_MissingKey(t *testing.T) {
 		t.Errorf("expected 'Missing' in response body, got: %s", body)
 	}
 }
+
+func TestLindexHandler_InvalidIndexPage(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("jobs", "a")
+
+	rr := httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=jobs&index=foo", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for a malformed index on the HTML page, got %d", rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("expected an HTML error page, got %q", ct)
+	}
+}
+
+func TestDisplayKey(t *testing.T) {
+	tests := map[string]string{
+		"mylist":          "mylist",
+		"with space":      "with space",
+		"café":            "café",
+		"line\nbreak":     `line\nbreak`,
+		"bin\xffary":      `bin\xffary`,
+		"tab\tand\x00nul": `tab\tand\x00nul`,
+	}
+	for input, want := range tests {
+		if got := displayKey(input); got != want {
+			t.Errorf("displayKey(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestBinaryKeyRoundTrip(t *testing.T) {
+	mr := useMiniredis(t)
+	key := "50% off\xffnow"
+	mr.Lpush(key, `{"a":1}`)
+
+	rr := httptest.NewRecorder()
+	listsAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/lists", nil))
+	var resp struct {
+		Lists []listSummary `json:"lists"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding /api/lists: %v", err)
+	}
+	if len(resp.Lists) != 1 || resp.Lists[0].Display != `50% off\xffnow` {
+		t.Fatalf("expected escaped key name from /api/lists, got: %+v", resp.Lists)
+	}
+
+	rr = httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key="+resp.Lists[0].Query, nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200 following index link, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if !strings.Contains(rr.Body.String(), `50% off\xffnow`) {
+		t.Errorf("expected escaped key name on result page")
+	}
+}
+
+func TestResolveIndex(t *testing.T) {
+	tests := []struct {
+		index, newest string
+		want          int64
+		wantErr       bool
+	}{
+		{"", "", 9, false},
+		{"3", "", 3, false},
+		{"-1", "", 9, false},
+		{"-5", "", 5, false},
+		{"", "1", 9, false},
+		{"", "5", 5, false},
+		{"2", "5", 2, false},
+		{"0%", "", 0, false},
+		{"50%", "", 4, false},
+		{"90%", "", 8, false},
+		{"100%", "", 9, false},
+		{"first", "", 0, false},
+		{"last", "", 9, false},
+		{"mid", "", 5, false},
+		{"first", "5", 0, false},
+		{"abc", "", 0, true},
+		{"First", "", 0, true},
+		{"150%", "", 0, true},
+		{"x%", "", 0, true},
+		{"", "0", 0, true},
+		{"", "x", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := resolveIndex(tt.index, tt.newest, 10, false)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("resolveIndex(%q, %q) error = %v, wantErr %v", tt.index, tt.newest, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("resolveIndex(%q, %q) = %d, want %d", tt.index, tt.newest, got, tt.want)
+		}
+	}
+}
+
+func TestHandlers_MethodNotAllowed(t *testing.T) {
+	mux := http.NewServeMux()
+	registerRoutes(mux, nil)
+	for _, path := range []string{"/", "/lindex", customCSSRoute, "/api/lists"} {
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, path, nil))
+
+		if rr.Code != http.StatusMethodNotAllowed {
+			t.Errorf("POST %s: expected status 405, got %d", path, rr.Code)
+		}
+		if allow := rr.Header().Get("Allow"); allow != "GET, HEAD" {
+			t.Errorf("POST %s: expected Allow header 'GET, HEAD', got %q", path, allow)
+		}
+	}
+}
+
+func writeSchema(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "order.json")
+	schema := `{
+		"type": "object",
+		"required": ["id", "status"],
+		"properties": {
+			"id": {"type": "integer"},
+			"status": {"enum": ["ok", "failed"]}
+		}
+	}`
+	if err := os.WriteFile(path, []byte(schema), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLindexHandler_SchemaSummary(t *testing.T) {
+	mr := useMiniredis(t)
+	prev := schemaRules
+	defer func() { schemaRules = prev }()
+	rules, err := inspector.ParseSchemaRules(`[{"pattern":"orders:*","schema":"` + writeSchema(t) + `"}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemaRules = rules
+
+	mr.RPush("orders:new", `{"id": 1, "status": "ok"}`, `{"id": 2, "status": "lost"}`)
+
+	rr := httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=orders:new", nil))
+	body := rr.Body.String()
+	if !strings.Contains(body, "1 of 2 elements fail") {
+		t.Errorf("expected schema summary in page, got: %s", body)
+	}
+}
+
+func TestLindexHandler_AlertPatterns(t *testing.T) {
+	mr := useMiniredis(t)
+	prev := alertRules
+	defer func() { alertRules = prev }()
+	rules, err := inspector.ParseAlertRules(`[{"pattern":"orders:*","alerts":["\"status\":\"error\""]}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alertRules = rules
+
+	mr.RPush("orders:new", `{"status":"ok"}`, `{"status":"error"}`, `{"status":"error"}`)
+
+	rr := httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=orders:new", nil))
+	body := rr.Body.String()
+	for _, want := range []string{"2 of 3 elements match an alert pattern", "⚠ 2 alerting", "new Set([1,2])"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in page", want)
+		}
+	}
+}
+
+func TestLindexHandler_Summaries(t *testing.T) {
+	mr := useMiniredis(t)
+	prev := summaryRules
+	defer func() { summaryRules = prev }()
+	rules, err := inspector.ParseSummaryRules(`[{"pattern":"orders:*","fields":["id","status"]}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	summaryRules = rules
+
+	mr.RPush("orders:new", `{"id":1,"status":"ok","payload":"..."}`, "plain text")
+
+	rr := httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=orders:new", nil))
+	if body := rr.Body.String(); !strings.Contains(body, `const summaries = ["id=1 status=ok",""];`) {
+		t.Errorf("expected per-element summaries in page")
+	}
+}
+
+func TestLindexHandler_PreloadBudget(t *testing.T) {
+	mr := useMiniredis(t)
+	prev := maxPreloadSize
+	defer func() { maxPreloadSize = prev }()
+	maxPreloadSize = 64
+
+	mr.RPush("big", strings.Repeat("a", 50), strings.Repeat("b", 50), "current")
+
+	rr := httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=big&index=2", nil))
+	body := rr.Body.String()
+	// The element farthest from the current one is dropped to fit the budget
+	if !strings.Contains(body, `const allValues = ["`+strings.Repeat("b", 50)+`","current"];`) {
+		t.Errorf("expected only the elements around the current one to be embedded")
+	}
+	if !strings.Contains(body, "too large to load at once, so only elements 1&ndash;2") {
+		t.Errorf("expected a notice that only some elements were loaded")
+	}
+
+	maxPreloadSize = 0
+	rr = httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=big&index=2", nil))
+	if strings.Contains(rr.Body.String(), "too large to load at once") {
+		t.Errorf("expected every element to be loaded without a budget")
+	}
+}
+
+func TestLindexHandler_PreloadBudgetLimitsRendering(t *testing.T) {
+	mr := useMiniredis(t)
+	prevSize, prevCache := maxPreloadSize, prettyJSON
+	defer func() { maxPreloadSize, prettyJSON = prevSize, prevCache }()
+	maxPreloadSize = 64
+	prettyJSON = inspector.NewPrettyCache(100)
+
+	for i := 0; i < 10; i++ {
+		mr.RPush("big", fmt.Sprintf(`{"n":%d,"pad":"%s"}`, i, strings.Repeat("x", 30)))
+	}
+
+	rr := httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=big&index=9", nil))
+	if !strings.Contains(rr.Body.String(), "too large to load at once") {
+		t.Fatalf("expected the budget to be exceeded")
+	}
+	// Only the current element fits the budget, so it is the only one rendered
+	if n := prettyJSON.Len(); n != 1 {
+		t.Errorf("expected only the loaded elements to be rendered, but %d were", n)
+	}
+}
+
+func TestLindexHandler_PreloadNewest(t *testing.T) {
+	mr := useMiniredis(t)
+	prev := preloadNewest
+	defer func() { preloadNewest = prev }()
+	preloadNewest = 2
+
+	mr.RPush("log", "a", "b", "c", "d", "e")
+
+	rr := httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=log", nil))
+	body := rr.Body.String()
+	if !strings.Contains(body, `const allValues = ["d","e"];`) || !strings.Contains(body, "const offset =  3 ;") {
+		t.Errorf("expected only the newest two elements, from index 3")
+	}
+	if !strings.Contains(body, `<pre id="valueDisplay">e</pre>`) || !strings.Contains(body, "Only elements 3&ndash;4") {
+		t.Errorf("expected the newest element and a partial load notice, got: %s", body)
+	}
+
+	rr = httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=log&index=1", nil))
+	body = rr.Body.String()
+	if !strings.Contains(body, `const allValues = ["a","b"];`) || !strings.Contains(body, `<pre id="valueDisplay">b</pre>`) {
+		t.Errorf("expected the elements around an older index to be loaded")
+	}
+
+	rr = httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=log&full=1", nil))
+	body = rr.Body.String()
+	if !strings.Contains(body, `const allValues = ["a","b","c","d","e"];`) || strings.Contains(body, "Only elements") {
+		t.Errorf("expected full=1 to load the whole list")
+	}
+}
+
+func TestResolveIndex_HeadIsNewest(t *testing.T) {
+	if got, _ := resolveIndex("", "", 10, true); got != 0 {
+		t.Errorf("expected default index 0 when head is newest, got %d", got)
+	}
+	if got, _ := resolveIndex("", "3", 10, true); got != 2 {
+		t.Errorf("expected 3rd newest at index 2 when head is newest, got %d", got)
+	}
+	if got, _ := resolveIndex("-1", "", 10, true); got != 9 {
+		t.Errorf("expected negative index to keep LINDEX semantics, got %d", got)
+	}
+}
+
+func TestLindexHandler_PushDirection(t *testing.T) {
+	mr := useMiniredis(t)
+	prev := directionRules
+	defer func() { directionRules = prev }()
+	rules, err := inspector.ParseDirectionRules(`[{"pattern":"logs:*","direction":"lpush"}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	directionRules = rules
+
+	mr.RPush("logs:app", "newest", "older")
+
+	rr := httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=logs:app", nil))
+	body := rr.Body.String()
+	if !strings.Contains(body, "Populated via LPUSH") {
+		t.Errorf("expected push direction in metadata, got: %s", body)
+	}
+	if !strings.Contains(body, `<pre id="valueDisplay">newest</pre>`) {
+		t.Errorf("expected the head element to be shown by default for LPUSH lists")
+	}
+	if !strings.Contains(body, "← Newer (Left Arrow)") {
+		t.Errorf("expected navigation labels to reflect LPUSH ordering")
+	}
+}
+
+func TestLindexHandler_LastAccess(t *testing.T) {
+	mr := useMiniredis(t)
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	mr.SetTime(start)
+	mr.RPush("jobs", "a", "b")
+	mr.SetTime(start.Add(2 * time.Minute))
+
+	rr := httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=jobs", nil))
+	if body := rr.Body.String(); !strings.Contains(body, "idle for 2m0s before this page loaded") {
+		t.Errorf("expected the idle time from before the page read the list, got: %s", body)
+	}
+}
+
+func TestLindexHandler_CopyAll(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("jobs", "a", "b", "c")
+
+	rr := httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=jobs", nil))
+	body := rr.Body.String()
+	if !strings.Contains(body, `<button type="button" id="copyAllBtn" title=`) {
+		t.Errorf("expected Copy all enabled for a fully preloaded list")
+	}
+
+	// Only the newest elements are on the page, so a copy would be partial
+	prev := preloadNewest
+	defer func() { preloadNewest = prev }()
+	preloadNewest = 2
+	rr = httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=jobs", nil))
+	if !strings.Contains(rr.Body.String(), `id="copyAllBtn" disabled title="Only part of this list is loaded`) {
+		t.Errorf("expected Copy all disabled with a reason when only part of the list is loaded")
+	}
+}
+
+func TestLindexHandler_BinaryHint(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("mixed", "text", "\xff\xfebinary")
+
+	rr := httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=mixed&index=1", nil))
+	body := rr.Body.String()
+	if !strings.Contains(body, `<span id="binaryHint" class="binary-hint">`) {
+		t.Errorf("expected the binary hint shown for a non-UTF-8 element")
+	}
+	if !strings.Contains(body, "const binaryValues = [false,true];") {
+		t.Errorf("expected per-element binary flags for navigation")
+	}
+
+	rr = httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=mixed&index=0", nil))
+	if !strings.Contains(rr.Body.String(), `<span id="binaryHint" class="binary-hint" hidden>`) {
+		t.Errorf("expected the binary hint hidden for a text element")
+	}
+}
+
+func TestLindexHandler_WrappedJSON(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("events", `"{\"id\":1}"`, `{"id":2}`)
+
+	rr := httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=events&index=0", nil))
+	body := rr.Body.String()
+	if !strings.Contains(body, `<span id="wrappedHint" class="wrapped-hint">JSON stored as a string`) || !strings.Contains(body, "unwrap and pretty-print") {
+		t.Errorf("expected an offer to unwrap JSON stored as a string")
+	}
+	if !strings.Contains(body, "const wrappedLayers = [1,0];") {
+		t.Errorf("expected per-element layer counts for navigation")
+	}
+
+	// Unwrapping pretty-prints the inner document; the stored value is untouched
+	rr = httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=events&index=0&transform=unwrap", nil))
+	body = rr.Body.String()
+	if !strings.Contains(body, "unwrapped: JSON stored as a string") || strings.Contains(body, "unwrap and pretty-print") {
+		t.Errorf("expected a notice that the value was unwrapped")
+	}
+	if !strings.Contains(body, "<pre id=\"valueDisplay\">{\n  &#34;id&#34;: 1\n}</pre>") {
+		t.Errorf("expected the inner JSON pretty-printed, got: %s", body)
+	}
+	if stored, _ := mr.List("events"); stored[0] != `"{\"id\":1}"` {
+		t.Errorf("expected the stored value untouched, got %q", stored[0])
+	}
+}
+
+func TestLindexHandler_BrowsingControls(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("jobs", "a", "b", "c")
+
+	rr := httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=jobs", nil))
+	body := rr.Body.String()
+	if !strings.Contains(body, `id="tableViewport"`) {
+		t.Errorf("expected table view on result page")
+	}
+	if !strings.Contains(body, `onclick="jumpToRandom()"`) {
+		t.Errorf("expected random element button on result page")
+	}
+	if !strings.Contains(body, `id="shortcutHelp" class="shortcut-overlay" hidden`) || !strings.Contains(body, "Last element (index 2)") {
+		t.Errorf("expected a hidden keyboard shortcut overlay on result page")
+	}
+	if !strings.Contains(body, "rediscanRecentKeys") {
+		t.Errorf("expected the result page to record the key in the browser's history")
+	}
+	if !strings.Contains(body, `onclick="takeSnapshot()"`) || !strings.Contains(body, `const snapshotStorageKey = 'rediscanSnapshot:' + "jobs";`) {
+		t.Errorf("expected snapshot controls on result page")
+	}
+	if !strings.Contains(body, `id="tabularDisplay"`) || !strings.Contains(body, `id="tabularToggle"`) {
+		t.Errorf("expected a table display for tabular values on result page")
+	}
+	if !strings.Contains(body, `id="jsonPath"`) || !strings.Contains(body, `id="copyPathBtn"`) {
+		t.Errorf("expected a JSON path breadcrumb on result page")
+	}
+	if !strings.Contains(body, `id="changesView"`) || !strings.Contains(body, `id="changesList"`) {
+		t.Errorf("expected a changes-only view on result page")
+	}
+	if !strings.Contains(body, `id="positionSlider" min="0" max="2"`) || !strings.Contains(body, "sliderSettleDelay") {
+		t.Errorf("expected a position slider that waits to load unloaded elements on result page")
+	}
+}
+
+func TestLindexHandler_PercentageIndex(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("jobs", "a", "b", "c", "d", "e")
+
+	// A hand-typed, unescaped '%' is accepted as well as the encoded form
+	for _, query := range []string{"index=50%", "index=50%25"} {
+		rr := httptest.NewRecorder()
+		lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=jobs&"+query, nil))
+		if !strings.Contains(rr.Body.String(), `<pre id="valueDisplay">c</pre>`) {
+			t.Errorf("%s: expected the middle element, got status %d", query, rr.Code)
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=jobs&index=abc%25", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for a malformed percentage, got %d", rr.Code)
+	}
+}
+
+func TestLindexHandler_SearchDeepLink(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("orders", `{"id":"order-1"}`, `{"id":"order-123"}`)
+
+	rr := httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=orders&search=order-123", nil))
+	body := rr.Body.String()
+	if !strings.Contains(body, `id="searchInput" value="order-123"`) {
+		t.Errorf("expected search box prefilled from the search parameter")
+	}
+	if !strings.Contains(body, `if ("order-123") {`) {
+		t.Errorf("expected the search to run on load")
+	}
+}
+
+func TestLindexHandler_ExpiredKey(t *testing.T) {
+	useMiniredis(t)
+
+	rr := httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=gone&ttl=30", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "most likely expired") {
+		t.Errorf("expected an expiry hint for a key listed with a TTL, got: %s", rr.Body.String())
+	}
+}
+
+func TestLindexHandler_Head(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("jobs", "a", "b", "c")
+	mr.Set("plain", "value")
+
+	tests := []struct {
+		key     string
+		status  int
+		length  string
+		keyType string
+	}{
+		{"jobs", http.StatusOK, "3", ""},
+		{"missing", http.StatusNotFound, "", ""},
+		{"plain", http.StatusNotFound, "", "string"},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		lindexHandler(rr, httptest.NewRequest(http.MethodHead, "/lindex?key="+tt.key, nil))
+		if rr.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.key, tt.status, rr.Code)
+		}
+		if got := rr.Header().Get("X-List-Length"); got != tt.length {
+			t.Errorf("%s: expected X-List-Length %q, got %q", tt.key, tt.length, got)
+		}
+		if got := rr.Header().Get("X-Key-Type"); got != tt.keyType {
+			t.Errorf("%s: expected X-Key-Type %q, got %q", tt.key, tt.keyType, got)
+		}
+		if rr.Body.Len() != 0 {
+			t.Errorf("%s: expected no body, got %q", tt.key, rr.Body.String())
+		}
+	}
+}
+
+func TestLindexHandler_ScalarHint(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("counters", "true", "42")
+
+	rr := httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=counters", nil))
+	body := rr.Body.String()
+	if !strings.Contains(body, `<span id="scalarHint" class="scalar-hint">scalar: number</span>`) {
+		t.Errorf("expected a scalar hint for the bare number")
+	}
+	if !strings.Contains(body, `const scalarKinds = ["boolean","number"];`) {
+		t.Errorf("expected per-element scalar kinds for navigation")
+	}
+}
+
+func TestLindexHandler_SplitLines(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("logs", "{\"level\":\"info\"}\nplain line\n")
+
+	rr := httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=logs", nil))
+	body := rr.Body.String()
+	if !strings.Contains(body, `id="splitLines">`) || strings.Contains(body, `<pre id="valueDisplay" hidden>`) {
+		t.Errorf("expected the value shown whole by default")
+	}
+
+	rr = httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=logs&lines=1", nil))
+	body = rr.Body.String()
+	if !strings.Contains(body, `id="splitLines" checked>`) || !strings.Contains(body, `<pre id="valueDisplay" hidden>`) {
+		t.Errorf("expected lines=1 to start in the split view")
+	}
+}
+
+func TestLindexHandler_TrackElement(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("queue", "a", "b", "c", "d")
+	hash := inspector.ElementHash("c")
+
+	// Two elements are consumed, so "c" moves from index 2 to 0
+	mr.Lpop("queue")
+	mr.Lpop("queue")
+	rr := httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=queue&index=2&track="+hash, nil))
+	body := rr.Body.String()
+	if rr.Code != http.StatusOK || !strings.Contains(body, "moved from index 2 to 0") || !strings.Contains(body, "let currentIndex =  0 ;") {
+		t.Errorf("expected the tracked element to be followed to index 0, got %d", rr.Code)
+	}
+	if !strings.Contains(body, `id="trackElement" checked`) || !strings.Contains(body, hash) {
+		t.Errorf("expected tracking to stay on with the elements' hashes")
+	}
+
+	mr.Lpop("queue")
+	rr = httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=queue&index=2&track="+hash, nil))
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "no longer in the list") {
+		t.Errorf("expected a consumed element to be reported, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/its-the-vibe/RediScan/inspector"
+)
+
+// markdownAPIHandler renders the element at key[index] as sanitized HTML for
+// the result page's Markdown view. The element is decoded as it is for
+// display, with the key's configured transforms and any view transforms.
+func markdownAPIHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	viewTransforms, err := parseViewTransforms(query.Get("transform"))
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, apiErrInvalidParameter, err.Error())
+		return
+	}
+	key, index, value, ok := readAPIElement(w, query)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"key":   key,
+		"index": index,
+		"html":  inspector.RenderMarkdown(renderValueWith(key, value, viewTransforms, nil)),
+	})
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMarkdownAPIHandler(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("notifications", "# Hi\n\n<script>alert(1)</script>", "IyBIaQ==")
+
+	rr := httptest.NewRecorder()
+	markdownAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/markdown?key=notifications&index=0", nil))
+	var body struct {
+		HTML string `json:"html"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("expected a JSON body, got %q: %v", rr.Body.String(), err)
+	}
+	if !strings.Contains(body.HTML, "<h1>Hi</h1>") || strings.Contains(body.HTML, "<script") {
+		t.Errorf("expected sanitized Markdown, got %q", body.HTML)
+	}
+
+	// View transforms are applied before rendering
+	rr = httptest.NewRecorder()
+	markdownAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/markdown?key=notifications&index=1&transform=base64", nil))
+	body.HTML = ""
+	json.Unmarshal(rr.Body.Bytes(), &body)
+	if !strings.Contains(body.HTML, "<h1>Hi</h1>") {
+		t.Errorf("expected the decoded value to be rendered, got %s", rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	markdownAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/markdown?key=notifications&index=0&transform=nope", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unknown transform, got %d", rr.Code)
+	}
+}
+
+func TestLindexHandler_MarkdownView(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("notifications", "# Hi")
+
+	rr := httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=notifications&index=0&md=1", nil))
+	body := rr.Body.String()
+	if !strings.Contains(body, `id="markdownView" checked`) || !strings.Contains(body, `<pre id="valueDisplay" hidden>`) {
+		t.Errorf("expected the Markdown view to be selected")
+	}
+}
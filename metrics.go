@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/its-the-vibe/RediScan/inspector"
+)
+
+var (
+	metricsPatterns  []string // Key patterns whose list lengths /metrics exports, from METRICS_LIST_PATTERNS
+	metricsMaxSeries = 100    // Most rediscan_list_length series exported, to bound Prometheus cardinality
+)
+
+// parseMetricsPatterns parses a comma-separated list of key patterns.
+func parseMetricsPatterns(config string) []string {
+	var patterns []string
+	for _, pattern := range strings.Split(config, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// escapeLabelValue escapes a Prometheus label value. Keys are shown in their
+// display form first, as label values must be valid UTF-8.
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// metricsHandler serves Prometheus text-format metrics: whether Redis is
+// reachable, and the length of each list matching METRICS_LIST_PATTERNS,
+// read with LLEN on every scrape.
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	var b strings.Builder
+	up := 1
+	if err := redisClient.Ping(ctx).Err(); err != nil {
+		log.Printf("Metrics: Redis is unreachable: %v", err)
+		up = 0
+	}
+	b.WriteString("# HELP rediscan_redis_up Whether Redis answered PING.\n")
+	b.WriteString("# TYPE rediscan_redis_up gauge\n")
+	fmt.Fprintf(&b, "rediscan_redis_up %d\n", up)
+
+	if len(metricsPatterns) > 0 && up == 1 {
+		// A key matching several patterns is exported once
+		seen := make(map[string]bool)
+		var lists []inspector.ListInfo
+		truncated := false
+		for _, pattern := range metricsPatterns {
+			// Ask for one extra so hitting the cap can be reported
+			matches, err := inspector.MatchingLists(ctx, redisClient, pattern, metricsMaxSeries-len(lists)+1)
+			if err != nil {
+				log.Printf("Metrics: error scanning keys for %q: %v", pattern, err)
+				continue
+			}
+			for _, list := range matches {
+				if seen[list.Name] {
+					continue
+				}
+				if len(lists) == metricsMaxSeries {
+					truncated = true
+					break
+				}
+				seen[list.Name] = true
+				lists = append(lists, list)
+			}
+			if truncated {
+				log.Printf("Metrics: more than METRICS_MAX_SERIES (%d) lists match METRICS_LIST_PATTERNS; exporting the first %d", metricsMaxSeries, metricsMaxSeries)
+				break
+			}
+		}
+
+		b.WriteString("# HELP rediscan_list_length Number of elements in a monitored Redis list.\n")
+		b.WriteString("# TYPE rediscan_list_length gauge\n")
+		for _, list := range lists {
+			fmt.Fprintf(&b, "rediscan_list_length{key=\"%s\"} %d\n", escapeLabelValue(displayKey(list.Name)), list.Size)
+		}
+		b.WriteString("# HELP rediscan_list_length_truncated Whether more lists matched than METRICS_MAX_SERIES allows.\n")
+		b.WriteString("# TYPE rediscan_list_length_truncated gauge\n")
+		if truncated {
+			b.WriteString("rediscan_list_length_truncated 1\n")
+		} else {
+			b.WriteString("rediscan_list_length_truncated 0\n")
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write([]byte(b.String()))
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	mr := useMiniredis(t)
+	prevPatterns, prevMax := metricsPatterns, metricsMaxSeries
+	defer func() { metricsPatterns, metricsMaxSeries = prevPatterns, prevMax }()
+	metricsPatterns = parseMetricsPatterns("jobs:*, jobs:high")
+	metricsMaxSeries = 10
+
+	mr.RPush("jobs:high", "a", "b")
+	mr.RPush("jobs:\"low\"", "a")
+	mr.RPush("other", "a")
+	mr.Set("jobs:config", "x")
+
+	rr := httptest.NewRecorder()
+	metricsHandler(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rr.Body.String()
+	for _, want := range []string{
+		"rediscan_redis_up 1\n",
+		"# TYPE rediscan_list_length gauge\n",
+		`rediscan_list_length{key="jobs:high"} 2` + "\n",
+		`rediscan_list_length{key="jobs:\"low\""} 1` + "\n",
+		"rediscan_list_length_truncated 0\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in metrics, got:\n%s", want, body)
+		}
+	}
+	if strings.Count(body, `key="jobs:high"`) != 1 || strings.Contains(body, "other") || strings.Contains(body, "config") {
+		t.Errorf("expected each matching list exported once, got:\n%s", body)
+	}
+
+	metricsMaxSeries = 1
+	rr = httptest.NewRecorder()
+	metricsHandler(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body = rr.Body.String()
+	if strings.Count(body, "rediscan_list_length{") != 1 || !strings.Contains(body, "rediscan_list_length_truncated 1\n") {
+		t.Errorf("expected the series to be capped and reported as truncated, got:\n%s", body)
+	}
+}
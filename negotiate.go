@@ -0,0 +1,58 @@
+package main
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// wantsJSON reports whether the request's Accept header prefers
+// application/json to HTML. Browsers, clients without an Accept header and
+// ambiguous headers such as */* get HTML.
+func wantsJSON(r *http.Request) bool {
+	jsonQ, htmlQ := 0.0, 0.0
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if qStr, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(qStr, 64); err != nil {
+				continue
+			}
+		}
+		switch mediaType {
+		case "application/json":
+			jsonQ = max(jsonQ, q)
+		case "text/html", "text/*", "*/*":
+			htmlQ = max(htmlQ, q)
+		}
+	}
+	return jsonQ > htmlQ
+}
+
+// lindexElement is the JSON form of a /lindex response.
+type lindexElement struct {
+	Key         string `json:"key"`
+	Display     string `json:"display"` // The key with non-printable bytes escaped
+	Index       int64  `json:"index"`
+	Length      int64  `json:"length"`
+	Value       string `json:"value"`    // The element as stored
+	Rendered    string `json:"rendered"` // The element as shown on the page: transformed and pretty-printed
+	Summary     string `json:"summary,omitempty"`
+	Alerting    bool   `json:"alerting"`
+	SchemaValid *bool  `json:"schema_valid,omitempty"` // Omitted when no schema applies
+	SchemaError string `json:"schema_error,omitempty"`
+}
+
+// lindexFail reports a failed /lindex request as an API error with code for
+// clients that asked for JSON, and otherwise with the render page function.
+func lindexFail(w http.ResponseWriter, asJSON bool, render func(http.ResponseWriter, string), status int, code, message string) {
+	if asJSON {
+		writeAPIError(w, status, code, message)
+		return
+	}
+	render(w, message)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWantsJSON(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{"", false},
+		{"*/*", false},
+		{"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", false},
+		{"application/json", true},
+		{"application/json, text/plain, */*", false},
+		{"application/json, */*;q=0.1", true},
+		{"text/html;q=0.5, application/json", true},
+		{"application/json;q=0", false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/lindex?key=jobs", nil)
+		r.Header.Set("Accept", tt.accept)
+		if got := wantsJSON(r); got != tt.want {
+			t.Errorf("wantsJSON(%q) = %v, want %v", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestLindexHandler_JSON(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("jobs", `{"id":1}`, `{"id":2}`)
+	mr.Set("plain", "value")
+
+	get := func(target, accept string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		r.Header.Set("Accept", accept)
+		rr := httptest.NewRecorder()
+		lindexHandler(rr, r)
+		return rr
+	}
+
+	rr := get("/lindex?key=jobs&index=0", "application/json")
+	var element lindexElement
+	if err := json.Unmarshal(rr.Body.Bytes(), &element); err != nil {
+		t.Fatalf("expected a JSON body, got %q: %v", rr.Body.String(), err)
+	}
+	if rr.Header().Get("Content-Type") != "application/json" || element.Index != 0 || element.Length != 2 || element.Value != `{"id":1}` || !strings.Contains(element.Rendered, "\n") {
+		t.Errorf("unexpected JSON response: %+v", element)
+	}
+	if !strings.Contains(rr.Header().Get("Vary"), "Accept") {
+		t.Errorf("expected the response to vary on Accept")
+	}
+
+	if rr := get("/lindex?key=jobs&index=0", "*/*"); !strings.HasPrefix(rr.Header().Get("Content-Type"), "text/html") {
+		t.Errorf("expected HTML for an ambiguous Accept header, got %s", rr.Header().Get("Content-Type"))
+	}
+
+	errorTests := []struct {
+		target string
+		status int
+		code   string
+	}{
+		{"/lindex?key=missing", http.StatusNotFound, apiErrNotFound},
+		{"/lindex?key=plain", http.StatusConflict, apiErrWrongType},
+		{"/lindex?key=jobs&index=9", http.StatusNotFound, apiErrOutOfBounds},
+		{"/lindex?key=jobs&index=abc", http.StatusBadRequest, apiErrInvalidIndex},
+		{"/lindex?key=jobs&index=0,1", http.StatusBadRequest, apiErrInvalidIndex},
+		{"/lindex", http.StatusBadRequest, apiErrInvalidParameter},
+	}
+	for _, tt := range errorTests {
+		rr := get(tt.target, "application/json")
+		if rr.Code != tt.status || !strings.Contains(rr.Body.String(), `"code":"`+tt.code+`"`) {
+			t.Errorf("%s: expected %d %s, got %d %s", tt.target, tt.status, tt.code, rr.Code, rr.Body.String())
+		}
+	}
+}
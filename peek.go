@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/its-the-vibe/RediScan/inspector"
+)
+
+// peekPreviewLength is how many characters of each element the peek page shows.
+const peekPreviewLength = 200
+
+// newestIndex returns the index of the most recently pushed element of key,
+// honouring the configured push direction.
+func newestIndex(key string) int64 {
+	if inspector.MatchDirection(directionRules, key).HeadIsNewest() {
+		return 0
+	}
+	return -1
+}
+
+// elementPreview renders value on one line for the peek page and the index
+// page previews: the list's SUMMARY_FIELDS summary when one is configured and
+// the element has any of the fields, else the start of the raw value. Either
+// is cut to n characters.
+func elementPreview(key, value string, n int) string {
+	if rule := inspector.MatchSummary(summaryRules, key); rule != nil {
+		if summary := rule.Summarize(value); summary != "" {
+			return inspector.Truncate(summary, n)
+		}
+	}
+	return inspector.Truncate(value, n)
+}
+
+// peekRow is one list on the peek page.
+type peekRow struct {
+	inspector.Peek
+	Preview string // The element as shown, a summary or the start of the value
+}
+
+func peekHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	pattern := query.Get("pattern")
+	if pattern == "" {
+		renderNotFound(w, "Missing 'pattern' parameter")
+		return
+	}
+
+	// An index reads the same position of every list instead of the newest
+	indexFor := newestIndex
+	var index int64
+	hasIndex := query.Get("index") != ""
+	if hasIndex {
+		var err error
+		if index, err = strconv.ParseInt(query.Get("index"), 10, 64); err != nil {
+			renderBadRequest(w, "Invalid 'index' parameter: expected an integer, negative to count back from the tail")
+			return
+		}
+		indexFor = func(string) int64 { return index }
+	}
+
+	lists, err := inspector.MatchingLists(ctx, redisClient, pattern, maxLists)
+	if err != nil {
+		renderError(w, fmt.Sprintf("Error scanning keys: %v", err))
+		return
+	}
+	peeks, err := inspector.PeekLists(ctx, redisClient, lists, indexFor)
+	if err != nil {
+		renderError(w, fmt.Sprintf("Error reading list elements: %v", err))
+		return
+	}
+	rows := make([]peekRow, len(peeks))
+	for i, peek := range peeks {
+		rows[i] = peekRow{Peek: peek, Preview: elementPreview(peek.Key, peek.Value, peekPreviewLength)}
+		rows[i].Value = inspector.Truncate(peek.Value, peekPreviewLength)
+	}
+
+	tmplStr := `<!DOCTYPE html>
+<html>
+<head>
+    <title>RediScan - {{if .HasIndex}}Element {{.Index}}{{else}}Newest{{end}} in {{.Pattern}}</title>
+    <style>
+        body {
+            font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif;
+            max-width: 1200px;
+            margin: 0 auto;
+            padding: 20px;
+            background-color: #f5f5f5;
+        }
+        h1 a {
+            color: #333;
+            text-decoration: none;
+        }
+        h1 a:hover {
+            text-decoration: underline;
+        }
+        .peeks {
+            background-color: white;
+            padding: 20px;
+            border-radius: 5px;
+            box-shadow: 0 2px 4px rgba(0,0,0,0.1);
+            margin-bottom: 20px;
+        }
+        .peeks h2 {
+            margin-top: 0;
+            color: #333;
+        }
+        table {
+            width: 100%;
+            border-collapse: collapse;
+            table-layout: fixed;
+        }
+        th, td {
+            text-align: left;
+            padding: 8px;
+            border-bottom: 1px solid #eee;
+            vertical-align: top;
+        }
+        td a {
+            color: #2196F3;
+            text-decoration: none;
+            word-break: break-all;
+        }
+        td a:hover {
+            text-decoration: underline;
+        }
+        .preview {
+            font-family: monospace;
+            white-space: nowrap;
+            overflow: hidden;
+            text-overflow: ellipsis;
+        }
+        .missing, .empty {
+            color: #666;
+            font-style: italic;
+        }
+        .back-link {
+            color: #2196F3;
+            text-decoration: none;
+        }
+    </style>
+    {{customCSSLink}}
+</head>
+<body>
+    {{persistenceBanner}}
+    <h1><a href="/">RediScan - Redis List Inspector</a></h1>
+    <div class="peeks">
+        <h2>{{if .HasIndex}}Element {{.Index}}{{else}}Newest element{{end}} of lists matching <code>{{.Pattern}}</code></h2>
+        {{if .Peeks}}
+        <table>
+            <tr><th style="width: 25%">Key</th><th style="width: 10%">Length</th><th>{{if .HasIndex}}Element {{.Index}}{{else}}Newest Element{{end}}</th></tr>
+            {{range .Peeks}}
+            <tr>
+                <td><a href="{{lindexPath}}?key={{.Key | urlquery}}{{if and $.HasIndex .Found}}&amp;index={{$.Index}}{{end}}">{{displayKey .Key}}</a></td>
+                <td>{{.Size}}</td>
+                {{if .Found}}<td class="preview" title="{{.Value}}">{{.Preview}}</td>{{else if $.HasIndex}}<td class="missing">(no element at index {{$.Index}})</td>{{else}}<td class="missing">(no longer available)</td>{{end}}
+            </tr>
+            {{end}}
+        </table>
+        {{else}}
+        <p class="empty">No lists match this pattern.</p>
+        {{end}}
+    </div>
+    <a href="/" class="back-link">← Back to Home</a>
+</body>
+</html>`
+
+	tmpl, err := parseTemplate("peek", tmplStr)
+	if err != nil {
+		renderError(w, fmt.Sprintf("Template error: %v", err))
+		return
+	}
+
+	data := struct {
+		Pattern  string
+		HasIndex bool
+		Index    int64
+		Peeks    []peekRow
+	}{
+		Pattern:  pattern,
+		HasIndex: hasIndex,
+		Index:    index,
+		Peeks:    rows,
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("Error rendering template: %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/its-the-vibe/RediScan/inspector"
+)
+
+func TestPeekHandler(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("jobs:email", "old", `{"to":"newest@example.com"}`)
+	mr.RPush("jobs:sms", strings.Repeat("x", peekPreviewLength+50))
+	mr.RPush("other", "not matched")
+
+	rr := httptest.NewRecorder()
+	peekHandler(rr, httptest.NewRequest(http.MethodGet, "/peek?pattern=jobs:*", nil))
+	body := rr.Body.String()
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if !strings.Contains(body, "newest@example.com") || strings.Contains(body, ">old<") {
+		t.Errorf("expected the newest element of jobs:email, got: %s", body)
+	}
+	if strings.Contains(body, "not matched") {
+		t.Errorf("expected non-matching lists to be excluded")
+	}
+	if strings.Contains(body, strings.Repeat("x", peekPreviewLength+1)) {
+		t.Errorf("expected previews to be truncated")
+	}
+	if !strings.Contains(body, `href="/lindex?key=jobs%3aemail"`) && !strings.Contains(body, `href="/lindex?key=jobs%3Aemail"`) {
+		t.Errorf("expected a link to each list's inspector view, got: %s", body)
+	}
+}
+
+func TestPeekHandler_FixedIndex(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("jobs:email", "email-head", "email-1", "email-tail")
+	mr.RPush("jobs:sms", "sms-head")
+
+	rr := httptest.NewRecorder()
+	peekHandler(rr, httptest.NewRequest(http.MethodGet, "/peek?pattern=jobs:*&index=1", nil))
+	body := rr.Body.String()
+	if !strings.Contains(body, ">email-1<") || strings.Contains(body, "email-tail") {
+		t.Errorf("expected element 1 of jobs:email, got: %s", body)
+	}
+	if !strings.Contains(body, "(no element at index 1)") {
+		t.Errorf("expected the short list to be reported, got: %s", body)
+	}
+	if !strings.Contains(body, `&amp;index=1"`) {
+		t.Errorf("expected links to open the inspector at the index, got: %s", body)
+	}
+
+	rr = httptest.NewRecorder()
+	peekHandler(rr, httptest.NewRequest(http.MethodGet, "/peek?pattern=jobs:*&index=0", nil))
+	if body := rr.Body.String(); !strings.Contains(body, ">email-head<") || !strings.Contains(body, ">sms-head<") {
+		t.Errorf("expected the head of every list, got: %s", body)
+	}
+
+	rr = httptest.NewRecorder()
+	peekHandler(rr, httptest.NewRequest(http.MethodGet, "/peek?pattern=jobs:*&index=first", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an invalid index, got %d", rr.Code)
+	}
+}
+
+func TestPeekHandler_Summaries(t *testing.T) {
+	mr := useMiniredis(t)
+	prev := summaryRules
+	defer func() { summaryRules = prev }()
+	rules, err := inspector.ParseSummaryRules(`[{"pattern":"orders:*","fields":["id","status"]}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	summaryRules = rules
+
+	mr.RPush("orders:new", `{"id":7,"status":"paid","payload":"bulky"}`)
+	mr.RPush("orders:raw", "plain text")
+	mr.RPush("jobs", `{"id":8,"status":"queued"}`)
+
+	rr := httptest.NewRecorder()
+	peekHandler(rr, httptest.NewRequest(http.MethodGet, "/peek?pattern=*", nil))
+	body := rr.Body.String()
+	if !strings.Contains(body, ">id=7 status=paid<") {
+		t.Errorf("expected the configured summary for orders:new, got: %s", body)
+	}
+	if !strings.Contains(body, ">plain text<") {
+		t.Errorf("expected the raw preview for an element without the fields, got: %s", body)
+	}
+	if strings.Contains(body, "id=8") {
+		t.Errorf("expected lists without a summary rule to show the raw value, got: %s", body)
+	}
+
+	// The index page previews use the same summaries
+	rr = httptest.NewRecorder()
+	listsAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/lists", nil))
+	if !strings.Contains(rr.Body.String(), `"preview":"id=7 status=paid"`) {
+		t.Errorf("expected the summary as the index page preview, got: %s", rr.Body.String())
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"html/template"
+	"log"
+
+	"github.com/its-the-vibe/RediScan/inspector"
+)
+
+var (
+	persistenceCheck = true // Whether PERSISTENCE_CHECK looks for a Redis that saves nothing at startup
+	ephemeralRedis   bool   // The startup check found neither RDB snapshots nor AOF enabled
+)
+
+// checkPersistence runs the startup check for a Redis without persistence.
+// Failures (CONFIG is often disabled on managed Redis) are logged and leave
+// the warning off.
+func checkPersistence() {
+	status, err := inspector.Persistence(ctx, redisClient)
+	if err != nil {
+		ephemeralRedis = false
+		log.Printf("Could not check Redis persistence (set PERSISTENCE_CHECK=false to skip): %v", err)
+		return
+	}
+	ephemeralRedis = status.Ephemeral()
+	if ephemeralRedis {
+		log.Printf("Warning: Redis has neither RDB snapshots nor AOF enabled; its data will not survive a restart")
+	}
+}
+
+// persistenceBanner returns the warning shown at the top of every page when
+// the connected Redis persists nothing.
+func persistenceBanner() template.HTML {
+	if !ephemeralRedis {
+		return ""
+	}
+	return template.HTML(`<div class="persistence-warning" role="status" style="background-color: #fff8e1; border: 1px solid #ffe082; color: #795548; padding: 6px 12px; border-radius: 3px; margin-bottom: 10px; font-size: 13px;">` +
+		`This Redis has neither RDB snapshots nor AOF enabled, so the data shown will not survive a restart.</div>`)
+}
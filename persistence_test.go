@@ -0,0 +1,41 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPersistenceBanner(t *testing.T) {
+	prev := ephemeralRedis
+	defer func() { ephemeralRedis = prev }()
+	useMiniredis(t)
+
+	ephemeralRedis = false
+	rr := httptest.NewRecorder()
+	indexHandler(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	if strings.Contains(rr.Body.String(), "persistence-warning") {
+		t.Error("expected no warning when Redis persists its data")
+	}
+
+	ephemeralRedis = true
+	rr = httptest.NewRecorder()
+	indexHandler(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	if !strings.Contains(rr.Body.String(), "neither RDB snapshots nor AOF") {
+		t.Error("expected the index page to warn about an ephemeral Redis")
+	}
+}
+
+func TestCheckPersistence_Unsupported(t *testing.T) {
+	prev := ephemeralRedis
+	defer func() { ephemeralRedis = prev }()
+	useMiniredis(t)
+
+	// miniredis refuses CONFIG GET, as managed services may; no warning is shown
+	ephemeralRedis = true
+	checkPersistence()
+	if ephemeralRedis {
+		t.Error("expected a failed check to leave the warning off")
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+)
+
+const (
+	// prefsKeyPrefix is the reserved Redis key prefix preferences are stored
+	// under, one hash per user: rediscan:prefs:<user>.
+	prefsKeyPrefix = "rediscan:prefs:"
+	// prefsNamePrefix is the prefix of the browser localStorage names that are
+	// synced, e.g. rediscan.wrapMode. static/prefs.js uses the same prefix.
+	prefsNamePrefix = "rediscan."
+	maxPrefs        = 50   // Most preferences stored per user
+	maxPrefLength   = 1000 // Longest preference value accepted
+)
+
+var (
+	prefsEnabled    bool                 // Whether PREFS_ENABLED allows storing preferences in Redis
+	prefsUserHeader = "X-Forwarded-User" // Header an authenticating proxy sets to the signed-in user
+)
+
+// prefsUser returns the user a request was authenticated as by the proxy in
+// front of RediScan, or "" if there is none.
+func prefsUser(r *http.Request) string {
+	return strings.TrimSpace(r.Header.Get(prefsUserHeader))
+}
+
+// prefsAPIHandler reads (GET) or replaces (PUT) the signed-in user's UI
+// preferences, a JSON object of localStorage names to values.
+func prefsAPIHandler(w http.ResponseWriter, r *http.Request) {
+	if !prefsEnabled {
+		writeAPIError(w, http.StatusForbidden, apiErrForbidden, "Server-side preferences are disabled; set PREFS_ENABLED=true to enable them")
+		return
+	}
+	user := prefsUser(r)
+	if user == "" {
+		writeAPIError(w, http.StatusUnauthorized, apiErrUnauthenticated, fmt.Sprintf("No signed-in user: expected the %s header from an authenticating proxy", prefsUserHeader))
+		return
+	}
+	key := prefsKeyPrefix + user
+
+	if r.Method == http.MethodPut {
+		var prefs map[string]string
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxPrefs*(maxPrefLength+100))).Decode(&prefs); err != nil {
+			writeAPIError(w, http.StatusBadRequest, apiErrInvalidParameter, "Invalid preferences: expected a JSON object of strings")
+			return
+		}
+		if len(prefs) > maxPrefs {
+			writeAPIError(w, http.StatusBadRequest, apiErrInvalidParameter, fmt.Sprintf("Too many preferences: at most %d are stored", maxPrefs))
+			return
+		}
+		fields := make([]interface{}, 0, 2*len(prefs))
+		for name, value := range prefs {
+			if !strings.HasPrefix(name, prefsNamePrefix) || len(value) > maxPrefLength {
+				writeAPIError(w, http.StatusBadRequest, apiErrInvalidParameter, fmt.Sprintf("Invalid preference %q: names must start with %q and values be at most %d bytes", name, prefsNamePrefix, maxPrefLength))
+				return
+			}
+			fields = append(fields, name, value)
+		}
+
+		pipe := redisClient.TxPipeline()
+		pipe.Del(ctx, key)
+		if len(fields) > 0 {
+			pipe.HSet(ctx, key, fields...)
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			log.Printf("Error saving preferences for %q: %v", user, err)
+			writeAPIError(w, http.StatusInternalServerError, apiErrInternal, err.Error())
+			return
+		}
+	}
+
+	prefs, err := redisClient.HGetAll(ctx, key).Result()
+	if err != nil {
+		log.Printf("Error reading preferences for %q: %v", user, err)
+		writeAPIError(w, http.StatusInternalServerError, apiErrInternal, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"prefs": prefs})
+}
+
+// prefsScript returns a script that keeps the browser's rediscan.* settings in
+// step with the signed-in user's stored preferences: stored values are
+// applied on load (reloading once if they differ) and every change is saved
+// back. Templates place it in <head>, before their own scripts read settings.
+// The script itself is the bundled static/prefs.js.
+func prefsScript() template.HTML {
+	if !prefsEnabled {
+		return ""
+	}
+	return template.HTML(`<script src="` + staticURL("prefs.js") + `"></script>`)
+}
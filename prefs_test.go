@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPrefsAPIHandler(t *testing.T) {
+	mr := useMiniredis(t)
+	prev := prefsEnabled
+	defer func() { prefsEnabled = prev }()
+
+	rr := httptest.NewRecorder()
+	prefsAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/prefs", nil))
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("expected 403 while disabled, got %d", rr.Code)
+	}
+
+	prefsEnabled = true
+	rr = httptest.NewRecorder()
+	prefsAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/prefs", nil))
+	if rr.Code != http.StatusUnauthorized || !strings.Contains(rr.Body.String(), apiErrUnauthenticated) {
+		t.Errorf("expected 401 without a user, got %d", rr.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodPut, "/api/prefs", strings.NewReader(`{"rediscan.wrapMode": "stop"}`))
+	req.Header.Set("X-Forwarded-User", "ada")
+	rr = httptest.NewRecorder()
+	prefsAPIHandler(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 saving preferences, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if got := mr.HGet("rediscan:prefs:ada", "rediscan.wrapMode"); got != "stop" {
+		t.Errorf("expected preference stored under the reserved prefix, got %q", got)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/prefs", nil)
+	req.Header.Set("X-Forwarded-User", "ada")
+	rr = httptest.NewRecorder()
+	prefsAPIHandler(rr, req)
+	var body struct {
+		Prefs map[string]string `json:"prefs"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil || body.Prefs["rediscan.wrapMode"] != "stop" {
+		t.Errorf("expected stored preferences back, got %s", rr.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/api/prefs", strings.NewReader(`{"other": "x"}`))
+	req.Header.Set("X-Forwarded-User", "ada")
+	rr = httptest.NewRecorder()
+	prefsAPIHandler(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a name outside the rediscan. prefix, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/its-the-vibe/RediScan/inspector"
+)
+
+// writeEnabled allows the result page to add elements to lists (WRITE_ENABLED).
+var writeEnabled bool
+
+// pushHandler adds an element to a list with LPUSH or RPUSH, then shows it.
+// It is refused unless WRITE_ENABLED is set, since it mutates real data, and
+// for forms submitted from other sites.
+func pushHandler(w http.ResponseWriter, r *http.Request) {
+	if !refuseCrossOrigin(w, r) {
+		return
+	}
+	if !writeEnabled {
+		renderErrorStatus(w, http.StatusForbidden, "Forbidden", "Adding elements is disabled. Set WRITE_ENABLED=true to allow it.")
+		return
+	}
+
+	key := r.FormValue("key")
+	value := r.FormValue("value")
+	direction := inspector.PushDirection(r.FormValue("direction"))
+	if key == "" {
+		renderBadRequest(w, "Missing 'key' parameter")
+		return
+	}
+	if direction != inspector.PushLeft && direction != inspector.PushRight {
+		renderBadRequest(w, "Invalid 'direction' parameter: expected lpush or rpush")
+		return
+	}
+	if r.FormValue("validate_json") != "" && !json.Valid([]byte(value)) {
+		renderBadRequest(w, "The value is not valid JSON, so it was not added")
+		return
+	}
+
+	// Refuse up front rather than surfacing Redis's WRONGTYPE error
+	keyType, err := inspector.KeyType(ctx, redisClient, key)
+	if err != nil {
+		renderError(w, fmt.Sprintf("Error checking key: %v", err))
+		return
+	}
+	if keyType != "none" && keyType != "list" {
+		renderBadRequest(w, fmt.Sprintf("Key '%s' is not a list (type: %s)", displayKey(key), keyType))
+		return
+	}
+
+	index := "-1"
+	if direction == inspector.PushLeft {
+		err = redisClient.LPush(ctx, key, value).Err()
+		index = "0"
+	} else {
+		err = redisClient.RPush(ctx, key, value).Err()
+	}
+	if err != nil {
+		erroredKeys.record(key, errorKindRedis, err.Error())
+		renderError(w, fmt.Sprintf("Error adding element: %v", err))
+		return
+	}
+	forgetEmptyScan()
+
+	http.Redirect(w, r, lindexRoute+"?key="+url.QueryEscape(key)+"&index="+index, http.StatusSeeOther)
+}
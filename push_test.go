@@ -0,0 +1,104 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func postPush(form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/push", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rr := httptest.NewRecorder()
+	pushHandler(rr, req)
+	return rr
+}
+
+func TestPushHandler(t *testing.T) {
+	mr := useMiniredis(t)
+	prev := writeEnabled
+	defer func() { writeEnabled = prev }()
+	mr.RPush("jobs", `{"n":1}`)
+
+	writeEnabled = false
+	rr := postPush(url.Values{"key": {"jobs"}, "value": {`{"n":2}`}, "direction": {"rpush"}})
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403 when writes are disabled, got %d", rr.Code)
+	}
+
+	writeEnabled = true
+	rr = postPush(url.Values{"key": {"jobs"}, "value": {`{"n":2}`}, "direction": {"rpush"}})
+	if rr.Code != http.StatusSeeOther || rr.Header().Get("Location") != "/lindex?key=jobs&index=-1" {
+		t.Fatalf("expected redirect to the pushed element, got %d %q", rr.Code, rr.Header().Get("Location"))
+	}
+	rr = postPush(url.Values{"key": {"jobs"}, "value": {"first"}, "direction": {"lpush"}})
+	if rr.Header().Get("Location") != "/lindex?key=jobs&index=0" {
+		t.Errorf("expected redirect to the head after LPUSH, got %q", rr.Header().Get("Location"))
+	}
+	if values, _ := mr.List("jobs"); len(values) != 3 || values[0] != "first" || values[2] != `{"n":2}` {
+		t.Errorf("unexpected list contents: %v", values)
+	}
+
+	rr = postPush(url.Values{"key": {"jobs"}, "value": {"not json"}, "direction": {"rpush"}, "validate_json": {"1"}})
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for invalid JSON, got %d", rr.Code)
+	}
+
+	mr.Set("plain", "v")
+	rr = postPush(url.Values{"key": {"plain"}, "value": {"x"}, "direction": {"rpush"}})
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for a non-list key, got %d", rr.Code)
+	}
+}
+
+func TestPushHandler_CrossOrigin(t *testing.T) {
+	mr := useMiniredis(t)
+	prev := writeEnabled
+	defer func() { writeEnabled = prev }()
+	writeEnabled = true
+
+	push := func(header, value string) int {
+		form := url.Values{"key": {"jobs"}, "value": {"x"}, "direction": {"rpush"}}
+		req := httptest.NewRequest(http.MethodPost, "/push", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set(header, value)
+		rr := httptest.NewRecorder()
+		pushHandler(rr, req)
+		return rr.Code
+	}
+
+	if code := push("Sec-Fetch-Site", "cross-site"); code != http.StatusForbidden {
+		t.Errorf("expected a form from another site to be refused, got %d", code)
+	}
+	if code := push("Origin", "https://evil.example"); code != http.StatusForbidden {
+		t.Errorf("expected a foreign Origin to be refused, got %d", code)
+	}
+	if mr.Exists("jobs") {
+		t.Fatal("expected nothing written by refused requests")
+	}
+
+	if code := push("Sec-Fetch-Site", "same-origin"); code != http.StatusSeeOther {
+		t.Errorf("expected RediScan's own form to be accepted, got %d", code)
+	}
+	if code := push("Origin", "http://example.com"); code != http.StatusSeeOther {
+		t.Errorf("expected a matching Origin to be accepted, got %d", code)
+	}
+}
+
+func TestLindexHandler_PushFormGated(t *testing.T) {
+	mr := useMiniredis(t)
+	prev := writeEnabled
+	defer func() { writeEnabled = prev }()
+	mr.RPush("jobs", "a")
+
+	for _, enabled := range []bool{false, true} {
+		writeEnabled = enabled
+		rr := httptest.NewRecorder()
+		lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=jobs", nil))
+		if got := strings.Contains(rr.Body.String(), `action="/push"`); got != enabled {
+			t.Errorf("WRITE_ENABLED=%v: expected add-element form shown = %v", enabled, enabled)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// forceReadOnly (FORCE_READONLY) guarantees RediScan never modifies data:
+// the features that write are turned off and readOnlyHook refuses mutating
+// commands before they reach Redis, whatever code sends them.
+var forceReadOnly bool
+
+// writeCommands modify data or server state. readOnlyHook refuses them and
+// retryHook never retries them. Besides commands that write keys, the list
+// covers scripts and functions (which may write), blocking pops and
+// XREADGROUP (which change what other consumers see), PUBLISH (which
+// delivers messages), and server commands that change configuration,
+// persistence or connected clients.
+var writeCommands = map[string]bool{
+	// Keys
+	"del": true, "unlink": true, "expire": true, "pexpire": true, "expireat": true, "pexpireat": true,
+	"persist": true, "rename": true, "renamenx": true, "move": true, "copy": true, "restore": true,
+	"migrate": true, "sort": true, "swapdb": true, "flushdb": true, "flushall": true,
+	// Strings
+	"set": true, "setnx": true, "setex": true, "psetex": true, "mset": true, "msetnx": true,
+	"append": true, "setrange": true, "getset": true, "getdel": true, "getex": true,
+	"incr": true, "incrby": true, "incrbyfloat": true, "decr": true, "decrby": true,
+	"setbit": true, "bitop": true, "bitfield": true,
+	// Lists
+	"lpush": true, "rpush": true, "lpushx": true, "rpushx": true, "linsert": true, "lset": true,
+	"lrem": true, "ltrim": true, "lpop": true, "rpop": true, "lmpop": true, "blpop": true,
+	"brpop": true, "blmpop": true, "lmove": true, "blmove": true, "rpoplpush": true, "brpoplpush": true,
+	// Hashes
+	"hset": true, "hsetnx": true, "hmset": true, "hdel": true, "hincrby": true, "hincrbyfloat": true,
+	"hgetdel": true, "hgetex": true, "hsetex": true, "hexpire": true, "hpexpire": true,
+	"hexpireat": true, "hpexpireat": true, "hpersist": true,
+	// Sets
+	"sadd": true, "srem": true, "spop": true, "smove": true,
+	"sinterstore": true, "sunionstore": true, "sdiffstore": true,
+	// Sorted sets
+	"zadd": true, "zincrby": true, "zrem": true, "zremrangebyscore": true, "zremrangebyrank": true,
+	"zremrangebylex": true, "zpopmin": true, "zpopmax": true, "bzpopmin": true, "bzpopmax": true,
+	"zmpop": true, "bzmpop": true, "zunionstore": true, "zinterstore": true, "zdiffstore": true,
+	"zrangestore": true,
+	// Streams
+	"xadd": true, "xdel": true, "xtrim": true, "xgroup": true, "xack": true, "xclaim": true,
+	"xautoclaim": true, "xreadgroup": true, "xsetid": true,
+	// HyperLogLogs and geospatial indexes
+	"pfadd": true, "pfmerge": true, "geoadd": true, "georadius": true, "georadiusbymember": true,
+	"geosearchstore": true,
+	// Scripts, functions and pub/sub
+	"eval": true, "evalsha": true, "fcall": true, "publish": true, "spublish": true,
+	// Server
+	"debug": true, "shutdown": true, "save": true, "bgsave": true, "bgrewriteaof": true,
+	"replicaof": true, "slaveof": true, "failover": true,
+}
+
+// writeSubcommands are the container commands that write only with some
+// subcommands, so that CONFIG GET or CLIENT LIST, say, still work.
+var writeSubcommands = map[string]map[string]bool{
+	"acl":      {"deluser": true, "load": true, "save": true, "setuser": true},
+	"client":   {"kill": true, "no-evict": true, "no-touch": true, "pause": true, "unpause": true, "unblock": true},
+	"cluster":  {"addslots": true, "addslotsrange": true, "bumpepoch": true, "delslots": true, "delslotsrange": true, "failover": true, "flushslots": true, "forget": true, "meet": true, "replicate": true, "reset": true, "saveconfig": true, "set-config-epoch": true, "setslot": true},
+	"config":   {"resetstat": true, "rewrite": true, "set": true},
+	"function": {"delete": true, "flush": true, "kill": true, "load": true, "restore": true},
+	"latency":  {"reset": true},
+	"memory":   {"purge": true},
+	"module":   {"load": true, "loadex": true, "unload": true},
+	"script":   {"flush": true, "kill": true, "load": true},
+	"slowlog":  {"reset": true},
+}
+
+// isWriteCommand reports whether cmd is in writeCommands, or is a container
+// command with a subcommand in writeSubcommands.
+func isWriteCommand(cmd redis.Cmder) bool {
+	name := cmd.Name()
+	if writeCommands[name] {
+		return true
+	}
+	subcommands, ok := writeSubcommands[name]
+	if !ok {
+		return false
+	}
+	args := cmd.Args()
+	if len(args) < 2 {
+		return false
+	}
+	return subcommands[strings.ToLower(fmt.Sprint(args[1]))]
+}
+
+// readOnlyError is returned for a command refused under FORCE_READONLY.
+type readOnlyError struct {
+	Command string
+}
+
+func (e *readOnlyError) Error() string {
+	return fmt.Sprintf("%s refused: FORCE_READONLY is set, so commands that modify data are never sent", strings.ToUpper(e.Command))
+}
+
+// readOnlyHook is a go-redis hook that refuses write commands. A pipeline
+// or transaction holding one is refused as a whole, so none of it is sent.
+type readOnlyHook struct{}
+
+func (readOnlyHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (readOnlyHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if isWriteCommand(cmd) {
+			err := &readOnlyError{Command: cmd.Name()}
+			cmd.SetErr(err)
+			return err
+		}
+		return next(ctx, cmd)
+	}
+}
+
+func (readOnlyHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		var refused error
+		for _, cmd := range cmds {
+			if isWriteCommand(cmd) {
+				err := &readOnlyError{Command: cmd.Name()}
+				cmd.SetErr(err)
+				if refused == nil {
+					refused = err
+				}
+			}
+		}
+		if refused != nil {
+			return refused
+		}
+		return next(ctx, cmds)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestReadOnlyHook(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("jobs", "a", "b")
+	redisClient.AddHook(readOnlyHook{})
+	ctx := context.Background()
+
+	var refused *readOnlyError
+	if err := redisClient.RPush(ctx, "jobs", "c").Err(); !errors.As(err, &refused) || refused.Command != "rpush" {
+		t.Errorf("expected RPUSH to be refused, got %v", err)
+	}
+	if err := redisClient.Do(ctx, "FLUSHALL").Err(); !errors.As(err, &refused) {
+		t.Errorf("expected FLUSHALL sent with Do to be refused, got %v", err)
+	}
+	if n, err := redisClient.LLen(ctx, "jobs").Result(); err != nil || n != 2 {
+		t.Errorf("expected reads to pass and the list to be untouched, got %d (%v)", n, err)
+	}
+
+	// A transaction with a write in it is refused as a whole
+	pipe := redisClient.TxPipeline()
+	pipe.LLen(ctx, "jobs")
+	pipe.Del(ctx, "jobs")
+	if _, err := pipe.Exec(ctx); !errors.As(err, &refused) {
+		t.Errorf("expected the transaction to be refused, got %v", err)
+	}
+	if !mr.Exists("jobs") {
+		t.Error("expected nothing in the refused transaction to be sent")
+	}
+
+	// Read-only transactions, as used to load lists, still work
+	pipe = redisClient.TxPipeline()
+	typeCmd := pipe.Type(ctx, "jobs")
+	rangeCmd := pipe.LRange(ctx, "jobs", 0, -1)
+	if _, err := pipe.Exec(ctx); err != nil || typeCmd.Val() != "list" || len(rangeCmd.Val()) != 2 {
+		t.Errorf("expected a read-only transaction to run, got %v", err)
+	}
+}
+
+func TestIsWriteCommand(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		args  []interface{}
+		write bool
+	}{
+		{[]interface{}{"publish", "events", "hi"}, true},
+		{[]interface{}{"CLIENT", "KILL", "ID", "7"}, true},
+		{[]interface{}{"client", "pause", "1000"}, true},
+		{[]interface{}{"client", "list"}, false},
+		{[]interface{}{"cluster", "forget", "abc"}, true},
+		{[]interface{}{"cluster", "info"}, false},
+		{[]interface{}{"config", "set", "save", ""}, true},
+		{[]interface{}{"config", "get", "save"}, false},
+		{[]interface{}{"script", "flush"}, true},
+		{[]interface{}{"client"}, false},
+		{[]interface{}{"lrange", "jobs", 0, -1}, false},
+	}
+	for _, tt := range tests {
+		if got := isWriteCommand(redis.NewCmd(ctx, tt.args...)); got != tt.write {
+			t.Errorf("isWriteCommand(%v) = %v, want %v", tt.args, got, tt.write)
+		}
+	}
+}
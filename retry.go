@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	debugLogging bool      // Enables debug-level log lines (LOG_LEVEL=debug)
+	redisRetry   retryHook // Retry policy installed on redisClient
+)
+
+// debugf logs like log.Printf when debug logging is enabled.
+func debugf(format string, args ...interface{}) {
+	if debugLogging {
+		log.Printf("Debug: "+format, args...)
+	}
+}
+
+// transientErrorPrefixes are Redis error replies that clear up on their own,
+// typically while a server restarts or a replica is promoted.
+var transientErrorPrefixes = []string{"LOADING", "MASTERDOWN", "TRYAGAIN", "CLUSTERDOWN"}
+
+// isTransient reports whether err is worth retrying: a network failure or a
+// Redis reply that signals a temporary condition. Logical errors such as
+// WRONGTYPE, and a missing element (redis.Nil), are not.
+func isTransient(err error) bool {
+	if err == nil || errors.Is(err, redis.Nil) {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var redisErr redis.Error
+	if errors.As(err, &redisErr) {
+		for _, prefix := range transientErrorPrefixes {
+			if strings.HasPrefix(redisErr.Error(), prefix) {
+				return true
+			}
+		}
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// retryHook is a go-redis hook that retries read commands and pipelines
+// failing with a transient error, waiting baseDelay, then twice that, and so
+// on between attempts. Write commands are never retried, since repeating one
+// that reached the server before the connection failed would apply it twice.
+type retryHook struct {
+	attempts  int
+	baseDelay time.Duration
+}
+
+func (h retryHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h retryHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if isWriteCommand(cmd) {
+			return next(ctx, cmd)
+		}
+		return h.do(ctx, cmd.Name(), func() error { return next(ctx, cmd) })
+	}
+}
+
+func (h retryHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		for _, cmd := range cmds {
+			if isWriteCommand(cmd) {
+				return next(ctx, cmds)
+			}
+		}
+		return h.do(ctx, "pipeline", func() error { return next(ctx, cmds) })
+	}
+}
+
+// do runs fn, retrying it with exponential backoff while it fails with a
+// transient error and attempts remain.
+func (h retryHook) do(ctx context.Context, name string, fn func() error) error {
+	err := fn()
+	delay := h.baseDelay
+	for attempt := 2; attempt <= h.attempts && isTransient(err); attempt++ {
+		debugf("Retrying %s in %v (attempt %d of %d) after transient error: %v", name, delay, attempt, h.attempts, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		err = fn()
+		delay *= 2
+	}
+	return err
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// flakyProcess fails with each of errs in turn, then succeeds.
+func flakyProcess(calls *int, errs ...error) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		*calls++
+		if *calls <= len(errs) {
+			return errs[*calls-1]
+		}
+		return nil
+	}
+}
+
+func TestRetryHook(t *testing.T) {
+	hook := retryHook{attempts: 3, baseDelay: time.Millisecond}
+	ctx := context.Background()
+	lindex := redis.NewStringCmd(ctx, "lindex", "jobs", 0)
+
+	calls := 0
+	if err := hook.ProcessHook(flakyProcess(&calls, io.EOF, io.EOF))(ctx, lindex); err != nil || calls != 3 {
+		t.Errorf("expected success on the third attempt, got err=%v after %d calls", err, calls)
+	}
+
+	calls = 0
+	if err := hook.ProcessHook(flakyProcess(&calls, io.EOF, io.EOF, io.EOF))(ctx, lindex); err == nil || calls != 3 {
+		t.Errorf("expected failure after 3 attempts, got err=%v after %d calls", err, calls)
+	}
+
+	calls = 0
+	wrongType := redis.NewStringCmd(ctx, "lindex", "hash", 0)
+	wrongTypeErr := redisReply("WRONGTYPE Operation against a key holding the wrong kind of value")
+	if err := hook.ProcessHook(flakyProcess(&calls, wrongTypeErr))(ctx, wrongType); err == nil || calls != 1 {
+		t.Errorf("expected logical errors not to be retried, got %d calls", calls)
+	}
+
+	calls = 0
+	push := redis.NewIntCmd(ctx, "lpush", "jobs", "x")
+	if err := hook.ProcessHook(flakyProcess(&calls, io.EOF))(ctx, push); err == nil || calls != 1 {
+		t.Errorf("expected writes not to be retried, got %d calls", calls)
+	}
+
+	calls = 0
+	pause := redis.NewStatusCmd(ctx, "client", "pause", 1000)
+	if err := hook.ProcessHook(flakyProcess(&calls, io.EOF))(ctx, pause); err == nil || calls != 1 {
+		t.Errorf("expected commands refused under FORCE_READONLY not to be retried either, got %d calls", calls)
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := map[error]bool{
+		io.EOF:           true,
+		redis.Nil:        false,
+		context.Canceled: false,
+		redisReply("LOADING Redis is loading the dataset in memory"): true,
+		redisReply("ERR unknown command"):                            false,
+		errors.New("some other failure"):                             false,
+	}
+	for err, want := range tests {
+		if got := isTransient(err); got != want {
+			t.Errorf("isTransient(%v) = %v, want %v", err, got, want)
+		}
+	}
+}
+
+// redisReply is an error reply from the Redis server.
+type redisReply string
+
+func (e redisReply) Error() string { return string(e) }
+func (e redisReply) RedisError()   {}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// route is a named HTTP handler registration. Names are what operators list
+// in DISABLED_ROUTES; Methods are the only request methods the route accepts,
+// checked by serveRoute so handlers need not check them again.
+type route struct {
+	Name    string
+	Pattern string
+	Methods []string
+	Handler http.HandlerFunc
+}
+
+// Method sets shared by most routes. HEAD is accepted wherever GET is.
+var (
+	readMethods      = []string{http.MethodGet, http.MethodHead}
+	writeMethods     = []string{http.MethodPost}
+	readWriteMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost}
+)
+
+// lindexRoute is the path the list inspector is served at, set by
+// LINDEX_ROUTE for deployments where /lindex clashes with another service
+// behind the same gateway. Pages link to it through the lindexPath template
+// function.
+var lindexRoute = "/lindex"
+
+// maxRequestBodyBytes caps the body of requests that carry one (POST and
+// PUT), set by MAX_REQUEST_BODY_BYTES.
+var maxRequestBodyBytes int64 = 1 << 20
+
+// routes returns every handler RediScan can serve, in registration order.
+func routes() []route {
+	return []route{
+		{"index", "/{$}", readMethods, indexHandler},
+		{"lindex", lindexRoute, readMethods, lindexHandler},
+		{"lists-api", "/api/lists", readMethods, listsAPIHandler},
+		{"keys-api", "/api/keys", readMethods, keysAPIHandler},
+		{"keys-export-api", "/api/keys/export", readMethods, keysExportHandler},
+		{"stats-api", "/api/stats", readMethods, statsAPIHandler},
+		{"llen-api", "/api/llen", readMethods, llenAPIHandler},
+		{"types-api", "/api/types", writeMethods, typesAPIHandler},
+		{"find-api", "/api/find", readMethods, findAPIHandler},
+		{"time-window-api", "/api/time-window", readMethods, timeWindowAPIHandler},
+		{"diagnose-api", "/api/diagnose", readMethods, diagnoseAPIHandler},
+		{"duplicates-api", "/api/duplicates", readMethods, duplicatesAPIHandler},
+		{"markdown-api", "/api/markdown", readMethods, markdownAPIHandler},
+		{"value-stream-api", "/api/value-stream", readMethods, valueStreamHandler},
+		{"prefs-api", "/api/prefs", []string{http.MethodGet, http.MethodHead, http.MethodPut}, prefsAPIHandler},
+		{"aggregate", "/aggregate", readMethods, aggregateHandler},
+		{"sample", "/sample", readMethods, sampleHandler},
+		{"push", "/push", writeMethods, pushHandler},
+		{"console", "/console", readWriteMethods, consoleHandler},
+		{"peek", "/peek", readMethods, peekHandler},
+		{"stream", "/stream", readMethods, streamHandler},
+		{"dashboard", "/dashboard", readMethods, dashboardHandler},
+		{"lengths-api", "/api/lengths", readMethods, lengthsAPIHandler},
+		{"follow", "/follow", readMethods, followHandler},
+		{"tail-api", "/api/tail", readMethods, tailAPIHandler},
+		{"custom-css", customCSSRoute, readMethods, customCSSHandler},
+		{"static", staticRoute, readMethods, staticHandler},
+		{"metrics", "/metrics", readMethods, metricsHandler},
+		{"admin-errors", "/admin/errors", readMethods, errorReportHandler},
+		{"admin-errors-clear", "/admin/errors/clear", writeMethods, clearErrorReportHandler},
+		{"admin-audit", "/admin/audit", readMethods, auditHandler},
+		{"admin-config", "/admin/config", readMethods, configHandler},
+		{"admin-testconn", "/api/testconn", writeMethods, testConnAPIHandler},
+	}
+}
+
+// checkRoutePath returns an error unless path can replace the pattern of the
+// route called name: a plain absolute path, without wildcards or a trailing
+// slash, that no other route already serves.
+func checkRoutePath(name, path string) error {
+	if len(path) < 2 || path[0] != '/' || strings.HasSuffix(path, "/") || strings.Contains(path, "//") {
+		return errors.New("must be a path such as /inspect, without a trailing slash")
+	}
+	for _, c := range path {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.ContainsRune("/-._~", c)) {
+			return fmt.Errorf("must contain only letters, digits and / - . _ ~, not %q", c)
+		}
+	}
+	for _, r := range routes() {
+		if r.Name == name {
+			continue
+		}
+		if r.Pattern == path || (strings.HasSuffix(r.Pattern, "/") && strings.HasPrefix(path, r.Pattern)) {
+			return fmt.Errorf("clashes with the %s route (%s)", r.Name, r.Pattern)
+		}
+	}
+	return nil
+}
+
+// parseDisabledRoutes parses a comma-separated list of route names. Unknown
+// names are rejected so a typo cannot leave a route exposed unnoticed.
+func parseDisabledRoutes(config string) (map[string]bool, error) {
+	known := make(map[string]bool)
+	for _, r := range routes() {
+		known[r.Name] = true
+	}
+
+	disabled := make(map[string]bool)
+	for _, name := range strings.Split(config, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !known[name] {
+			names := make([]string, 0, len(known))
+			for n := range known {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf("unknown route %q (known routes: %s)", name, strings.Join(names, ", "))
+		}
+		disabled[name] = true
+	}
+	return disabled, nil
+}
+
+// registerRoutes registers every route on mux except those disabled.
+// Requests to a disabled route fall through to the mux's 404 handling. Routes
+// that call Redis share the MAX_CONCURRENT_REDIS_OPS limit.
+//
+// Patterns are registered without a method (e.g. "/lindex" rather than
+// "GET /lindex") so that the catch-all "/" does not conflict with them and
+// so a wrong method gets the route's own 405 response (JSON for /api/ routes)
+// instead of the mux's plain-text one. The method check is done by
+// serveRoute from the route's Methods.
+func registerRoutes(mux *http.ServeMux, disabled map[string]bool) {
+	for _, r := range routes() {
+		if disabled[r.Name] {
+			log.Printf("Route %s (%s) disabled", r.Name, r.Pattern)
+			continue
+		}
+		handler := r.Handler
+		if !redisFreeRoutes[r.Name] && !redisBatchedRoutes[r.Name] {
+			handler = limitRedis(handler)
+		}
+		mux.HandleFunc(r.Pattern, serveRoute(r, handler))
+	}
+}
+
+// serveRoute refuses requests whose method r does not declare, asks for the
+// admin password on adminRoutes and caps the size of request bodies before
+// calling next. Checking these first means a refused request never waits for
+// a Redis slot.
+func serveRoute(r route, next http.HandlerFunc) http.HandlerFunc {
+	isAPI := strings.HasPrefix(r.Pattern, "/api/")
+	return func(w http.ResponseWriter, req *http.Request) {
+		if isAPI {
+			if !apiAllowMethods(w, req, r.Methods...) {
+				return
+			}
+		} else if !allowMethods(w, req, r.Methods...) {
+			return
+		}
+		if adminRoutes[r.Name] && !requireAdmin(w, req) {
+			return
+		}
+
+		if req.Method == http.MethodPost || req.Method == http.MethodPut {
+			if req.ContentLength > maxRequestBodyBytes {
+				message := fmt.Sprintf("The request body is larger than the %d bytes allowed (MAX_REQUEST_BODY_BYTES)", maxRequestBodyBytes)
+				if isAPI {
+					writeAPIError(w, http.StatusRequestEntityTooLarge, apiErrTooLarge, message)
+				} else {
+					renderErrorStatus(w, http.StatusRequestEntityTooLarge, "Request Too Large", message)
+				}
+				return
+			}
+			req.Body = http.MaxBytesReader(w, req.Body, maxRequestBodyBytes)
+		}
+		next(w, req)
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseDisabledRoutes(t *testing.T) {
+	disabled, err := parseDisabledRoutes(" peek, admin-errors-clear ,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !disabled["peek"] || !disabled["admin-errors-clear"] || len(disabled) != 2 {
+		t.Errorf("unexpected disabled set: %v", disabled)
+	}
+
+	if _, err := parseDisabledRoutes("peek,nope"); err == nil {
+		t.Error("expected an error for an unknown route name")
+	}
+}
+
+func TestRegisterRoutes_Disabled(t *testing.T) {
+	mux := http.NewServeMux()
+	registerRoutes(mux, map[string]bool{"admin-errors": true, "admin-errors-clear": true})
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/admin/errors", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected disabled route to return 404, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/lindex", nil))
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected enabled route to be served, got %d", rr.Code)
+	}
+}
+
+func TestRegisterRoutes_Methods(t *testing.T) {
+	mux := http.NewServeMux()
+	registerRoutes(mux, nil)
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/api/lists", nil))
+	if rr.Code != http.StatusMethodNotAllowed || rr.Header().Get("Allow") != "GET, HEAD" {
+		t.Errorf("expected 405 with Allow: GET, HEAD, got %d %q", rr.Code, rr.Header().Get("Allow"))
+	}
+	if !strings.Contains(rr.Body.String(), apiErrMethodNotAllowed) {
+		t.Errorf("expected a JSON error for an API route, got %s", rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/push", nil))
+	if rr.Code != http.StatusMethodNotAllowed || rr.Header().Get("Allow") != "POST" {
+		t.Errorf("expected 405 with Allow: POST, got %d %q", rr.Code, rr.Header().Get("Allow"))
+	}
+
+	// The index only serves "/" itself; other paths are not found whatever the method
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/nope", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown path, got %d", rr.Code)
+	}
+}
+
+func TestRegisterRoutes_BodyLimit(t *testing.T) {
+	prevLimit, prevWrite := maxRequestBodyBytes, writeEnabled
+	defer func() { maxRequestBodyBytes, writeEnabled = prevLimit, prevWrite }()
+	maxRequestBodyBytes = 16
+	writeEnabled = true
+	mr := useMiniredis(t)
+
+	mux := http.NewServeMux()
+	registerRoutes(mux, nil)
+
+	body := "key=jobs&direction=rpush&value=" + strings.Repeat("x", 32)
+	req := httptest.NewRequest(http.MethodPost, "/push", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if rr.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413 for an oversized body, got %d", rr.Code)
+	}
+	if mr.Exists("jobs") {
+		t.Error("expected the oversized push not to reach Redis")
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/api/prefs", strings.NewReader(`{"rediscan.wrapMode":"`+strings.Repeat("x", 32)+`"}`))
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if rr.Code != http.StatusRequestEntityTooLarge || !strings.Contains(rr.Body.String(), apiErrTooLarge) {
+		t.Errorf("expected a TOO_LARGE API error, got %d %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestCheckRoutePath(t *testing.T) {
+	if err := checkRoutePath("lindex", "/inspect"); err != nil {
+		t.Errorf("expected /inspect to be accepted, got %v", err)
+	}
+	if err := checkRoutePath("lindex", "/tools/lists.view"); err != nil {
+		t.Errorf("expected a nested path to be accepted, got %v", err)
+	}
+	for _, path := range []string{"", "/", "inspect", "/inspect/", "//inspect", "/in spect", "/inspect?x=1", "/{key}", "/peek", "/static/lindex"} {
+		if err := checkRoutePath("lindex", path); err == nil {
+			t.Errorf("expected %q to be rejected", path)
+		}
+	}
+}
+
+func TestRegisterRoutes_LindexRoute(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("jobs:email", "a")
+	saved := lindexRoute
+	lindexRoute = "/inspect"
+	t.Cleanup(func() { lindexRoute = saved })
+
+	mux := http.NewServeMux()
+	registerRoutes(mux, nil)
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/inspect?key=jobs:email", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("expected the inspector at the configured path, got %d", rr.Code)
+	}
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=jobs:email", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected /lindex to be free for something else, got %d", rr.Code)
+	}
+
+	// Generated links follow the configured path
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/peek?pattern=jobs:*", nil))
+	if body := rr.Body.String(); !strings.Contains(body, `href="/inspect?key=`) || strings.Contains(body, "/lindex?") {
+		t.Errorf("expected peek links to use /inspect, got: %s", body)
+	}
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	if body := rr.Body.String(); !strings.Contains(body, `action="/inspect"`) || strings.Contains(body, "/lindex?key=") {
+		t.Errorf("expected the index to link to /inspect")
+	}
+}
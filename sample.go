@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/its-the-vibe/RediScan/inspector"
+)
+
+// maxSampleCount caps the elements one sampled overview reads.
+const maxSampleCount = 1000
+
+// sampleCount is how many elements the sampled overview reads by default
+// (SAMPLE_COUNT).
+var sampleCount = 20
+
+// sampleRow is one sampled element on the overview page.
+type sampleRow struct {
+	inspector.Peek
+	Percent float64 // How far through the list the element is, head to tail
+	Preview string  // The element as shown, a summary or the start of the value
+}
+
+// sampleHandler shows elements spread evenly from the head to the tail of a
+// list, read with one pipeline of LINDEX calls, for an overview of a list too
+// large to load.
+func sampleHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	key := query.Get("key")
+	if key == "" {
+		renderNotFound(w, "Missing 'key' parameter")
+		return
+	}
+	count := sampleCount
+	if countStr := query.Get("count"); countStr != "" {
+		n, err := strconv.Atoi(countStr)
+		if err != nil || n < 1 || n > maxSampleCount {
+			renderBadRequest(w, fmt.Sprintf("Invalid 'count' parameter: expected 1 to %d", maxSampleCount))
+			return
+		}
+		count = n
+	}
+
+	llen, err := inspector.ListLength(ctx, redisClient, key)
+	if err != nil {
+		renderListError(w, key, err)
+		return
+	}
+	peeks, err := inspector.PeekIndices(ctx, redisClient, key, llen, inspector.SampleIndices(llen, count))
+	if err != nil {
+		erroredKeys.record(key, errorKindRedis, err.Error())
+		renderError(w, fmt.Sprintf("Error reading list elements: %v", err))
+		return
+	}
+	rows := make([]sampleRow, len(peeks))
+	for i, peek := range peeks {
+		rows[i] = sampleRow{Peek: peek, Preview: elementPreview(key, peek.Value, peekPreviewLength)}
+		rows[i].Value = inspector.Truncate(peek.Value, peekPreviewLength)
+		if llen > 1 {
+			rows[i].Percent = float64(peek.Index) * 100 / float64(llen-1)
+		}
+	}
+
+	tmplStr := `<!DOCTYPE html>
+<html>
+<head>
+    <title>RediScan - Sample of {{displayKey .Key}}</title>
+    <style>
+        body {
+            font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif;
+            max-width: 1200px;
+            margin: 0 auto;
+            padding: 20px;
+            background-color: #f5f5f5;
+        }
+        h1 a {
+            color: #333;
+            text-decoration: none;
+        }
+        h1 a:hover {
+            text-decoration: underline;
+        }
+        .metadata, .samples {
+            background-color: white;
+            padding: 15px;
+            border-radius: 5px;
+            margin-bottom: 20px;
+            box-shadow: 0 2px 4px rgba(0,0,0,0.1);
+        }
+        .metadata p {
+            margin: 5px 0;
+        }
+        table {
+            width: 100%;
+            border-collapse: collapse;
+            table-layout: fixed;
+        }
+        th, td {
+            text-align: left;
+            padding: 8px;
+            border-bottom: 1px solid #eee;
+            vertical-align: top;
+        }
+        td a {
+            color: #2196F3;
+            text-decoration: none;
+        }
+        td a:hover {
+            text-decoration: underline;
+        }
+        .preview {
+            font-family: monospace;
+            white-space: nowrap;
+            overflow: hidden;
+            text-overflow: ellipsis;
+        }
+        .missing, .empty {
+            color: #666;
+            font-style: italic;
+        }
+        .back-link {
+            display: inline-block;
+            color: #2196F3;
+            text-decoration: none;
+        }
+        .back-link:hover {
+            text-decoration: underline;
+        }
+    </style>
+    {{customCSSLink}}
+</head>
+<body>
+    {{persistenceBanner}}
+    <h1><a href="/">RediScan - Redis List Inspector</a></h1>
+
+    <div class="metadata">
+        <p><strong>Key:</strong> {{displayKey .Key}}</p>
+        <p><strong>Length:</strong> {{.LLen}}</p>
+        <form method="get">
+            <input type="hidden" name="key" value="{{.Key}}">
+            <label for="count"><strong>Sampled:</strong></label>
+            <input type="number" id="count" name="count" min="1" max="{{.MaxCount}}" value="{{.Count}}">
+            elements spread evenly from head to tail
+            <button type="submit">Resample</button>
+        </form>
+    </div>
+
+    <div class="samples">
+        {{if .Samples}}
+        <table>
+            <tr><th style="width: 12%">Index</th><th style="width: 10%">Position</th><th>Element</th></tr>
+            {{range .Samples}}
+            <tr>
+                <td><a href="{{lindexPath}}?key={{$.Key | urlquery}}&amp;index={{.Index}}">{{.Index}}</a></td>
+                <td>{{printf "%.0f" .Percent}}%</td>
+                {{if .Found}}<td class="preview" title="{{.Value}}">{{.Preview}}</td>{{else}}<td class="missing">(no longer available)</td>{{end}}
+            </tr>
+            {{end}}
+        </table>
+        {{else}}
+        <p class="empty">The list is empty.</p>
+        {{end}}
+    </div>
+
+    <a href="{{lindexPath}}?key={{.Key | urlquery}}" class="back-link">← Back to list</a>
+</body>
+</html>`
+
+	tmpl, err := parseTemplate("sample", tmplStr)
+	if err != nil {
+		renderError(w, fmt.Sprintf("Template error: %v", err))
+		return
+	}
+
+	data := struct {
+		Key      string
+		LLen     int64
+		Count    int
+		MaxCount int
+		Samples  []sampleRow
+	}{
+		Key:      key,
+		LLen:     llen,
+		Count:    count,
+		MaxCount: maxSampleCount,
+		Samples:  rows,
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("Error rendering template: %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSampleHandler(t *testing.T) {
+	mr := useMiniredis(t)
+	values := make([]string, 101)
+	for i := range values {
+		values[i] = "element-" + strconv.Itoa(i)
+	}
+	mr.RPush("huge", values...)
+
+	rr := httptest.NewRecorder()
+	sampleHandler(rr, httptest.NewRequest(http.MethodGet, "/sample?key=huge&count=5", nil))
+	body := rr.Body.String()
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	for _, index := range []string{"0", "25", "50", "75", "100"} {
+		if !strings.Contains(body, ">element-"+index+"<") || !strings.Contains(body, `href="/lindex?key=huge&amp;index=`+index+`"`) {
+			t.Errorf("expected element %s sampled and linked, got: %s", index, body)
+		}
+	}
+	if strings.Contains(body, ">element-1<") {
+		t.Errorf("expected only the sampled elements")
+	}
+	if !strings.Contains(body, "<td>25%</td>") {
+		t.Errorf("expected each element's position through the list")
+	}
+}
+
+func TestSampleHandler_DefaultCount(t *testing.T) {
+	mr := useMiniredis(t)
+	for i := 0; i < 100; i++ {
+		mr.RPush("huge", strconv.Itoa(i))
+	}
+
+	rr := httptest.NewRecorder()
+	sampleHandler(rr, httptest.NewRequest(http.MethodGet, "/sample?key=huge", nil))
+	if got := strings.Count(rr.Body.String(), `class="preview"`); got != sampleCount {
+		t.Errorf("expected SAMPLE_COUNT (%d) elements, got %d", sampleCount, got)
+	}
+
+	rr = httptest.NewRecorder()
+	sampleHandler(rr, httptest.NewRequest(http.MethodGet, "/sample?key=huge&count=0", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid count, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	sampleHandler(rr, httptest.NewRequest(http.MethodGet, "/sample?key=missing", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for a missing key, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"io/fs"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// staticRoute is where the assets bundled from the static directory are
+// served from.
+const staticRoute = "/static/"
+
+//go:embed static
+var staticFiles embed.FS
+
+// staticAsset is one bundled file, reachable under its plain name and a name
+// carrying a hash of its contents (prefs.js and prefs.1a2b3c4d.js).
+type staticAsset struct {
+	Name    string
+	Hashed  string
+	Content []byte
+	ETag    string
+}
+
+// staticAssets maps both names of every bundled file to it.
+var staticAssets = loadStaticAssets(staticFiles)
+
+// loadStaticAssets indexes the files under static/ in files by plain and
+// hashed name.
+func loadStaticAssets(files fs.FS) map[string]*staticAsset {
+	assets := make(map[string]*staticAsset)
+	err := fs.WalkDir(files, "static", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := fs.ReadFile(files, name)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(content)
+		hash := hex.EncodeToString(sum[:4])
+		plain := strings.TrimPrefix(name, "static/")
+		ext := path.Ext(plain)
+		asset := &staticAsset{
+			Name:    plain,
+			Hashed:  strings.TrimSuffix(plain, ext) + "." + hash + ext,
+			Content: content,
+			ETag:    `"` + hash + `"`,
+		}
+		assets[asset.Name] = asset
+		assets[asset.Hashed] = asset
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("Error loading static assets: %v", err)
+	}
+	return assets
+}
+
+// staticURL returns the URL templates use for the bundled file name. The
+// hashed name changes whenever the file does, so browsers can keep it
+// forever and still pick up a new version after a deploy.
+func staticURL(name string) string {
+	if asset, ok := staticAssets[name]; ok {
+		return staticRoute + asset.Hashed
+	}
+	return staticRoute + name
+}
+
+func staticHandler(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, staticRoute)
+	asset, ok := staticAssets[name]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("ETag", asset.ETag)
+	if name == asset.Hashed {
+		setImmutableCacheHeaders(w)
+	} else {
+		setStaticCacheHeaders(w)
+	}
+	http.ServeContent(w, r, asset.Name, time.Time{}, bytes.NewReader(asset.Content))
+}
@@ -0,0 +1,24 @@
+// Tracks user activity so live pages can stop polling in forgotten tabs.
+// idleTracker(timeout) returns an object whose idle() is true once timeout
+// milliseconds pass without a key press, click, scroll, touch or pointer
+// movement. A timeout of 0 never goes idle.
+function idleTracker(timeout) {
+    let last = Date.now();
+    const touch = function() {
+        last = Date.now();
+    };
+    ['mousemove', 'keydown', 'click', 'scroll', 'wheel', 'touchstart'].forEach(function(type) {
+        window.addEventListener(type, touch, {passive: true, capture: true});
+    });
+    return {
+        idle: function() {
+            return timeout > 0 && Date.now() - last >= timeout;
+        },
+        reset: touch,
+        // How long the page waits, for telling the user why polling stopped
+        describe: function() {
+            const minutes = Math.round(timeout / 60000);
+            return minutes >= 1 ? minutes + ' minute' + (minutes === 1 ? '' : 's') : Math.round(timeout / 1000) + ' seconds';
+        }
+    };
+}
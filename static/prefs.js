@@ -0,0 +1,39 @@
+// Keeps the browser's rediscan.* settings in step with the signed-in user's
+// preferences stored by /api/prefs. Loaded in <head> when PREFS_ENABLED is set.
+(function() {
+    function localPrefs() {
+        const prefs = {};
+        for (let i = 0; i < localStorage.length; i++) {
+            const name = localStorage.key(i);
+            if (name.startsWith('rediscan.')) {
+                prefs[name] = localStorage.getItem(name);
+            }
+        }
+        return prefs;
+    }
+    const setItem = localStorage.setItem.bind(localStorage);
+    localStorage.setItem = function(name, value) {
+        setItem(name, value);
+        if (name.startsWith('rediscan.')) {
+            fetch('/api/prefs', {method: 'PUT', body: JSON.stringify(localPrefs())});
+        }
+    };
+    fetch('/api/prefs').then(function(response) {
+        return response.ok ? response.json() : null;
+    }).then(function(body) {
+        if (!body) {
+            return;
+        }
+        let changed = false;
+        Object.keys(body.prefs).forEach(function(name) {
+            if (localStorage.getItem(name) !== body.prefs[name]) {
+                setItem(name, body.prefs[name]);
+                changed = true;
+            }
+        });
+        if (changed && !sessionStorage.getItem('rediscan.prefsApplied')) {
+            sessionStorage.setItem('rediscan.prefsApplied', '1');
+            window.location.reload();
+        }
+    });
+})();
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadStaticAssets(t *testing.T) {
+	assets := loadStaticAssets(fstest.MapFS{
+		"static/app.js":       {Data: []byte("one")},
+		"static/css/site.css": {Data: []byte("two")},
+	})
+	asset := assets["app.js"]
+	if asset == nil || !strings.HasPrefix(asset.Hashed, "app.") || !strings.HasSuffix(asset.Hashed, ".js") || asset.Hashed == "app.js" {
+		t.Fatalf("expected app.js with a hashed name, got %+v", asset)
+	}
+	if assets[asset.Hashed] != asset {
+		t.Error("expected the hashed name to serve the same asset")
+	}
+	if assets["css/site.css"] == nil {
+		t.Error("expected files in subdirectories to be bundled")
+	}
+
+	changed := loadStaticAssets(fstest.MapFS{"static/app.js": {Data: []byte("three")}})
+	if changed["app.js"].Hashed == asset.Hashed {
+		t.Error("expected new contents to get a new hashed name")
+	}
+}
+
+func TestStaticHandler(t *testing.T) {
+	url := staticURL("prefs.js")
+	if url == "/static/prefs.js" || !strings.HasSuffix(url, ".js") {
+		t.Fatalf("expected a hashed URL for prefs.js, got %q", url)
+	}
+
+	handler := noStoreHandler(http.HandlerFunc(staticHandler))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, url, nil))
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "/api/prefs") {
+		t.Fatalf("expected the bundled script, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/javascript") {
+		t.Errorf("expected a JavaScript content type, got %q", ct)
+	}
+	if got := rr.Header().Get("Cache-Control"); got != "public, max-age=31536000, immutable" {
+		t.Errorf("expected the hashed name to be cached for good, got %q", got)
+	}
+
+	// The plain name stays reachable, cached only for STATIC_CACHE_MAX_AGE
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/static/prefs.js", nil))
+	if got := rr.Header().Get("Cache-Control"); rr.Code != http.StatusOK || got != "public, max-age=3600" {
+		t.Errorf("expected the plain name to be served with the usual max-age, got %d %q", rr.Code, got)
+	}
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/static/missing.js", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown asset, got %d", rr.Code)
+	}
+}
+
+func TestPrefsScript_LinksStaticAsset(t *testing.T) {
+	prev := prefsEnabled
+	prefsEnabled = true
+	defer func() { prefsEnabled = prev }()
+
+	if got := string(prefsScript()); got != `<script src="`+staticURL("prefs.js")+`"></script>` {
+		t.Errorf("expected a script tag for the hashed asset, got %s", got)
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/its-the-vibe/RediScan/inspector"
+)
+
+// streamRecentEntries is how many of the newest entries the stream page shows.
+const streamRecentEntries = 20
+
+// streamIDTime renders the time encoded in a stream entry ID in the display
+// timezone, or "" if the ID does not encode one.
+func streamIDTime(id string) string {
+	t, ok := inspector.StreamIDTime(id)
+	if !ok {
+		return ""
+	}
+	return formatTime(t)
+}
+
+// streamHandler shows a stream's consumer groups, their pending entries and
+// consumers, for debugging stuck consumers and a growing PEL.
+func streamHandler(w http.ResponseWriter, r *http.Request) {
+	key := r.URL.Query().Get("key")
+	if key == "" {
+		renderNotFound(w, "Missing 'key' parameter")
+		return
+	}
+
+	info, err := inspector.InspectStream(ctx, redisClient, key)
+	var wrongType *inspector.WrongTypeError
+	switch {
+	case errors.Is(err, inspector.ErrKeyNotFound):
+		renderNotFound(w, fmt.Sprintf("Key '%s' does not exist", displayKey(key)))
+		return
+	case errors.As(err, &wrongType):
+		renderNotFound(w, fmt.Sprintf("Key '%s' is not a stream (type: %s)", displayKey(key), wrongType.Type))
+		return
+	case err != nil:
+		erroredKeys.record(key, errorKindRedis, err.Error())
+		renderError(w, "Error "+err.Error())
+		return
+	}
+	entries, err := inspector.RecentStreamEntries(ctx, redisClient, key, streamRecentEntries)
+	if err != nil {
+		erroredKeys.record(key, errorKindRedis, err.Error())
+		renderError(w, "Error "+err.Error())
+		return
+	}
+
+	tmplStr := `<!DOCTYPE html>
+<html>
+<head>
+    <title>Stream {{displayKey .Key}} - RediScan</title>
+    <style>
+        body {
+            font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif;
+            max-width: 1200px;
+            margin: 0 auto;
+            padding: 20px;
+            background-color: #f5f5f5;
+        }
+        h1 a {
+            color: #333;
+            text-decoration: none;
+        }
+        h1 a:hover {
+            text-decoration: underline;
+        }
+        .panel {
+            background-color: white;
+            padding: 20px;
+            border-radius: 5px;
+            box-shadow: 0 2px 4px rgba(0,0,0,0.1);
+            margin-bottom: 20px;
+        }
+        .panel h2 {
+            margin-top: 0;
+            color: #333;
+            word-break: break-all;
+        }
+        table {
+            width: 100%;
+            border-collapse: collapse;
+            margin-top: 10px;
+        }
+        th, td {
+            text-align: left;
+            padding: 8px;
+            border-bottom: 1px solid #eee;
+        }
+        td.number {
+            font-family: monospace;
+        }
+        .pending {
+            color: #c62828;
+            font-weight: bold;
+        }
+        .empty {
+            color: #666;
+            font-style: italic;
+        }
+        .id-time {
+            color: #666;
+            font-size: 0.9em;
+            margin-left: 6px;
+        }
+        .fields {
+            font-family: monospace;
+            word-break: break-all;
+        }
+        .back-link {
+            color: #2196F3;
+            text-decoration: none;
+        }
+    </style>
+    {{customCSSLink}}
+</head>
+<body>
+    {{persistenceBanner}}
+    <h1><a href="/">RediScan - Redis List Inspector</a></h1>
+    <div class="panel">
+        <h2>Stream {{displayKey .Key}}</h2>
+        <p><strong>Length:</strong> {{.Info.Length}} entries</p>
+        <p><strong>Consumer groups:</strong> {{len .Info.Groups}}</p>
+    </div>
+    {{range .Info.Groups}}
+    <div class="panel">
+        <h2>Group {{.Name}}</h2>
+        <p><strong>Last delivered ID:</strong> <code>{{.LastDeliveredID}}</code>{{with streamIDTime .LastDeliveredID}}<span class="id-time">{{.}}</span>{{end}}</p>
+        <p><strong>Pending (unacknowledged):</strong> <span{{if .Pending}} class="pending"{{end}}>{{.Pending}}</span></p>
+        <p><strong>Entries read:</strong> {{.EntriesRead}}</p>
+        <p><strong>Lag:</strong> {{if lt .Lag 0}}unknown{{else}}{{.Lag}}{{end}}</p>
+        {{if .Consumers}}
+        <table>
+            <thead><tr><th>Consumer</th><th>Pending</th><th>Idle</th></tr></thead>
+            <tbody>
+            {{range .Consumers}}
+                <tr><td>{{.Name}}</td><td class="number{{if .Pending}} pending{{end}}">{{.Pending}}</td><td class="number">{{.Idle}}</td></tr>
+            {{end}}
+            </tbody>
+        </table>
+        {{else}}
+        <p class="empty">No consumers have joined this group.</p>
+        {{end}}
+    </div>
+    {{else}}
+    <div class="panel"><p class="empty">This stream has no consumer groups.</p></div>
+    {{end}}
+    <div class="panel">
+        <h2>Newest entries</h2>
+        {{if .Entries}}
+        <table>
+            <thead><tr><th>ID</th><th>Added</th><th>Fields</th></tr></thead>
+            <tbody>
+            {{range .Entries}}
+                <tr><td class="number">{{.ID}}</td><td>{{streamIDTime .ID}}</td><td class="fields">{{range $field, $value := .Fields}}{{$field}}={{$value}} {{end}}</td></tr>
+            {{end}}
+            </tbody>
+        </table>
+        {{else}}
+        <p class="empty">This stream has no entries.</p>
+        {{end}}
+    </div>
+    <a href="/" class="back-link">← Back to Home</a>
+</body>
+</html>`
+
+	tmpl, err := parseTemplate("stream", tmplStr)
+	if err != nil {
+		renderError(w, fmt.Sprintf("Template error: %v", err))
+		return
+	}
+
+	data := struct {
+		Key     string
+		Info    *inspector.StreamInfo
+		Entries []inspector.StreamEntry
+	}{
+		Key:     key,
+		Info:    info,
+		Entries: entries,
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("Error rendering template: %v", err)
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestStreamHandler(t *testing.T) {
+	mr := useMiniredis(t)
+	redisClient.XAdd(ctx, &redis.XAddArgs{Stream: "events", ID: "1700000000000-0", Values: []string{"type", "signup"}})
+	redisClient.XGroupCreate(ctx, "events", "mailers", "0")
+	redisClient.XReadGroup(ctx, &redis.XReadGroupArgs{Group: "mailers", Consumer: "mailer-1", Streams: []string{"events", ">"}})
+	redisClient.XAdd(ctx, &redis.XAddArgs{Stream: "events", Values: []string{"type", "login"}})
+
+	rr := httptest.NewRecorder()
+	streamHandler(rr, httptest.NewRequest(http.MethodGet, "/stream?key=events", nil))
+	body := rr.Body.String()
+	for _, want := range []string{"Group mailers", "mailer-1", "<strong>Length:</strong> 2 entries", "<code>1700000000000-0</code><span class=\"id-time\">2023-11-14 22:13:20 UTC</span>", "type=login"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q on stream page", want)
+		}
+	}
+
+	mr.RPush("jobs", "a")
+	rr = httptest.NewRecorder()
+	streamHandler(rr, httptest.NewRequest(http.MethodGet, "/stream?key=jobs", nil))
+	if rr.Code != http.StatusNotFound || !strings.Contains(rr.Body.String(), "is not a stream") {
+		t.Errorf("expected 404 for a list key, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"log"
+	"mime"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// testConnTimeout bounds each connection test, from dialing to the PING reply.
+const testConnTimeout = 3 * time.Second
+
+// testConnEnabled allows /api/testconn to connect to Redis servers given in
+// the request (TESTCONN_ENABLED). It is off by default, as the endpoint opens
+// connections to any address; it also needs ADMIN_PASSWORD.
+var testConnEnabled bool
+
+// testConnParams is the JSON body of a connection test: a redis:// or
+// rediss:// URL, or an address with optional credentials, database and TLS.
+type testConnParams struct {
+	URL      string `json:"url"`
+	Addr     string `json:"addr"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	DB       int    `json:"db"`
+	TLS      bool   `json:"tls"`
+}
+
+// testConnOptions builds client options from the request's JSON body. Only
+// a body is read, so a password never appears in a URL that proxies log, and
+// errors never quote the parameters.
+func testConnOptions(r *http.Request) (*redis.Options, error) {
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		return nil, errors.New("Expected a JSON body with Content-Type: application/json")
+	}
+	var params testConnParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		return nil, errors.New("Invalid JSON body: expected an object with url, or addr and optional username, password, db and tls")
+	}
+
+	if params.URL != "" {
+		opts, err := redis.ParseURL(params.URL)
+		if err != nil {
+			return nil, errors.New("Invalid 'url' parameter: expected redis://[user:password@]host:port/db or rediss:// for TLS")
+		}
+		return opts, nil
+	}
+
+	if params.Addr == "" {
+		return nil, errors.New("Missing 'url' or 'addr' parameter")
+	}
+	host, _, err := net.SplitHostPort(params.Addr)
+	if err != nil {
+		return nil, errors.New("Invalid 'addr' parameter: expected host:port")
+	}
+	if params.DB < 0 {
+		return nil, errors.New("Invalid 'db' parameter: expected a database number")
+	}
+	opts := &redis.Options{
+		Addr:     params.Addr,
+		Username: params.Username,
+		Password: params.Password,
+		DB:       params.DB,
+	}
+	if params.TLS {
+		opts.TLSConfig = &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}
+	}
+	return opts, nil
+}
+
+// testConnAPIHandler tries a PING against the Redis server described by the
+// request, using a throwaway client so the active connection is untouched,
+// and reports whether it answered and how long it took.
+func testConnAPIHandler(w http.ResponseWriter, r *http.Request) {
+	if !refuseCrossOrigin(w, r) {
+		return
+	}
+	if !testConnEnabled {
+		writeAPIError(w, http.StatusForbidden, apiErrForbidden, "Connection tests are disabled; set TESTCONN_ENABLED=true to enable them")
+		return
+	}
+
+	opts, err := testConnOptions(r)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, apiErrInvalidParameter, err.Error())
+		return
+	}
+	// One attempt on one connection, given up quickly
+	opts.MaxRetries = -1
+	opts.PoolSize = 1
+	opts.DialTimeout = testConnTimeout
+	opts.ReadTimeout = testConnTimeout
+	opts.WriteTimeout = testConnTimeout
+	client := redis.NewClient(opts)
+	defer client.Close()
+
+	reqCtx, cancel := context.WithTimeout(r.Context(), testConnTimeout)
+	defer cancel()
+	start := time.Now()
+	err = client.Ping(reqCtx).Err()
+	latency := time.Since(start)
+
+	body := map[string]interface{}{
+		"addr":       opts.Addr,
+		"db":         opts.DB,
+		"tls":        opts.TLSConfig != nil,
+		"ok":         err == nil,
+		"latency_ms": float64(latency.Microseconds()) / 1000,
+	}
+	if err != nil {
+		body["reason"] = err.Error()
+		log.Printf("Connection test to %s failed after %v: %v", opts.Addr, latency.Round(time.Millisecond), err)
+	}
+	writeJSON(w, http.StatusOK, body)
+}
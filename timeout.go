@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// maxRequestTimeout is the longest Redis timeout a single request may ask for
+// with ?timeout=, configured by REDIS_MAX_TIMEOUT.
+var maxRequestTimeout = time.Minute
+
+// requestRedis returns the Redis client and context to use for a request. A
+// timeout query parameter (e.g. timeout=30s) overrides the client's read and
+// write timeouts for this request only, so a known-slow LRANGE can be given
+// longer. A timeout above maxRequestTimeout is ignored, leaving the client's
+// own timeouts in place. The returned cancel func must be
+// called once the request is done. A cluster client cannot be copied with new
+// timeouts, so there the timeout only bounds the request's context.
+func requestRedis(r *http.Request, timeoutStr string) (redis.UniversalClient, context.Context, context.CancelFunc, error) {
+	if timeoutStr == "" {
+		return redisClient, ctx, func() {}, nil
+	}
+	timeout, err := time.ParseDuration(timeoutStr)
+	if err != nil || timeout <= 0 {
+		return nil, nil, nil, fmt.Errorf("Invalid 'timeout' parameter %q: expected a positive duration such as 30s", timeoutStr)
+	}
+	if timeout > maxRequestTimeout {
+		debugf("Ignoring requested timeout %s above REDIS_MAX_TIMEOUT %s", timeout, maxRequestTimeout)
+		return redisClient, ctx, func() {}, nil
+	}
+	reqCtx, cancel := context.WithTimeout(r.Context(), timeout)
+	if client, ok := redisClient.(*redis.Client); ok {
+		return client.WithTimeout(timeout), reqCtx, cancel, nil
+	}
+	return redisClient, reqCtx, cancel, nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestRequestRedis_Timeout(t *testing.T) {
+	useMiniredis(t)
+	prev := maxRequestTimeout
+	defer func() { maxRequestTimeout = prev }()
+	maxRequestTimeout = 10 * time.Second
+
+	r := httptest.NewRequest(http.MethodGet, "/lindex", nil)
+	client, _, cancel, err := requestRedis(r, "")
+	if err != nil || client != redisClient {
+		t.Fatalf("expected the shared client without a timeout, got %v", err)
+	}
+	cancel()
+
+	client, reqCtx, cancel, err := requestRedis(r, "5s")
+	if err != nil {
+		t.Fatalf("requestRedis: %v", err)
+	}
+	defer cancel()
+	if got := client.(*redis.Client).Options().ReadTimeout; got != 5*time.Second {
+		t.Errorf("ReadTimeout = %s, want 5s", got)
+	}
+	if _, ok := reqCtx.Deadline(); !ok {
+		t.Error("expected the request context to have a deadline")
+	}
+
+	client, _, cancel, err = requestRedis(r, "5m")
+	defer cancel()
+	if err != nil || client != redisClient {
+		t.Errorf("expected a timeout above the maximum to be ignored, got %v", err)
+	}
+}
+
+func TestLindexHandler_Timeout(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("jobs", "a", "b")
+
+	rr := httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=jobs&timeout=soon", nil))
+	if rr.Code != http.StatusBadRequest || !strings.Contains(rr.Body.String(), "Invalid &#39;timeout&#39; parameter") {
+		t.Errorf("expected 400 for an invalid timeout, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=jobs&timeout=30s", nil))
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), `<pre id="valueDisplay">b</pre>`) {
+		t.Errorf("expected the list to load with a timeout override, got %d", rr.Code)
+	}
+}
+
+func TestRequestRedis_TimeoutCluster(t *testing.T) {
+	prev := redisClient
+	redisClient = redis.NewClusterClient(&redis.ClusterOptions{Addrs: []string{"127.0.0.1:1"}})
+	defer func() {
+		redisClient.Close()
+		redisClient = prev
+	}()
+
+	client, reqCtx, cancel, err := requestRedis(httptest.NewRequest(http.MethodGet, "/lindex", nil), "5s")
+	if err != nil {
+		t.Fatalf("requestRedis: %v", err)
+	}
+	defer cancel()
+	if client != redisClient {
+		t.Error("expected the shared cluster client")
+	}
+	if _, ok := reqCtx.Deadline(); !ok {
+		t.Error("expected the request context to have a deadline")
+	}
+}
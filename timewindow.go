@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/its-the-vibe/RediScan/inspector"
+)
+
+const (
+	// defaultTimestampField is the element field /api/time-window reads when
+	// neither the request nor TIMESTAMP_FIELDS names one.
+	defaultTimestampField = "ts"
+	// timeWindowMaxIndices caps the indices one /api/time-window reply lists.
+	timeWindowMaxIndices = 10000
+)
+
+// timestampRules name the timestamp field of lists used as streams
+// (TIMESTAMP_FIELDS).
+var timestampRules []inspector.TimestampRule
+
+// parseWindowTime reads one end of a time window: "now", a duration before
+// now such as -15m, an RFC 3339 time, a date (midnight in DISPLAY_TIMEZONE)
+// or a Unix time in seconds or finer units. An empty string is an open end,
+// returned as the zero time.
+func parseWindowTime(s string, now time.Time) (time.Time, error) {
+	switch {
+	case s == "":
+		return time.Time{}, nil
+	case s == "now":
+		return now, nil
+	case strings.HasPrefix(s, "-"):
+		if d, err := time.ParseDuration(s); err == nil {
+			return now.Add(d), nil
+		}
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, displayLocation); err == nil {
+		return t, nil
+	}
+	if t, ok := inspector.ParseTimestamp(s); ok {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: use now, a duration ago such as -15m, an RFC 3339 time, a date or a Unix time", s)
+}
+
+// windowTimeJSON renders one end of a window for a reply, nil when open.
+func windowTimeJSON(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// timeWindowAPIHandler returns the indices of the elements of a list whose
+// timestamp field falls between from and to, for lists of
+// {"ts": ..., ...} records used as a stream.
+func timeWindowAPIHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	key := query.Get("key")
+	if key == "" {
+		writeAPIError(w, http.StatusBadRequest, apiErrInvalidParameter, "missing 'key' parameter")
+		return
+	}
+	now := time.Now()
+	from, err := parseWindowTime(query.Get("from"), now)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, apiErrInvalidParameter, err.Error())
+		return
+	}
+	to, err := parseWindowTime(query.Get("to"), now)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, apiErrInvalidParameter, err.Error())
+		return
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		writeAPIError(w, http.StatusBadRequest, apiErrInvalidParameter, "'to' is before 'from'")
+		return
+	}
+
+	// The field comes from the request, else the list's TIMESTAMP_FIELDS rule
+	field := query.Get("field")
+	if field == "" {
+		field = defaultTimestampField
+		if rule := inspector.MatchTimestamp(timestampRules, key); rule != nil {
+			field = rule.Field
+		}
+	}
+
+	if _, err := inspector.ListLength(ctx, redisClient, key); err != nil {
+		writeAPIListError(w, key, err)
+		return
+	}
+	result, err := inspector.TimeWindow(ctx, redisClient, key, field, from, to, timeWindowMaxIndices)
+	if err != nil {
+		log.Printf("Error filtering %q by time: %v", key, err)
+		writeAPIError(w, http.StatusInternalServerError, apiErrInternal, err.Error())
+		return
+	}
+	indices := result.Indices
+	if indices == nil {
+		indices = []int64{}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"key":       key,
+		"field":     field,
+		"from":      windowTimeJSON(from),
+		"to":        windowTimeJSON(to),
+		"indices":   indices,
+		"matched":   result.Matched,
+		"scanned":   result.Scanned,
+		"truncated": result.Matched > int64(len(result.Indices)),
+	})
+}
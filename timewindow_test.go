@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/its-the-vibe/RediScan/inspector"
+)
+
+func TestParseWindowTime(t *testing.T) {
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	tests := map[string]time.Time{
+		"":                     {},
+		"now":                  now,
+		"-15m":                 now.Add(-15 * time.Minute),
+		"2024-01-02T03:04:05Z": time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		"2024-01-01":           time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		"1704164645":           time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		"1704164645000":        time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	for input, want := range tests {
+		if got, err := parseWindowTime(input, now); err != nil || !got.Equal(want) {
+			t.Errorf("parseWindowTime(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"yesterday", "-soon", "15m"} {
+		if _, err := parseWindowTime(input, now); err == nil {
+			t.Errorf("parseWindowTime(%q): expected an error", input)
+		}
+	}
+}
+
+func TestTimeWindowAPIHandler(t *testing.T) {
+	mr := useMiniredis(t)
+	prev := timestampRules
+	defer func() { timestampRules = prev }()
+	timestampRules, _ = inspector.ParseTimestampRules(`[{"pattern": "events", "field": "at"}]`)
+	mr.RPush("logs", `{"ts": 1000}`, `{"ts": "1970-01-01T00:33:20Z"}`, `{"msg": "no ts"}`, `{"ts": 3000}`)
+	mr.RPush("events", `{"at": 1000}`, `{"ts": 1000}`)
+
+	window := func(query string) (int, map[string]interface{}) {
+		rr := httptest.NewRecorder()
+		timeWindowAPIHandler(rr, httptest.NewRequest(http.MethodGet, "/api/time-window?"+query, nil))
+		var body map[string]interface{}
+		json.Unmarshal(rr.Body.Bytes(), &body)
+		return rr.Code, body
+	}
+
+	code, body := window("key=logs&from=1500&to=2500")
+	if code != http.StatusOK || !reflect.DeepEqual(body["indices"], []interface{}{float64(1)}) || body["field"] != "ts" {
+		t.Errorf("expected the element at 2000s, got %d %v", code, body)
+	}
+	if code, body = window("key=logs&from=1500"); !reflect.DeepEqual(body["indices"], []interface{}{float64(1), float64(3)}) || body["to"] != nil {
+		t.Errorf("expected an open end, got %d %v", code, body)
+	}
+	if code, body = window("key=events&to=1500"); !reflect.DeepEqual(body["indices"], []interface{}{float64(0)}) || body["field"] != "at" {
+		t.Errorf("expected the configured field for events, got %d %v", code, body)
+	}
+	if code, body = window("key=logs&from=5000"); code != http.StatusOK || !reflect.DeepEqual(body["indices"], []interface{}{}) {
+		t.Errorf("expected an empty window, got %d %v", code, body)
+	}
+
+	for _, query := range []string{"", "key=logs&from=yesterday", "key=logs&from=3000&to=1000"} {
+		if code, _ := window(query); code != http.StatusBadRequest {
+			t.Errorf("%q: expected 400, got %d", query, code)
+		}
+	}
+	if code, _ := window("key=missing"); code != http.StatusNotFound {
+		t.Errorf("expected 404 for a missing key, got %d", code)
+	}
+}
@@ -0,0 +1,14 @@
+package main
+
+import (
+	"time"
+	_ "time/tzdata" // The scratch image has no zoneinfo, so embed it for DISPLAY_TIMEZONE
+)
+
+// displayLocation is the timezone rendered timestamps are shown in.
+var displayLocation = time.UTC
+
+// formatTime renders t in the configured display timezone.
+func formatTime(t time.Time) string {
+	return t.In(displayLocation).Format("2006-01-02 15:04:05 MST")
+}
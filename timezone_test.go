@@ -0,0 +1,25 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatTime(t *testing.T) {
+	prev := displayLocation
+	defer func() { displayLocation = prev }()
+
+	instant := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+	if got := formatTime(instant); got != "2024-07-01 12:00:00 UTC" {
+		t.Errorf("expected UTC by default, got %q", got)
+	}
+
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	displayLocation = loc
+	if got := formatTime(instant); got != "2024-07-01 08:00:00 EDT" {
+		t.Errorf("expected time in New York, got %q", got)
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/its-the-vibe/RediScan/inspector"
+)
+
+// transformRules holds the pipelines configured by VALUE_TRANSFORMS.
+var transformRules []inspector.TransformRule
+
+// renderValue produces the display form of a list element for key, applying
+// the VALUE_FILTER_CMD filter and any configured transform pipeline and
+// falling back to JSON pretty-printing. A failed filter or pipeline reports
+// the error above the untouched raw value.
+func renderValue(key, value string) string {
+	return renderValueWith(key, value, nil, nil)
+}
+
+// renderValueWith is renderValue followed by the extra stages chosen for the
+// current view, such as unquote to peel a layer of encoding. The result of
+// extra stages is pretty-printed if it is JSON. If they fail, the error is
+// reported above the value as it was before them; unlike configured
+// pipelines, this is not recorded in the errored-keys report. Filter runs
+// count against run's budget, if any.
+func renderValueWith(key, value string, extra []string, run *filterRun) string {
+	if valueFilterArgs != nil {
+		filtered, err := filterValue(value, run)
+		if err != nil {
+			if !errors.Is(err, errFilterBudgetSpent) {
+				erroredKeys.record(key, errorKindTransform, "VALUE_FILTER_CMD: "+err.Error())
+			}
+			return fmt.Sprintf("[Filter %s: %v]\n\n%s", valueFilterArgs[0], err, value)
+		}
+		value = filtered
+	}
+
+	stages := inspector.MatchTransforms(transformRules, key)
+	if stages == nil && len(extra) == 0 {
+		return prettyPrintJSON(value)
+	}
+
+	out := value
+	if stages != nil {
+		var err error
+		if out, err = inspector.ApplyTransforms(value, stages); err != nil {
+			erroredKeys.record(key, errorKindTransform, err.Error())
+			return fmt.Sprintf("[Transform %s: %v]\n\n%s", strings.Join(stages, " → "), err, value)
+		}
+	}
+	if len(extra) == 0 {
+		return out
+	}
+
+	peeled, err := inspector.ApplyTransforms(out, extra)
+	if err != nil {
+		return fmt.Sprintf("[Transform %s: %v]\n\n%s", strings.Join(extra, " → "), err, out)
+	}
+	return prettyPrintJSON(peeled)
+}
+
+// parseViewTransforms parses the comma-separated transform query parameter,
+// rejecting unknown stage names.
+func parseViewTransforms(param string) ([]string, error) {
+	if param == "" {
+		return nil, nil
+	}
+	stages := strings.Split(param, ",")
+	for _, name := range stages {
+		if _, ok := inspector.Transforms[name]; !ok {
+			return nil, fmt.Errorf("Unknown transform %q", name)
+		}
+	}
+	return stages, nil
+}
+
+// transformNames lists the available transform stages in alphabetical order.
+func transformNames() []string {
+	names := make([]string, 0, len(inspector.Transforms))
+	for name := range inspector.Transforms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/its-the-vibe/RediScan/inspector"
+)
+
+func TestRenderValue_UsesMatchingPipeline(t *testing.T) {
+	prev := transformRules
+	defer func() { transformRules = prev }()
+	rules, err := inspector.ParseTransformRules(`[{"pattern":"b64:*","transforms":["base64"]}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transformRules = rules
+
+	encoded := base64.StdEncoding.EncodeToString([]byte("plain"))
+	if got := renderValue("b64:one", encoded); got != "plain" {
+		t.Errorf("expected decoded value, got %q", got)
+	}
+	if got := renderValue("other", encoded); got != encoded {
+		t.Errorf("expected untouched value for unmatched key, got %q", got)
+	}
+	if got := renderValue("b64:bad", "!!!"); !strings.Contains(got, "stage 1 (base64)") || !strings.HasSuffix(got, "!!!") {
+		t.Errorf("expected error notice followed by raw value, got %q", got)
+	}
+}
+
+func TestRenderValueWith_ViewTransforms(t *testing.T) {
+	prev := erroredKeys
+	defer func() { erroredKeys = prev }()
+	erroredKeys = newErrorReport(10)
+
+	doubleEncoded := `"{\"id\":1}"`
+	if got := renderValueWith("any", doubleEncoded, []string{"unquote"}, nil); got != "{\n  \"id\": 1\n}" {
+		t.Errorf("expected unquoted, pretty-printed JSON, got %q", got)
+	}
+
+	got := renderValueWith("any", "plain", []string{"unquote"}, nil)
+	if !strings.Contains(got, "[Transform unquote:") || !strings.HasSuffix(got, "plain") {
+		t.Errorf("expected error notice followed by the untouched value, got %q", got)
+	}
+	if len(erroredKeys.snapshot()) != 0 {
+		t.Errorf("expected view transform failures not to be recorded")
+	}
+}
+
+func TestLindexHandler_ViewTransforms(t *testing.T) {
+	mr := useMiniredis(t)
+	mr.RPush("encoded", "%7B%22id%22%3A1%7D")
+
+	rr := httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=encoded&transform=urldecode", nil))
+	if !strings.Contains(rr.Body.String(), "&#34;id&#34;: 1") {
+		t.Errorf("expected the URL-decoded value, got: %s", rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=encoded&transform=rot13", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an unknown transform, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// valueStreamChunk is how much of a value /api/value-stream writes before
+// flushing it to the client.
+const valueStreamChunk = 64 << 10
+
+// maxValueBytes is the largest element shown in full on the result page, set
+// by MAX_VALUE_BYTES (0 for unlimited). Larger elements are shown raw and cut
+// short, and the rest is loaded from /api/value-stream on request.
+var maxValueBytes = 1 << 20
+
+// valueCut records that an element on the result page was cut short.
+type valueCut struct {
+	Shown int `json:"shown"` // Bytes of the value shown
+	Size  int `json:"size"`  // Bytes of the whole value
+}
+
+// valueStats measures a stored element for the result page metadata.
+type valueStats struct {
+	Bytes int `json:"bytes"`
+	Chars int `json:"chars"` // UTF-8 characters, counting each invalid byte as one
+	Lines int `json:"lines"` // Lines of text, not counting a final newline; 0 when empty
+}
+
+// measureValue returns the size of value in bytes, characters and lines.
+func measureValue(value string) valueStats {
+	stats := valueStats{Bytes: len(value), Chars: utf8.RuneCountInString(value)}
+	if value != "" {
+		stats.Lines = strings.Count(strings.TrimSuffix(value, "\n"), "\n") + 1
+	}
+	return stats
+}
+
+// cutValue returns value cut to maxValueBytes, on a UTF-8 character boundary,
+// and a record of the cut. Values within the limit are returned whole with a
+// nil cut.
+func cutValue(value string) (string, *valueCut) {
+	if maxValueBytes <= 0 || len(value) <= maxValueBytes {
+		return value, nil
+	}
+	shown := maxValueBytes
+	for shown > 0 && !utf8.RuneStart(value[shown]) {
+		shown--
+	}
+	return value[:shown], &valueCut{Shown: shown, Size: len(value)}
+}
+
+// valueStreamHandler writes the raw bytes of the element at key[index] from
+// offset, at most length bytes of it if length is given, in chunks so the
+// client can show a very large element progressively. Redis has no partial
+// read for list elements, so the element is still read whole, but it is
+// never pretty-printed or embedded in a page. X-Value-Length carries the size
+// of the whole element.
+func valueStreamHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var offset, length int
+	for _, param := range []struct {
+		name string
+		dest *int
+	}{{"offset", &offset}, {"length", &length}} {
+		if s := query.Get(param.name); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 0 {
+				writeAPIError(w, http.StatusBadRequest, apiErrInvalidParameter, fmt.Sprintf("%s must be a non-negative integer", param.name))
+				return
+			}
+			*param.dest = n
+		}
+	}
+
+	_, _, value, ok := readAPIElement(w, query)
+	if !ok {
+		return
+	}
+	if offset > len(value) {
+		writeAPIError(w, http.StatusBadRequest, apiErrInvalidParameter, fmt.Sprintf("offset %d is past the end of the value (%d bytes)", offset, len(value)))
+		return
+	}
+	end := len(value)
+	if length > 0 && length < end-offset {
+		end = offset + length
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("X-Value-Length", strconv.Itoa(len(value)))
+	w.Header().Set("Content-Length", strconv.Itoa(end-offset))
+	if r.Method == http.MethodHead {
+		return
+	}
+	rc := http.NewResponseController(w)
+	for start := offset; start < end; start += valueStreamChunk {
+		stop := min(start+valueStreamChunk, end)
+		if _, err := w.Write([]byte(value[start:stop])); err != nil {
+			// The client went away
+			return
+		}
+		rc.Flush()
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCutValue(t *testing.T) {
+	prev := maxValueBytes
+	defer func() { maxValueBytes = prev }()
+	maxValueBytes = 4
+
+	if shown, cut := cutValue("abcd"); shown != "abcd" || cut != nil {
+		t.Errorf("expected a value at the limit to be whole, got %q %+v", shown, cut)
+	}
+	// "é" is two bytes, so cutting at 4 would split it
+	shown, cut := cutValue("abcé!")
+	if shown != "abc" || cut == nil || cut.Shown != 3 || cut.Size != 6 {
+		t.Errorf("expected a cut on a character boundary, got %q %+v", shown, cut)
+	}
+
+	maxValueBytes = 0
+	if _, cut := cutValue(strings.Repeat("x", 1<<21)); cut != nil {
+		t.Errorf("expected no cut when unlimited")
+	}
+}
+
+func TestMeasureValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  valueStats
+	}{
+		{"", valueStats{}},
+		{"abc", valueStats{Bytes: 3, Chars: 3, Lines: 1}},
+		{"héllo\nworld\n", valueStats{Bytes: 13, Chars: 12, Lines: 2}},
+		{"a\n\nb", valueStats{Bytes: 4, Chars: 4, Lines: 3}},
+		{"\xff\xfe", valueStats{Bytes: 2, Chars: 2, Lines: 1}},
+	}
+	for _, tt := range tests {
+		if got := measureValue(tt.value); got != tt.want {
+			t.Errorf("measureValue(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestValueStreamHandler(t *testing.T) {
+	mr := useMiniredis(t)
+	value := strings.Repeat("0123456789", valueStreamChunk/5)
+	mr.RPush("big", "small", value)
+
+	rr := httptest.NewRecorder()
+	valueStreamHandler(rr, httptest.NewRequest(http.MethodGet, "/api/value-stream?key=big&index=1", nil))
+	if rr.Code != http.StatusOK || rr.Body.String() != value {
+		t.Fatalf("expected the whole value, got %d with %d bytes", rr.Code, rr.Body.Len())
+	}
+	if got := rr.Header().Get("X-Value-Length"); got != strconv.Itoa(len(value)) {
+		t.Errorf("expected X-Value-Length %d, got %q", len(value), got)
+	}
+
+	rr = httptest.NewRecorder()
+	valueStreamHandler(rr, httptest.NewRequest(http.MethodGet, "/api/value-stream?key=big&index=-1&offset=3&length=5", nil))
+	if rr.Body.String() != "34567" || rr.Header().Get("Content-Length") != "5" {
+		t.Errorf("expected 5 bytes from offset 3, got %q", rr.Body.String())
+	}
+
+	tests := []struct {
+		query  string
+		status int
+		code   string
+	}{
+		{"key=big&index=0&offset=-1", http.StatusBadRequest, apiErrInvalidParameter},
+		{"key=big&index=0&length=x", http.StatusBadRequest, apiErrInvalidParameter},
+		{"key=big&index=0&offset=6", http.StatusBadRequest, apiErrInvalidParameter},
+		{"key=big&index=5", http.StatusNotFound, apiErrOutOfBounds},
+		{"key=missing&index=0", http.StatusNotFound, apiErrNotFound},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		valueStreamHandler(rr, httptest.NewRequest(http.MethodGet, "/api/value-stream?"+tt.query, nil))
+		if rr.Code != tt.status || !strings.Contains(rr.Body.String(), tt.code) {
+			t.Errorf("%q: expected %d %s, got %d: %s", tt.query, tt.status, tt.code, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestLindexHandler_CutsLargeValues(t *testing.T) {
+	mr := useMiniredis(t)
+	prev := maxValueBytes
+	defer func() { maxValueBytes = prev }()
+	maxValueBytes = 10
+	mr.RPush("big", `{"payload":"`+strings.Repeat("x", 40)+`"}`, `{"a":1}`)
+
+	rr := httptest.NewRecorder()
+	lindexHandler(rr, httptest.NewRequest(http.MethodGet, "/lindex?key=big&index=0", nil))
+	body := rr.Body.String()
+	if !strings.Contains(body, `<pre id="valueDisplay">{&#34;payload&#34;</pre>`) {
+		t.Errorf("expected the oversized element cut short and not pretty-printed, got: %s", body)
+	}
+	if !strings.Contains(body, `const valueCuts = [{"shown":10,"size":54},null];`) {
+		t.Errorf("expected the cuts embedded for the page, got: %s", body)
+	}
+	if !strings.Contains(body, `const valueStats = [{"bytes":54,"chars":54,"lines":1},{"bytes":7,"chars":7,"lines":1}];`) || !strings.Contains(body, `<span id="sizeText">`) {
+		t.Errorf("expected each element's size embedded for the metadata, got: %s", body)
+	}
+
+	rr = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/lindex?key=big&index=0", nil)
+	req.Header.Set("Accept", "application/json")
+	lindexHandler(rr, req)
+	if !strings.Contains(rr.Body.String(), strings.Repeat("x", 40)) {
+		t.Errorf("expected JSON responses to carry the whole value, got: %s", rr.Body.String())
+	}
+}